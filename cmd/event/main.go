@@ -21,6 +21,7 @@ import (
 	"github.com/rx3lixir/event-service/pkg/health"
 	"github.com/rx3lixir/event-service/pkg/logger"
 	"github.com/rx3lixir/event-service/pkg/metrics"
+	"github.com/rx3lixir/event-service/pkg/scheduler"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -120,10 +121,18 @@ func main() {
 	// Создаем менеджера консистентности
 	consistencyManager := consistency.New(storer, osService, log)
 
-	// Создаем gRPC сервер с interceptors для метрик
+	// Создаем gRPC сервер с interceptors для метрик, прав администратора и автора изменений
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(metrics.UnaryServerInterceptor("event-service")),
-		grpc.StreamInterceptor(metrics.StreamServerInterceptor("event-service")),
+		grpc.ChainUnaryInterceptor(
+			metrics.UnaryServerInterceptor("event-service"),
+			server.AuthUnaryInterceptor(c.Server.AdminToken),
+			server.ActorUnaryInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			metrics.StreamServerInterceptor("event-service"),
+			server.AuthStreamInterceptor(c.Server.AdminToken),
+			server.ActorStreamInterceptor(),
+		),
 	)
 
 	// Создаем gRPC сервер
@@ -160,6 +169,14 @@ func main() {
 	// Запускаем фоновые задачи для обновления метрик
 	startMetricsCollectors(ctx, storer, osService, log)
 
+	// Запускаем планировщик публикаций
+	scheduler.NewPublisher(storer, osService, log, c.Jobs.PublishInterval).Start(ctx)
+	log.Info("Publication scheduler started", "interval", c.Jobs.PublishInterval)
+
+	// Запускаем очистку удаленных событий и категорий
+	scheduler.NewPurger(storer, log, c.Jobs.PurgeInterval, c.Jobs.PurgeRetention).Start(ctx)
+	log.Info("Purge job started", "interval", c.Jobs.PurgeInterval, "retention", c.Jobs.PurgeRetention)
+
 	// Запускаем серверы
 	errCh := make(chan error, 3)
 
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ActorMetadataKey ключ метаданных запроса с автором изменений.
+// Автор только подписывает ревизии и не дает прав доступа
+const ActorMetadataKey = "x-actor"
+
+// maxActorLength максимальная длина автора (соответствует event_revisions.actor)
+const maxActorLength = 255
+
+// ActorUnaryInterceptor переносит автора изменений из метаданных запроса в контекст,
+// откуда его читает запись ревизий
+func ActorUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(contextWithActor(ctx), req)
+	}
+}
+
+// ActorStreamInterceptor переносит автора из метаданных в контекст потоковых вызовов
+func ActorStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: contextWithActor(ss.Context())})
+	}
+}
+
+// contextServerStream поток с контекстом, дополненным интерсептором
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// contextWithActor добавляет в контекст автора из метаданных запроса, если он передан
+func contextWithActor(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md.Get(ActorMetadataKey)
+	if len(values) == 0 {
+		return ctx
+	}
+
+	actor := strings.TrimSpace(values[0])
+	if len([]rune(actor)) > maxActorLength {
+		actor = string([]rune(actor)[:maxActorLength])
+	}
+	if actor == "" {
+		return ctx
+	}
+	return db.WithActor(ctx, actor)
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthorizationMetadataKey ключ метаданных с токеном администратора в виде "Bearer <token>"
+const AuthorizationMetadataKey = "authorization"
+
+// bearerPrefix схема токена в метаданных authorization
+const bearerPrefix = "Bearer "
+
+// adminContextKey ключ контекста, отмечающий запрос администратора
+type adminContextKey struct{}
+
+// AuthUnaryInterceptor проверяет токен администратора из метаданных и отмечает запрос
+// администратора в контексте. Запрос без токена выполняется как анонимный, с неверным
+// токеном отклоняется. Пустой adminToken отключает административный доступ.
+func AuthUnaryInterceptor(adminToken string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := contextWithAdmin(ctx, adminToken)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor проверяет токен администратора для потоковых вызовов
+func AuthStreamInterceptor(adminToken string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := contextWithAdmin(ss.Context(), adminToken)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextWithAdmin отмечает в контексте запрос с верным токеном администратора
+func contextWithAdmin(ctx context.Context, adminToken string) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+	values := md.Get(AuthorizationMetadataKey)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+
+	token, ok := strings.CutPrefix(values[0], bearerPrefix)
+	if !ok || adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "invalid admin token")
+	}
+	return context.WithValue(ctx, adminContextKey{}, true), nil
+}
+
+// isPrivileged сообщает, выполняется ли запрос администратором.
+// Администраторам доступны события во всех статусах
+func isPrivileged(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminContextKey{}).(bool)
+	return admin
+}
+
+// authorizeIncludeUnpublished разрешает include_unpublished только администраторам
+func authorizeIncludeUnpublished(ctx context.Context, includeUnpublished bool) error {
+	if includeUnpublished && !isPrivileged(ctx) {
+		return status.Error(codes.PermissionDenied, "include_unpublished requires admin authorization")
+	}
+	return nil
+}
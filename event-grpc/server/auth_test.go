@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestContextWithAdmin(t *testing.T) {
+	tests := []struct {
+		name       string
+		adminToken string
+		md         metadata.MD
+		wantAdmin  bool
+		wantCode   codes.Code
+	}{
+		{"no metadata", "secret", nil, false, codes.OK},
+		{"actor is not a credential", "secret", metadata.Pairs(ActorMetadataKey, "editor"), false, codes.OK},
+		{"valid token", "secret", metadata.Pairs(AuthorizationMetadataKey, "Bearer secret"), true, codes.OK},
+		{"wrong token", "secret", metadata.Pairs(AuthorizationMetadataKey, "Bearer guess"), false, codes.Unauthenticated},
+		{"missing scheme", "secret", metadata.Pairs(AuthorizationMetadataKey, "secret"), false, codes.Unauthenticated},
+		{"admin access disabled", "", metadata.Pairs(AuthorizationMetadataKey, "Bearer "), false, codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			ctx, err := contextWithAdmin(ctx, tt.adminToken)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("contextWithAdmin() code = %v, want %v", got, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if got := isPrivileged(ctx); got != tt.wantAdmin {
+				t.Errorf("isPrivileged() = %v, want %v", got, tt.wantAdmin)
+			}
+			if err := authorizeIncludeUnpublished(ctx, true); (err == nil) != tt.wantAdmin {
+				t.Errorf("authorizeIncludeUnpublished() error = %v, admin %v", err, tt.wantAdmin)
+			}
+		})
+	}
+}
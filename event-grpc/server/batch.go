@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBatchItems максимальное количество элементов в пакетном запросе
+const maxBatchItems = 100
+
+// BatchCreateEvents создает события пакетом и индексирует созданные одним bulk-запросом.
+func (s *Server) BatchCreateEvents(ctx context.Context, req *eventPb.BatchCreateEventsReq) (*eventPb.BatchEventsRes, error) {
+	s.log.Info("starting batch create events",
+		"method", "BatchCreateEvents",
+		"count", len(req.GetEvents()),
+		"best_effort", req.GetBestEffort(),
+	)
+
+	if err := validateBatchSize(len(req.GetEvents())); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	batch := newEventBatch(len(req.GetEvents()), req.GetBestEffort())
+	events := make([]*db.Event, 0, len(req.GetEvents()))
+	for i, item := range req.GetEvents() {
+		event, err := s.prepareCreateEvent(ctx, "BatchCreateEvents", item)
+		if err != nil {
+			batch.fail(i, err)
+			continue
+		}
+		batch.accept(i, 0)
+		events = append(events, event)
+	}
+
+	if batch.rejected() {
+		return batch.response(), nil
+	}
+
+	created, errs := s.storer.BatchCreateEvents(ctx, events, !req.GetBestEffort())
+	s.completeEventBatch(ctx, "BatchCreateEvents", batch, created, errs)
+
+	return batch.response(), nil
+}
+
+// BatchUpdateEvents обновляет события пакетом. В OpenSearch обновленные события
+// переиндексируются целиком одним bulk-запросом.
+func (s *Server) BatchUpdateEvents(ctx context.Context, req *eventPb.BatchUpdateEventsReq) (*eventPb.BatchEventsRes, error) {
+	s.log.Info("starting batch update events",
+		"method", "BatchUpdateEvents",
+		"count", len(req.GetEvents()),
+		"best_effort", req.GetBestEffort(),
+	)
+
+	if err := validateBatchSize(len(req.GetEvents())); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	batch := newEventBatch(len(req.GetEvents()), req.GetBestEffort())
+	events := make([]*db.Event, 0, len(req.GetEvents()))
+	fields := make([][]string, 0, len(req.GetEvents()))
+	for i, item := range req.GetEvents() {
+		event, itemFields, err := s.prepareEventUpdate(ctx, "BatchUpdateEvents", item)
+		if err != nil {
+			batch.fail(i, err)
+			batch.results[i].Id = item.GetId()
+			continue
+		}
+		batch.accept(i, item.GetId())
+		events = append(events, event)
+		fields = append(fields, itemFields)
+	}
+
+	if batch.rejected() {
+		return batch.response(), nil
+	}
+
+	updated, errs := s.storer.BatchUpdateEvents(ctx, events, fields, !req.GetBestEffort())
+	s.completeEventBatch(ctx, "BatchUpdateEvents", batch, updated, errs)
+
+	return batch.response(), nil
+}
+
+// BatchDeleteEvents мягко удаляет события пакетом и удаляет их документы одним bulk-запросом.
+func (s *Server) BatchDeleteEvents(ctx context.Context, req *eventPb.BatchDeleteEventsReq) (*eventPb.BatchEventsRes, error) {
+	s.log.Info("starting batch delete events",
+		"method", "BatchDeleteEvents",
+		"ids", req.GetIds(),
+		"best_effort", req.GetBestEffort(),
+	)
+
+	if err := validateBatchSize(len(req.GetIds())); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	batch := newEventBatch(len(req.GetIds()), req.GetBestEffort())
+	ids := make([]int64, 0, len(req.GetIds()))
+	for i, id := range req.GetIds() {
+		if id <= 0 {
+			batch.fail(i, status.Error(codes.InvalidArgument, "invalid event ID"))
+			batch.results[i].Id = id
+			continue
+		}
+		batch.accept(i, id)
+		ids = append(ids, id)
+	}
+
+	if batch.rejected() {
+		return batch.response(), nil
+	}
+
+	_, errs := s.storer.BatchDeleteEvents(ctx, ids, !req.GetBestEffort())
+
+	deletedIDs := make([]int64, 0, len(ids))
+	for j, i := range batch.pending {
+		if errs[j] != nil {
+			batch.fail(i, batchItemError(errs[j]))
+			continue
+		}
+		deletedIDs = append(deletedIDs, ids[j])
+	}
+
+	if err := s.esService.BulkDeleteEvents(ctx, deletedIDs); err != nil {
+		s.log.Error("failed to bulk delete events from OpenSearch",
+			"method", "BatchDeleteEvents",
+			"events_count", len(deletedIDs),
+			"error", err,
+		)
+		// Не возвращаем ошибку, так как события уже удалены из PostgreSQL
+	}
+
+	res := batch.response()
+	s.log.Info("batch delete events completed",
+		"method", "BatchDeleteEvents",
+		"succeeded", res.GetSucceeded(),
+		"failed", res.GetFailed(),
+	)
+
+	return res, nil
+}
+
+// completeEventBatch переносит результаты хранилища в пакет, синхронизирует статус
+// распроданных событий и индексирует сохраненные события одним bulk-запросом.
+func (s *Server) completeEventBatch(ctx context.Context, method string, batch *eventBatch, events []*db.Event, errs []error) {
+	saved := make([]*db.Event, 0, len(events))
+	for j, i := range batch.pending {
+		if errs[j] != nil {
+			batch.fail(i, batchItemError(errs[j]))
+			continue
+		}
+
+		s.syncSoldOutStatus(ctx, method, events[j])
+		saved = append(saved, events[j])
+
+		batch.results[i].Id = events[j].Id
+		batch.results[i].Event = DBEventToProtoEventRes(events[j])
+	}
+
+	if err := s.esService.BulkIndexEvents(ctx, saved); err != nil {
+		s.log.Error("failed to bulk index events in OpenSearch",
+			"method", method,
+			"events_count", len(saved),
+			"error", err,
+		)
+		// Не возвращаем ошибку, так как события уже сохранены в PostgreSQL
+	}
+
+	res := batch.response()
+	s.log.Info("batch completed",
+		"method", method,
+		"succeeded", res.GetSucceeded(),
+		"failed", res.GetFailed(),
+	)
+}
+
+// eventBatch собирает результаты элементов пакета в порядке запроса
+type eventBatch struct {
+	results    []*eventPb.BatchItemResult
+	pending    []int // Позиции элементов, переданных в хранилище, в порядке передачи
+	bestEffort bool
+}
+
+func newEventBatch(size int, bestEffort bool) *eventBatch {
+	results := make([]*eventPb.BatchItemResult, size)
+	for i := range results {
+		results[i] = &eventPb.BatchItemResult{Index: int32(i)}
+	}
+
+	return &eventBatch{
+		results:    results,
+		pending:    make([]int, 0, size),
+		bestEffort: bestEffort,
+	}
+}
+
+// accept отмечает элемент, прошедший проверку
+func (b *eventBatch) accept(i int, id int64) {
+	b.pending = append(b.pending, i)
+	b.results[i].Id = id
+}
+
+// fail записывает ошибку элемента (gRPC статус)
+func (b *eventBatch) fail(i int, err error) {
+	st := status.Convert(err)
+	b.results[i].Code = int32(st.Code())
+	b.results[i].Error = st.Message()
+}
+
+// rejected сообщает, что транзакционный пакет не прошел проверку и не должен применяться.
+// Прошедшие проверку элементы при этом отмечаются отмененными.
+func (b *eventBatch) rejected() bool {
+	if b.bestEffort || len(b.pending) == len(b.results) {
+		return false
+	}
+
+	for _, i := range b.pending {
+		b.fail(i, status.Error(codes.Aborted, "batch rejected: another item is invalid"))
+	}
+	return true
+}
+
+// response формирует ответ с итогами пакета
+func (b *eventBatch) response() *eventPb.BatchEventsRes {
+	res := &eventPb.BatchEventsRes{Results: b.results}
+	for _, result := range b.results {
+		if codes.Code(result.GetCode()) == codes.OK {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+	}
+	return res
+}
+
+// batchItemError преобразует ошибку хранилища для элемента пакета в gRPC ошибку.
+func batchItemError(err error) error {
+	if errors.Is(err, db.ErrBatchAborted) {
+		return status.Error(codes.Aborted, "batch rolled back: another item failed")
+	}
+	return wrapError(err)
+}
+
+// validateBatchSize проверяет количество элементов пакета.
+func validateBatchSize(size int) error {
+	if size == 0 {
+		return errors.New("batch is empty")
+	}
+	if size > maxBatchItems {
+		return fmt.Errorf("batch exceeds %d items", maxBatchItems)
+	}
+	return nil
+}
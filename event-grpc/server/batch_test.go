@@ -0,0 +1,116 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestEventBatchRejected(t *testing.T) {
+	invalid := status.Error(codes.InvalidArgument, "name is required")
+
+	tests := []struct {
+		name         string
+		bestEffort   bool
+		invalid      []int // Элементы, не прошедшие проверку, остальные приняты
+		wantRejected bool
+		wantCodes    []codes.Code
+	}{
+		{
+			name:      "all items valid",
+			wantCodes: []codes.Code{codes.OK, codes.OK, codes.OK},
+		},
+		{
+			name:         "atomic batch with an invalid item aborts the valid ones",
+			invalid:      []int{1},
+			wantRejected: true,
+			wantCodes:    []codes.Code{codes.Aborted, codes.InvalidArgument, codes.Aborted},
+		},
+		{
+			name:       "best effort batch keeps the valid items",
+			bestEffort: true,
+			invalid:    []int{1},
+			wantCodes:  []codes.Code{codes.OK, codes.InvalidArgument, codes.OK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := newEventBatch(3, tt.bestEffort)
+			for i := range 3 {
+				if slices.Contains(tt.invalid, i) {
+					batch.fail(i, invalid)
+					continue
+				}
+				batch.accept(i, int64(i+10))
+			}
+
+			if got := batch.rejected(); got != tt.wantRejected {
+				t.Errorf("rejected() = %v, want %v", got, tt.wantRejected)
+			}
+
+			res := batch.response()
+			var wantFailed int32
+			for i, want := range tt.wantCodes {
+				result := res.GetResults()[i]
+				if result.GetIndex() != int32(i) {
+					t.Errorf("result %d: index = %d", i, result.GetIndex())
+				}
+				if got := codes.Code(result.GetCode()); got != want {
+					t.Errorf("result %d: code = %v, want %v", i, got, want)
+				}
+				if want != codes.OK {
+					wantFailed++
+				}
+			}
+			if res.GetFailed() != wantFailed || res.GetSucceeded() != int32(len(tt.wantCodes))-wantFailed {
+				t.Errorf("succeeded/failed = %d/%d, want %d/%d",
+					res.GetSucceeded(), res.GetFailed(), int32(len(tt.wantCodes))-wantFailed, wantFailed)
+			}
+		})
+	}
+}
+
+func TestBatchItemError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"rolled back by another item", fmt.Errorf("item 2: %w", db.ErrBatchAborted), codes.Aborted},
+		{"not found", fmt.Errorf("event 5: %w", pgx.ErrNoRows), codes.NotFound},
+		{"version conflict", &db.VersionConflictError{Entity: "event", ID: 5, Current: 3}, codes.Aborted},
+		{"unexpected", fmt.Errorf("connection reset"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(batchItemError(tt.err)); got != tt.want {
+				t.Errorf("batchItemError() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBatchSize(t *testing.T) {
+	tests := []struct {
+		size    int
+		wantErr bool
+	}{
+		{0, true},
+		{1, false},
+		{maxBatchItems, false},
+		{maxBatchItems + 1, true},
+	}
+
+	for _, tt := range tests {
+		if err := validateBatchSize(tt.size); (err != nil) != tt.wantErr {
+			t.Errorf("validateBatchSize(%d) error = %v, wantErr %v", tt.size, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
+	"github.com/rx3lixir/event-service/internal/opensearch/search"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Ограничения календаря
+const (
+	maxCalendarDays           = 62 // Сетка месяца с соседними неделями с запасом
+	defaultCalendarHighlights = 3
+	maxCalendarHighlights     = 10
+	maxCalendarSeries         = 500 // Серий, разворачиваемых во вхождения за запрос
+)
+
+// GetEventCalendar возвращает события диапазона по дням: количество, категории и подборку дня.
+// Разовые события считаются агрегацией OpenSearch, вхождения серий добавляются после разворачивания.
+func (s *Server) GetEventCalendar(ctx context.Context, req *eventPb.GetEventCalendarReq) (*eventPb.GetEventCalendarRes, error) {
+	s.log.Info("starting get event calendar",
+		"method", "GetEventCalendar",
+		"date_from", req.GetDateFrom(),
+		"date_to", req.GetDateTo(),
+		"timezone", req.GetTimezone(),
+		"search_text", req.GetSearchText(),
+		"category_ids", req.GetCategoryIDs(),
+		"source", req.GetSource(),
+		"tags_any", req.GetTagsAny(),
+		"tags_all", req.GetTagsAll(),
+		"organizer_ids", req.GetOrganizerIds(),
+		"venue_ids", req.GetVenueIds(),
+		"cities", req.GetCities(),
+		"has_near", req.GetNear() != nil,
+		"has_bbox", req.GetBbox() != nil,
+		"highlights", req.GetHighlights(),
+		"highlight_sort", len(req.GetHighlightSort()),
+		"locale", req.GetLocale(),
+	)
+
+	if err := validateRequestLocale(req.GetLocale()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateOrganizerIDs(req.GetOrganizerIds()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := authorizeIncludeUnpublished(ctx, req.GetIncludeUnpublished()); err != nil {
+		return nil, err
+	}
+
+	filter, calendarReq, err := ProtoToCalendarRequest(req)
+	if err != nil {
+		s.log.Error("invalid calendar parameters",
+			"method", "GetEventCalendar",
+			"error", err,
+		)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.expandSearchCategories(ctx, "GetEventCalendar", filter); err != nil {
+		return nil, err
+	}
+
+	result, err := s.esService.EventCalendar(ctx, filter, calendarReq)
+	if err != nil {
+		s.log.Error("failed to build event calendar in OpenSearch",
+			"method", "GetEventCalendar",
+			"error", err,
+			"filter", filter,
+		)
+		return nil, status.Error(codes.Internal, "calendar search failed")
+	}
+	if result.SeriesTotal > int64(len(result.Series)) {
+		s.log.Warn("calendar recurring series truncated",
+			"method", "GetEventCalendar",
+			"series_total", result.SeriesTotal,
+			"series_used", len(result.Series),
+		)
+	}
+
+	days, err := s.addCalendarOccurrences(ctx, filter, calendarReq, result)
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("event calendar built",
+		"method", "GetEventCalendar",
+		"days", len(days),
+		"series", len(result.Series),
+		"search_time", result.SearchTime,
+	)
+
+	response := &eventPb.GetEventCalendarRes{Days: CalendarDaysToProto(days)}
+	for _, day := range response.GetDays() {
+		for _, event := range day.GetHighlights() {
+			LocalizeEventRes(event, req.GetLocale())
+		}
+	}
+
+	return response, nil
+}
+
+// addCalendarOccurrences разворачивает серии календаря во вхождения диапазона и добавляет их
+// в дни начала: в счетчики, категории и кандидаты подборки. Возвращает дни по возрастанию.
+func (s *Server) addCalendarOccurrences(ctx context.Context, filter *search.Filter, req *search.CalendarRequest, result *models.CalendarResult) ([]*models.CalendarDay, error) {
+	from, to := filter.DateWindow()
+	occurrences, err := s.expandRecurringEvents(ctx, models.ToDBEvents(result.Series), from, to)
+	if err != nil {
+		s.log.Error("failed to expand recurring events",
+			"method", "GetEventCalendar",
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	categoryNames := make(map[int64]string, len(result.Series))
+	for _, series := range result.Series {
+		categoryNames[series.CategoryID] = series.CategoryName
+	}
+
+	days := make(map[string]*models.CalendarDay, len(result.Days))
+	highlights := make(map[string][]*db.Event, len(result.Days))
+	for _, day := range result.Days {
+		days[day.Date] = day
+		highlights[day.Date] = models.ToDBEvents(day.Highlights)
+	}
+
+	loc := db.LoadLocation(req.Timezone)
+	for _, occurrence := range occurrences {
+		// Серия с некорректным правилом остается неразвернутой и может начинаться вне диапазона
+		if occurrence.StartsAt == nil || occurrence.StartsAt.Before(from) || !occurrence.StartsAt.Before(to) {
+			continue
+		}
+
+		date := occurrence.StartsAt.In(loc).Format(db.LegacyDateLayout)
+		day, ok := days[date]
+		if !ok {
+			day = &models.CalendarDay{Date: date}
+			days[date] = day
+		}
+		day.Count++
+		if occurrence.CategoryID > 0 {
+			day.Categories = addCalendarCategory(day.Categories, occurrence.CategoryID, categoryNames[occurrence.CategoryID])
+		}
+		if req.Highlights > 0 {
+			highlights[date] = append(highlights[date], occurrence)
+		}
+	}
+
+	ranking := req.GetRanking()
+	result.Days = result.Days[:0]
+	for date, day := range days {
+		slices.SortFunc(day.Categories, func(a, b db.FacetBucket) int {
+			if a.Count != b.Count {
+				return int(b.Count - a.Count)
+			}
+			return strings.Compare(a.Key, b.Key)
+		})
+		if len(day.Categories) > req.Categories {
+			day.Categories = day.Categories[:req.Categories]
+		}
+
+		candidates := highlights[date]
+		slices.SortFunc(candidates, func(a, b *db.Event) int {
+			return db.CompareEvents(a, b, ranking)
+		})
+		if len(candidates) > req.Highlights {
+			candidates = candidates[:req.Highlights]
+		}
+		day.Highlights = models.FromDBEvents(candidates)
+
+		result.Days = append(result.Days, day)
+	}
+	slices.SortFunc(result.Days, func(a, b *models.CalendarDay) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+
+	return result.Days, nil
+}
+
+// addCalendarCategory увеличивает счетчик категории дня, добавляя ее при отсутствии
+func addCalendarCategory(categories []db.FacetBucket, categoryID int64, name string) []db.FacetBucket {
+	key := strconv.FormatInt(categoryID, 10)
+	for i := range categories {
+		if categories[i].Key == key {
+			categories[i].Count++
+			return categories
+		}
+	}
+	return append(categories, db.FacetBucket{Key: key, Label: name, Count: 1})
+}
+
+// ProtoToCalendarRequest разбирает запрос календаря в фильтр OpenSearch и параметры календаря.
+// Фильтры разбираются так же, как в ListEvents.
+func ProtoToCalendarRequest(req *eventPb.GetEventCalendarReq) (*search.Filter, *search.CalendarRequest, error) {
+	if req.GetDateFrom() == "" || req.GetDateTo() == "" {
+		return nil, nil, fmt.Errorf("date_from and date_to are required")
+	}
+
+	dateFrom, dateTo := req.GetDateFrom(), req.GetDateTo()
+	filter, err := ProtoToOpenSearchFilter(&eventPb.ListEventsReq{
+		CategoryIDs:        req.GetCategoryIDs(),
+		MinPrice:           req.MinPrice,
+		MaxPrice:           req.MaxPrice,
+		DateFrom:           &dateFrom,
+		DateTo:             &dateTo,
+		Source:             req.Source,
+		SearchText:         req.SearchText,
+		Timezone:           req.Timezone,
+		Near:               req.GetNear(),
+		Bbox:               req.GetBbox(),
+		TagsAny:            req.GetTagsAny(),
+		TagsAll:            req.GetTagsAll(),
+		IsFree:             req.IsFree,
+		IncludeUnpublished: req.IncludeUnpublished,
+		Locale:             req.Locale,
+		OrganizerIds:       req.GetOrganizerIds(),
+		VenueIds:           req.GetVenueIds(),
+		Cities:             req.GetCities(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	from, to := filter.DateWindow()
+	if !from.Before(to) {
+		return nil, nil, fmt.Errorf("date_to cannot be before date_from")
+	}
+	if from.AddDate(0, 0, maxCalendarDays).Before(to) {
+		return nil, nil, fmt.Errorf("calendar range cannot exceed %d days", maxCalendarDays)
+	}
+
+	highlights := defaultCalendarHighlights
+	if req.Highlights != nil {
+		if req.GetHighlights() < 0 || req.GetHighlights() > maxCalendarHighlights {
+			return nil, nil, fmt.Errorf("highlights must be in [0, %d], got: %d", maxCalendarHighlights, req.GetHighlights())
+		}
+		highlights = int(req.GetHighlights())
+	}
+
+	ranking := make([]db.EventSort, 0, len(req.GetHighlightSort()))
+	seen := make(map[string]bool, len(req.GetHighlightSort()))
+	for _, spec := range req.GetHighlightSort() {
+		sort, err := db.ParseEventSort(spec.GetField(), spec.GetDirection())
+		if err != nil {
+			return nil, nil, err
+		}
+		if sort.Field == db.SortFieldRelevance || sort.Field == db.SortFieldDistance {
+			return nil, nil, fmt.Errorf("highlight_sort by %s is not supported", sort.Field)
+		}
+		if seen[sort.Field] {
+			return nil, nil, fmt.Errorf("duplicate highlight_sort field %q", sort.Field)
+		}
+		seen[sort.Field] = true
+		ranking = append(ranking, sort)
+	}
+
+	timezone := db.DefaultTimezone
+	if req.Timezone != nil {
+		timezone = req.GetTimezone()
+	}
+
+	return filter, &search.CalendarRequest{
+		Timezone:   timezone,
+		Highlights: highlights,
+		Ranking:    ranking,
+		Categories: defaultFacetSize,
+		MaxSeries:  maxCalendarSeries,
+	}, nil
+}
+
+// CalendarDaysToProto конвертирует дни календаря в proto
+func CalendarDaysToProto(days []*models.CalendarDay) []*eventPb.CalendarDay {
+	result := make([]*eventPb.CalendarDay, 0, len(days))
+	for _, day := range days {
+		result = append(result, &eventPb.CalendarDay{
+			Date:       day.Date,
+			Count:      day.Count,
+			Categories: facetBucketsToProto(day.Categories),
+			Highlights: OpenSearchEventsToProtoEventsList(day.Highlights),
+		})
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultDeletedEventsLimit = 20
+	maxDeletedEventsLimit     = 100
+)
+
+// RestoreEvent восстанавливает мягко удаленное событие и возвращает его в поисковый индекс.
+func (s *Server) RestoreEvent(ctx context.Context, req *eventPb.RestoreEventReq) (*eventPb.EventRes, error) {
+	s.log.Info("starting restore event",
+		"method", "RestoreEvent",
+		"event_id", req.GetId(),
+	)
+
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event ID")
+	}
+
+	deletedEvent, err := s.storer.GetDeletedEventByID(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get deleted event",
+			"method", "RestoreEvent",
+			"event_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	// Событие нельзя вернуть в удаленную категорию: сначала нужно восстановить или сменить ее
+	if deletedEvent.CategoryID != 0 {
+		if _, err := s.storer.GetCategoryByID(ctx, int(deletedEvent.CategoryID)); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, status.Errorf(codes.FailedPrecondition, "category %d of the event is deleted", deletedEvent.CategoryID)
+			}
+			s.log.Error("failed to check event category",
+				"method", "RestoreEvent",
+				"event_id", req.GetId(),
+				"category_id", deletedEvent.CategoryID,
+				"error", err,
+			)
+			return nil, wrapError(err)
+		}
+	}
+
+	event, err := s.storer.RestoreEvent(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to restore event",
+			"method", "RestoreEvent",
+			"event_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	if err := s.esService.IndexEvent(ctx, event); err != nil {
+		s.log.Error("failed to index restored event in OpenSearch",
+			"method", "RestoreEvent",
+			"event_id", event.Id,
+			"error", err,
+		)
+		// Не возвращаем ошибку, так как событие уже восстановлено в PostgreSQL
+	}
+
+	s.log.Info("event restored successfully",
+		"method", "RestoreEvent",
+		"event_id", event.Id,
+	)
+
+	return DBEventToProtoEventRes(event), nil
+}
+
+// ListDeletedEvents возвращает мягко удаленные события, начиная с последних удаленных.
+func (s *Server) ListDeletedEvents(ctx context.Context, req *eventPb.ListDeletedEventsReq) (*eventPb.ListEventsRes, error) {
+	s.log.Info("starting list deleted events",
+		"method", "ListDeletedEvents",
+		"limit", req.GetLimit(),
+		"offset", req.GetOffset(),
+	)
+
+	limit := int(req.GetLimit())
+	offset := int(req.GetOffset())
+	if limit < 0 || limit > maxDeletedEventsLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxDeletedEventsLimit)
+	}
+	if offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset cannot be negative")
+	}
+	if limit == 0 {
+		limit = defaultDeletedEventsLimit
+	}
+
+	events, total, err := s.storer.GetDeletedEvents(ctx, limit, offset)
+	if err != nil {
+		s.log.Error("failed to list deleted events",
+			"method", "ListDeletedEvents",
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	response := &eventPb.ListEventsRes{
+		Events:     make([]*eventPb.EventRes, 0, len(events)),
+		Pagination: CreatePaginationMeta(total, limit, offset),
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, DBEventToProtoEventRes(event))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/internal/dedup"
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
+	"github.com/rx3lixir/event-service/internal/opensearch/search"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultDuplicatesLimit = 20
+	maxDuplicatesLimit     = 100
+	maxMergeDuplicates     = 50
+
+	// Сколько похожих документов запрашивается у OpenSearch для одного события
+	similarCandidatesSize = 50
+	// Окно и размер выборки для поиска пар без event_id
+	duplicatesScanWindow = 30 * 24 * time.Hour
+	duplicatesScanLimit  = 500
+)
+
+// ListDuplicateCandidates возвращает пары событий, похожих на одно событие из разных источников.
+// Для event_id кандидаты ищутся через more_like_this в OpenSearch (при недоступности -
+// среди событий с близким началом в PostgreSQL), без него - среди событий ближайших 30 дней.
+// Итоговая оценка всегда вычисляется детерминированными правилами dedup.Score.
+func (s *Server) ListDuplicateCandidates(ctx context.Context, req *eventPb.ListDuplicateCandidatesReq) (*eventPb.ListDuplicateCandidatesRes, error) {
+	s.log.Info("starting list duplicate candidates",
+		"method", "ListDuplicateCandidates",
+		"event_id", req.GetEventId(),
+		"min_score", req.GetMinScore(),
+		"limit", req.GetLimit(),
+	)
+
+	minScore := dedup.DefaultMinScore
+	if req.MinScore != nil {
+		minScore = float64(req.GetMinScore())
+		if minScore < 0 || minScore > 1 {
+			return nil, status.Error(codes.InvalidArgument, "min_score must be between 0 and 1")
+		}
+	}
+
+	limit := int(req.GetLimit())
+	if limit < 0 || limit > maxDuplicatesLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxDuplicatesLimit)
+	}
+	if limit == 0 {
+		limit = defaultDuplicatesLimit
+	}
+
+	var candidates []dedup.Candidate
+	if req.EventId != nil {
+		event, err := s.storer.GetEventByID(ctx, req.GetEventId())
+		if err != nil {
+			s.log.Error("failed to get event for duplicate search",
+				"method", "ListDuplicateCandidates",
+				"event_id", req.GetEventId(),
+				"error", err,
+			)
+			return nil, wrapError(err)
+		}
+
+		others, err := s.similarEventCandidates(ctx, event)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		candidates = dedup.FindCandidates(event, others, minScore)
+	} else {
+		now := time.Now()
+		events, err := s.storer.GetEventsStartingBetween(ctx, now, now.Add(duplicatesScanWindow), duplicatesScanLimit)
+		if err != nil {
+			s.log.Error("failed to get events for duplicate scan",
+				"method", "ListDuplicateCandidates",
+				"error", err,
+			)
+			return nil, wrapError(err)
+		}
+		candidates = dedup.FindPairs(events, minScore)
+	}
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	s.log.Info("duplicate candidates found",
+		"method", "ListDuplicateCandidates",
+		"event_id", req.GetEventId(),
+		"count", len(candidates),
+	)
+
+	return &eventPb.ListDuplicateCandidatesRes{
+		Candidates: DuplicateCandidatesToProto(candidates),
+	}, nil
+}
+
+// similarEventCandidates загружает из PostgreSQL события, похожие на event по мнению OpenSearch.
+// Если OpenSearch недоступен, кандидатами считаются события, начинающиеся в пределах dedup.StartWindow.
+func (s *Server) similarEventCandidates(ctx context.Context, event *db.Event) ([]*db.Event, error) {
+	filter := &search.SimilarFilter{EventID: event.Id, Size: similarCandidatesSize}
+	if event.StartsAt != nil {
+		from, to := event.StartsAt.Add(-dedup.StartWindow), event.StartsAt.Add(dedup.StartWindow)
+		filter.StartsFrom, filter.StartsTo = &from, &to
+	}
+
+	result, err := s.esService.FindSimilarEvents(ctx, filter)
+	if err == nil {
+		// Документы индекса могут отставать: сравниваем актуальные данные из PostgreSQL
+		return s.storer.GetEventsByIDs(ctx, documentIDs(result.Events))
+	}
+
+	s.log.Warn("more_like_this search failed, falling back to PostgreSQL",
+		"method", "ListDuplicateCandidates",
+		"event_id", event.Id,
+		"error", err,
+	)
+
+	if event.StartsAt == nil {
+		return nil, nil
+	}
+	return s.storer.GetEventsStartingBetween(ctx, *filter.StartsFrom, *filter.StartsTo, similarCandidatesSize)
+}
+
+// MergeEvents объединяет дубликаты в каноническое событие. Дубликаты удаляются
+// из PostgreSQL (мягко) и из индекса OpenSearch, их ID перенаправляются на каноническое событие.
+func (s *Server) MergeEvents(ctx context.Context, req *eventPb.MergeEventsReq) (*eventPb.MergeEventsRes, error) {
+	s.log.Info("starting merge events",
+		"method", "MergeEvents",
+		"canonical_id", req.GetCanonicalId(),
+		"duplicate_ids", req.GetDuplicateIds(),
+	)
+
+	if err := validateMergeEventsReq(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	canonical, merged, err := s.storer.MergeEvents(ctx, req.GetCanonicalId(), req.GetDuplicateIds())
+	if err != nil {
+		s.log.Error("failed to merge events",
+			"method", "MergeEvents",
+			"canonical_id", req.GetCanonicalId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	mergedIDs := make([]int64, 0, len(merged))
+	for _, event := range merged {
+		mergedIDs = append(mergedIDs, event.Id)
+
+		if err := s.esService.DeleteEvent(ctx, event.Id); err != nil {
+			s.log.Error("failed to delete merged event from OpenSearch",
+				"method", "MergeEvents",
+				"event_id", event.Id,
+				"error", err,
+			)
+			// Не возвращаем ошибку, так как слияние уже сохранено в PostgreSQL
+		}
+	}
+
+	s.log.Info("events merged successfully",
+		"method", "MergeEvents",
+		"canonical_id", canonical.Id,
+		"merged_ids", mergedIDs,
+	)
+
+	return &eventPb.MergeEventsRes{
+		Event:     DBEventToProtoEventRes(canonical),
+		MergedIds: mergedIDs,
+	}, nil
+}
+
+// getEventOrRedirect получает событие по ID, а для ID события, объединенного
+// с другим, - каноническое событие.
+func (s *Server) getEventOrRedirect(ctx context.Context, id int64) (*db.Event, error) {
+	event, err := s.storer.GetEventByID(ctx, id)
+	if err == nil {
+		return event, nil
+	}
+
+	canonicalID, redirectErr := s.storer.ResolveEventRedirect(ctx, id)
+	if redirectErr != nil {
+		return nil, err
+	}
+
+	s.log.Debug("event redirected to canonical",
+		"event_id", id,
+		"canonical_id", canonicalID,
+	)
+
+	return s.storer.GetEventByID(ctx, canonicalID)
+}
+
+// validateMergeEventsReq проверяет ID канонического события и дубликатов.
+func validateMergeEventsReq(req *eventPb.MergeEventsReq) error {
+	if req.GetCanonicalId() <= 0 {
+		return errors.New("invalid canonical event ID")
+	}
+
+	duplicateIDs := req.GetDuplicateIds()
+	if len(duplicateIDs) == 0 {
+		return errors.New("at least one duplicate ID is required")
+	}
+	if len(duplicateIDs) > maxMergeDuplicates {
+		return errors.New("too many duplicate IDs")
+	}
+
+	for _, id := range duplicateIDs {
+		if id <= 0 {
+			return errors.New("invalid duplicate event ID")
+		}
+		if id == req.GetCanonicalId() {
+			return errors.New("event cannot be merged into itself")
+		}
+	}
+
+	return nil
+}
+
+// documentIDs возвращает ID событий из документов OpenSearch
+func documentIDs(docs []*models.EventDocument) []int64 {
+	ids := make([]int64, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	return ids
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+)
+
+// Количество значений в фасетах категорий, источников, городов и площадок
+const (
+	defaultFacetSize = 20
+	maxFacetSize     = 100
+)
+
+// listEventFacets считает фасеты для PostgreSQL ветки ListEvents.
+// OpenSearch считает их агрегациями в самом поисковом запросе.
+func (s *Server) listEventFacets(ctx context.Context, filter *db.EventFilter, req *db.FacetRequest) (*eventPb.Facets, error) {
+	facets, err := s.storer.GetEventFacets(ctx, filter, req)
+	if err != nil {
+		s.log.Error("failed to get event facets",
+			"method", "ListEvents",
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+	return EventFacetsToProto(facets), nil
+}
+
+// ProtoToFacetRequest разбирает запрос фасетов. nil, если фасеты не запрошены.
+func ProtoToFacetRequest(req *eventPb.ListEventsReq) (*db.FacetRequest, error) {
+	facets := req.GetFacets()
+	if facets == nil {
+		return nil, nil
+	}
+
+	size := defaultFacetSize
+	if facets.Size != nil {
+		if facets.GetSize() <= 0 || facets.GetSize() > maxFacetSize {
+			return nil, fmt.Errorf("facets.size must be in [1, %d], got: %d", maxFacetSize, facets.GetSize())
+		}
+		size = int(facets.GetSize())
+	}
+
+	if facets.PriceInterval != nil && facets.GetPriceInterval() <= 0 {
+		return nil, fmt.Errorf("facets.price_interval must be positive, got: %g", facets.GetPriceInterval())
+	}
+
+	timezone := db.DefaultTimezone
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(req.GetTimezone()); err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", req.GetTimezone())
+		}
+		timezone = req.GetTimezone()
+	}
+
+	return &db.FacetRequest{
+		Categories:    facets.GetCategories(),
+		Sources:       facets.GetSources(),
+		Cities:        facets.GetCities(),
+		Venues:        facets.GetVenues(),
+		Dates:         facets.GetDates(),
+		PriceInterval: facets.GetPriceInterval(),
+		Size:          size,
+		Timezone:      timezone,
+	}, nil
+}
+
+// EventFacetsToProto конвертирует счетчики фасетов в proto
+func EventFacetsToProto(facets *db.EventFacets) *eventPb.Facets {
+	if facets == nil {
+		return nil
+	}
+
+	return &eventPb.Facets{
+		Categories: facetBucketsToProto(facets.Categories),
+		Sources:    facetBucketsToProto(facets.Sources),
+		Cities:     facetBucketsToProto(facets.Cities),
+		Venues:     facetBucketsToProto(facets.Venues),
+		Price:      facetBucketsToProto(facets.Price),
+		Dates:      facetBucketsToProto(facets.Dates),
+	}
+}
+
+// facetBucketsToProto конвертирует значения одного фасета в proto
+func facetBucketsToProto(buckets []db.FacetBucket) []*eventPb.FacetBucket {
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	result := make([]*eventPb.FacetBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		result = append(result, &eventPb.FacetBucket{
+			Key:   bucket.Key,
+			Label: bucket.Label,
+			Count: bucket.Count,
+		})
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"unicode/utf8"
+
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxEventImages максимальное количество изображений у события
+const maxEventImages = 50
+
+// AddEventImage добавляет изображение в конец списка изображений события.
+func (s *Server) AddEventImage(ctx context.Context, req *eventPb.AddEventImageReq) (*eventPb.EventRes, error) {
+	s.log.Info("starting add event image",
+		"method", "AddEventImage",
+		"event_id", req.GetEventId(),
+		"role", req.GetRole(),
+	)
+
+	if req.GetEventId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event ID")
+	}
+
+	image := ProtoToEventImage(req)
+	if err := validateEventImage(image); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	current, err := s.storer.GetEventByID(ctx, req.GetEventId())
+	if err != nil {
+		s.log.Error("failed to get event for image",
+			"method", "AddEventImage",
+			"event_id", req.GetEventId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+	if len(current.Images) >= maxEventImages {
+		return nil, status.Errorf(codes.FailedPrecondition, "event already has maximum %d images", maxEventImages)
+	}
+
+	event, err := s.storer.AddEventImage(ctx, req.GetEventId(), image)
+	if err != nil {
+		s.log.Error("failed to add event image",
+			"method", "AddEventImage",
+			"event_id", req.GetEventId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	s.indexEventImage(ctx, "AddEventImage", event)
+
+	s.log.Info("event image added successfully",
+		"method", "AddEventImage",
+		"event_id", event.Id,
+		"image_id", image.Id,
+	)
+
+	return DBEventToProtoEventRes(event), nil
+}
+
+// RemoveEventImage удаляет изображение события.
+func (s *Server) RemoveEventImage(ctx context.Context, req *eventPb.RemoveEventImageReq) (*eventPb.EventRes, error) {
+	s.log.Info("starting remove event image",
+		"method", "RemoveEventImage",
+		"event_id", req.GetEventId(),
+		"image_id", req.GetImageId(),
+	)
+
+	if req.GetEventId() <= 0 || req.GetImageId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event or image ID")
+	}
+
+	event, err := s.storer.RemoveEventImage(ctx, req.GetEventId(), req.GetImageId())
+	if err != nil {
+		s.log.Error("failed to remove event image",
+			"method", "RemoveEventImage",
+			"event_id", req.GetEventId(),
+			"image_id", req.GetImageId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	s.indexEventImage(ctx, "RemoveEventImage", event)
+
+	s.log.Info("event image removed successfully",
+		"method", "RemoveEventImage",
+		"event_id", event.Id,
+		"image_id", req.GetImageId(),
+	)
+
+	return DBEventToProtoEventRes(event), nil
+}
+
+// ReorderEventImages задает порядок изображений события.
+func (s *Server) ReorderEventImages(ctx context.Context, req *eventPb.ReorderEventImagesReq) (*eventPb.EventRes, error) {
+	s.log.Info("starting reorder event images",
+		"method", "ReorderEventImages",
+		"event_id", req.GetEventId(),
+		"image_ids", req.GetImageIds(),
+	)
+
+	if req.GetEventId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event ID")
+	}
+
+	event, err := s.storer.ReorderEventImages(ctx, req.GetEventId(), req.GetImageIds())
+	if err != nil {
+		s.log.Error("failed to reorder event images",
+			"method", "ReorderEventImages",
+			"event_id", req.GetEventId(),
+			"error", err,
+		)
+		if errors.Is(err, db.ErrImagesMismatch) {
+			return nil, status.Error(codes.InvalidArgument, "image_ids must list every image of the event exactly once")
+		}
+		return nil, wrapError(err)
+	}
+
+	s.indexEventImage(ctx, "ReorderEventImages", event)
+
+	s.log.Info("event images reordered successfully",
+		"method", "ReorderEventImages",
+		"event_id", event.Id,
+	)
+
+	return DBEventToProtoEventRes(event), nil
+}
+
+// indexEventImage переиндексирует событие после изменения изображений.
+// Из изображений в индекс попадает только обложка.
+func (s *Server) indexEventImage(ctx context.Context, method string, event *db.Event) {
+	if err := s.esService.UpdateEvent(ctx, event); err != nil {
+		s.log.Error("failed to update event image in OpenSearch",
+			"method", method,
+			"event_id", event.Id,
+			"error", err,
+		)
+		// Не возвращаем ошибку, так как изменение уже сохранено в PostgreSQL
+	}
+}
+
+// validateEventImage проверяет URL, подпись, размеры и роль изображения.
+func validateEventImage(image *db.EventImage) error {
+	if image.URL == "" {
+		return errors.New("image url is required")
+	}
+	if len(image.URL) > db.MaxImageURLLength {
+		return fmt.Errorf("image url exceeds %d characters", db.MaxImageURLLength)
+	}
+
+	u, err := url.Parse(image.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid image url %q, expected http(s) URL", image.URL)
+	}
+
+	if utf8.RuneCountInString(image.AltText) > db.MaxImageAltTextLength {
+		return fmt.Errorf("image alt exceeds %d characters", db.MaxImageAltTextLength)
+	}
+
+	if (image.Width != nil && *image.Width <= 0) || (image.Height != nil && *image.Height <= 0) {
+		return errors.New("image width and height must be positive")
+	}
+
+	if !db.IsValidImageRole(image.Role) {
+		return fmt.Errorf("invalid image role %q, expected cover, gallery or poster", image.Role)
+	}
+
+	return nil
+}
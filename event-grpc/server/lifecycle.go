@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxStatusReasonLength максимальная длина причины отмены или переноса
+const maxStatusReasonLength = 1000
+
+// PublishEvent публикует событие сразу или планирует публикацию на publish_at.
+func (s *Server) PublishEvent(ctx context.Context, req *eventPb.PublishEventReq) (*eventPb.EventRes, error) {
+	s.log.Info("starting publish event",
+		"method", "PublishEvent",
+		"event_id", req.GetId(),
+	)
+
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event ID")
+	}
+	if req.GetPublishAt() != nil {
+		if err := req.GetPublishAt().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid publish_at: %v", err)
+		}
+	}
+
+	event, err := s.storer.GetEventByID(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get event for publishing",
+			"method", "PublishEvent",
+			"event_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	now := time.Now()
+	publishAt := protoTimestampToTime(req.GetPublishAt())
+	target := db.StatusPublished
+	if publishAt != nil && publishAt.After(now) {
+		target = db.StatusScheduled
+	}
+
+	if err := s.changeEventStatus(ctx, "PublishEvent", event, target, "", publishAt, now); err != nil {
+		return nil, err
+	}
+
+	return DBEventToProtoEventRes(event), nil
+}
+
+// CancelEvent отменяет событие. Отмена окончательна, причина обязательна.
+func (s *Server) CancelEvent(ctx context.Context, req *eventPb.CancelEventReq) (*eventPb.EventRes, error) {
+	s.log.Info("starting cancel event",
+		"method", "CancelEvent",
+		"event_id", req.GetId(),
+	)
+
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event ID")
+	}
+	reason := strings.TrimSpace(req.GetReason())
+	if reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "cancellation reason is required")
+	}
+	if err := validateStatusReason(reason); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	event, err := s.storer.GetEventByID(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get event for cancellation",
+			"method", "CancelEvent",
+			"event_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	if err := s.changeEventStatus(ctx, "CancelEvent", event, db.StatusCancelled, reason, nil, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return DBEventToProtoEventRes(event), nil
+}
+
+// PostponeEvent переносит опубликованное событие. Новая дата необязательна:
+// ее можно передать сразу или позже через UpdateEvent и PublishEvent.
+func (s *Server) PostponeEvent(ctx context.Context, req *eventPb.PostponeEventReq) (*eventPb.EventRes, error) {
+	s.log.Info("starting postpone event",
+		"method", "PostponeEvent",
+		"event_id", req.GetId(),
+	)
+
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event ID")
+	}
+	reason := strings.TrimSpace(req.GetReason())
+	if err := validateStatusReason(reason); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.GetEndsAt() != nil && req.GetStartsAt() == nil {
+		return nil, status.Error(codes.InvalidArgument, "ends_at requires starts_at")
+	}
+	if err := validateEventSchedule(req.GetStartsAt(), req.GetEndsAt(), ""); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	event, err := s.storer.GetEventByID(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get event for postponing",
+			"method", "PostponeEvent",
+			"event_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	if err := s.changeEventStatus(ctx, "PostponeEvent", event, db.StatusPostponed, reason, nil, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Новая дата сохраняется после смены статуса: если она не запишется,
+	// событие останется перенесенным без даты, что тоже корректно
+	if startsAt := protoTimestampToTime(req.GetStartsAt()); startsAt != nil {
+		event.Reschedule(*startsAt, protoTimestampToTime(req.GetEndsAt()))
+
+		updatedEvent, err := s.storer.UpdateEvent(ctx, event)
+		if err != nil {
+			s.log.Error("failed to save new date of postponed event",
+				"method", "PostponeEvent",
+				"event_id", event.Id,
+				"error", err,
+			)
+			return nil, wrapError(err)
+		}
+		event = updatedEvent
+
+		if err := s.esService.UpdateEvent(ctx, event); err != nil {
+			s.log.Error("failed to update postponed event in OpenSearch",
+				"method", "PostponeEvent",
+				"event_id", event.Id,
+				"error", err,
+			)
+		}
+	}
+
+	return DBEventToProtoEventRes(event), nil
+}
+
+// changeEventStatus проверяет переход, сохраняет новый статус и переиндексирует событие.
+// Возвращает готовую gRPC ошибку.
+func (s *Server) changeEventStatus(ctx context.Context, method string, event *db.Event, target, reason string, publishAt *time.Time, now time.Time) error {
+	from := event.Status
+
+	if err := event.TransitionTo(target, reason, publishAt, now); err != nil {
+		s.log.Warn("rejected event status transition",
+			"method", method,
+			"event_id", event.Id,
+			"from", from,
+			"to", target,
+		)
+		return statusChangeError(err)
+	}
+
+	if err := s.storer.ChangeEventStatus(ctx, event, from); err != nil {
+		s.log.Error("failed to change event status",
+			"method", method,
+			"event_id", event.Id,
+			"from", from,
+			"to", target,
+			"error", err,
+		)
+		return statusChangeError(err)
+	}
+
+	if err := s.esService.UpdateEvent(ctx, event); err != nil {
+		s.log.Error("failed to update event status in OpenSearch",
+			"method", method,
+			"event_id", event.Id,
+			"error", err,
+		)
+		// Не возвращаем ошибку, так как статус уже сохранен в PostgreSQL
+	}
+
+	s.log.Info("event status changed",
+		"method", method,
+		"event_id", event.Id,
+		"from", from,
+		"to", target,
+	)
+
+	return nil
+}
+
+// syncSoldOutStatus переводит опубликованное событие в sold_out и обратно
+// по доступности билетов. Ошибки только логируются: само событие уже сохранено.
+func (s *Server) syncSoldOutStatus(ctx context.Context, method string, event *db.Event) {
+	target := event.SoldOutTransition()
+	if target == "" {
+		return
+	}
+
+	from := event.Status
+	if err := event.TransitionTo(target, "", nil, time.Now()); err != nil {
+		return
+	}
+
+	if err := s.storer.ChangeEventStatus(ctx, event, from); err != nil {
+		s.log.Warn("failed to sync sold out status",
+			"method", method,
+			"event_id", event.Id,
+			"to", target,
+			"error", err,
+		)
+		event.Status = from
+	}
+}
+
+// statusChangeError преобразует ошибки смены статуса в gRPC ошибки.
+func statusChangeError(err error) error {
+	switch {
+	case errors.Is(err, db.ErrInvalidStatusTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, db.ErrEventStatusConflict):
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return wrapError(err)
+	}
+}
+
+// validateStatusReason проверяет длину причины отмены или переноса.
+func validateStatusReason(reason string) error {
+	if utf8.RuneCountInString(reason) > maxStatusReasonLength {
+		return fmt.Errorf("reason exceeds %d characters", maxStatusReasonLength)
+	}
+	return nil
+}
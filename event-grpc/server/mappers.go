@@ -2,13 +2,17 @@ package server
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
 	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/internal/dedup"
 	"github.com/rx3lixir/event-service/internal/opensearch/models"
 	"github.com/rx3lixir/event-service/internal/opensearch/search"
 	"github.com/rx3lixir/event-service/internal/opensearch/suggestions"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -43,6 +47,15 @@ func ProtoToEventFilter(req *eventPb.ListEventsReq) (*db.EventFilter, error) {
 		opts = append(opts, db.WithMaxPrice(maxPrice))
 	}
 
+	if req.IsFree != nil {
+		opts = append(opts, db.WithIsFree(req.GetIsFree()))
+	}
+
+	// Публичный список показывает только опубликованные события
+	if !req.GetIncludeUnpublished() {
+		opts = append(opts, db.WithStatuses(db.PublicStatuses()...))
+	}
+
 	// Фильтр по диапазону дат с валидацией формата
 	if err := applyDateFilters(req, &opts); err != nil {
 		return nil, err
@@ -58,8 +71,32 @@ func ProtoToEventFilter(req *eventPb.ListEventsReq) (*db.EventFilter, error) {
 		opts = append(opts, db.WithSource(req.GetSource()))
 	}
 
+	// Фильтры по тегам
+	if len(req.GetTagsAny()) > 0 {
+		opts = append(opts, db.WithTagsAny(req.GetTagsAny()...))
+	}
+	if len(req.GetTagsAll()) > 0 {
+		opts = append(opts, db.WithTagsAll(req.GetTagsAll()...))
+	}
+
+	// Фильтр по организаторам
+	if len(req.GetOrganizerIds()) > 0 {
+		opts = append(opts, db.WithOrganizers(req.GetOrganizerIds()...))
+	}
+
+	// Фильтры по площадкам и городам
+	if err := validateVenueIDs(req.GetVenueIds()); err != nil {
+		return nil, err
+	}
+	if len(req.GetVenueIds()) > 0 {
+		opts = append(opts, db.WithVenues(req.GetVenueIds()...))
+	}
+	if len(req.GetCities()) > 0 {
+		opts = append(opts, db.WithCities(req.GetCities()...))
+	}
+
 	// Пагинация
-	if req.Limit != nil || req.Offset != nil {
+	if req.Limit != nil || req.Offset != nil || req.PageToken != nil {
 		limit := int(req.GetLimit())
 		offset := int(req.GetOffset())
 
@@ -74,7 +111,34 @@ func ProtoToEventFilter(req *eventPb.ListEventsReq) (*db.EventFilter, error) {
 		opts = append(opts, db.WithPagination(limit, offset))
 	}
 
-	return db.NewEventFilter(opts...), nil
+	// Сортировка
+	sorts, err := ProtoToEventSort(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(sorts) > 0 {
+		opts = append(opts, db.WithSort(sorts...))
+	}
+
+	filter := db.NewEventFilter(opts...)
+
+	// Keyset-пагинация по токену предыдущей страницы
+	if req.PageToken != nil {
+		if req.GetOffset() > 0 {
+			return nil, fmt.Errorf("page_token cannot be combined with offset")
+		}
+		token, err := decodePageToken(req.GetPageToken(), pageTokenPostgres, filter.GetSort())
+		if err != nil {
+			return nil, err
+		}
+		cursor, err := token.eventCursor(filter.GetSort())
+		if err != nil {
+			return nil, err
+		}
+		filter.After = &cursor
+	}
+
+	return filter, nil
 }
 
 // ProtoToOpenSearchFilter конвертирует ListEventsReq в фильтр для OpenSearch
@@ -90,6 +154,10 @@ func ProtoToOpenSearchFilter(req *eventPb.ListEventsReq) (*search.Filter, error)
 		filter.WithQuery(req.GetSearchText())
 	}
 
+	if req.Locale != nil {
+		filter.WithLanguage(db.LocaleLanguage(db.NormalizeLocale(req.GetLocale())))
+	}
+
 	// Фильтр по категориям
 	if len(req.GetCategoryIDs()) > 0 {
 		filter.WithCategories(req.GetCategoryIDs()...)
@@ -109,21 +177,19 @@ func ProtoToOpenSearchFilter(req *eventPb.ListEventsReq) (*search.Filter, error)
 		filter.WithPriceRange(minPrice, maxPrice)
 	}
 
-	// Фильтр по диапазону дат
-	var dateFrom, dateTo *time.Time
-	if req.DateFrom != nil {
-		if parsed, err := time.Parse("2006-01-02", req.GetDateFrom()); err == nil {
-			dateFrom = &parsed
-		} else {
-			return nil, fmt.Errorf("invalid date_from format, expected YYYY-MM-DD: %s", req.GetDateFrom())
-		}
+	if req.IsFree != nil {
+		filter.WithFree(req.GetIsFree())
 	}
-	if req.DateTo != nil {
-		if parsed, err := time.Parse("2006-01-02", req.GetDateTo()); err == nil {
-			dateTo = &parsed
-		} else {
-			return nil, fmt.Errorf("invalid date_to format, expected YYYY-MM-DD: %s", req.GetDateTo())
-		}
+
+	// Публичный поиск показывает только опубликованные события
+	if !req.GetIncludeUnpublished() {
+		filter.WithStatuses(db.PublicStatuses()...)
+	}
+
+	// Фильтр по диапазону дат (дни интерпретируются в часовом поясе запроса)
+	dateFrom, dateTo, err := parseDateRange(req)
+	if err != nil {
+		return nil, err
 	}
 	if dateFrom != nil || dateTo != nil {
 		filter.WithDateRange(dateFrom, dateTo)
@@ -139,6 +205,35 @@ func ProtoToOpenSearchFilter(req *eventPb.ListEventsReq) (*search.Filter, error)
 		filter.WithSource(req.GetSource())
 	}
 
+	// Фильтры по тегам
+	if len(req.GetTagsAny()) > 0 {
+		filter.WithTagsAny(req.GetTagsAny()...)
+	}
+	if len(req.GetTagsAll()) > 0 {
+		filter.WithTagsAll(req.GetTagsAll()...)
+	}
+
+	// Фильтр по организаторам
+	if len(req.GetOrganizerIds()) > 0 {
+		filter.WithOrganizers(req.GetOrganizerIds()...)
+	}
+
+	// Фильтры по площадкам и городам
+	if err := validateVenueIDs(req.GetVenueIds()); err != nil {
+		return nil, err
+	}
+	if len(req.GetVenueIds()) > 0 {
+		filter.WithVenues(req.GetVenueIds()...)
+	}
+	if len(req.GetCities()) > 0 {
+		filter.WithCities(req.GetCities()...)
+	}
+
+	// Гео-фильтры
+	if err := applyGeoFilters(req, filter); err != nil {
+		return nil, err
+	}
+
 	// Пагинация
 	if req.Limit != nil || req.Offset != nil {
 		limit := int(req.GetLimit())
@@ -150,35 +245,198 @@ func ProtoToOpenSearchFilter(req *eventPb.ListEventsReq) (*search.Filter, error)
 		filter.WithPagination(offset, limit)
 	}
 
+	// Сортировка
+	sorts, err := ProtoToEventSort(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(sorts) > 0 {
+		filter.WithSort(sorts...)
+	}
+
+	// Без offset выдача идет через search_after: глубокие страницы не упираются
+	// в index.max_result_window
+	if req.Offset == nil {
+		var after *models.SearchCursor
+		if req.PageToken != nil {
+			token, err := decodePageToken(req.GetPageToken(), pageTokenOpenSearch, filter.GetSort())
+			if err != nil {
+				return nil, err
+			}
+			if after, err = token.searchCursor(); err != nil {
+				return nil, err
+			}
+		}
+		filter.WithCursor(after)
+	} else if req.PageToken != nil {
+		return nil, fmt.Errorf("page_token cannot be combined with offset")
+	}
+
 	return filter, nil
 }
 
+// applyGeoFilters проверяет и применяет near/bbox фильтры и сортировку по расстоянию
+func applyGeoFilters(req *eventPb.ListEventsReq, filter *search.Filter) error {
+	if near := req.GetNear(); near != nil {
+		if err := validateCoordinates(near.GetLat(), near.GetLon()); err != nil {
+			return fmt.Errorf("invalid near: %w", err)
+		}
+		if near.GetRadiusKm() <= 0 || near.GetRadiusKm() > maxGeoRadiusKm {
+			return fmt.Errorf("near.radius_km must be in (0, %g], got: %g", maxGeoRadiusKm, near.GetRadiusKm())
+		}
+		filter.WithNear(near.GetLat(), near.GetLon(), near.GetRadiusKm())
+	}
+
+	if bbox := req.GetBbox(); bbox != nil {
+		if err := validateCoordinates(bbox.GetTopLeftLat(), bbox.GetTopLeftLon()); err != nil {
+			return fmt.Errorf("invalid bbox top_left: %w", err)
+		}
+		if err := validateCoordinates(bbox.GetBottomRightLat(), bbox.GetBottomRightLon()); err != nil {
+			return fmt.Errorf("invalid bbox bottom_right: %w", err)
+		}
+		if bbox.GetTopLeftLat() < bbox.GetBottomRightLat() {
+			return fmt.Errorf("bbox top_left_lat (%g) cannot be below bottom_right_lat (%g)",
+				bbox.GetTopLeftLat(), bbox.GetBottomRightLat())
+		}
+		filter.WithBoundingBox(bbox.GetTopLeftLat(), bbox.GetTopLeftLon(), bbox.GetBottomRightLat(), bbox.GetBottomRightLon())
+	}
+
+	return nil
+}
+
+// ProtoToEventSort разбирает сортировку запроса. sort_by_distance - сокращение
+// для сортировки только по расстоянию. Пустой результат - сортировка по умолчанию.
+func ProtoToEventSort(req *eventPb.ListEventsReq) ([]db.EventSort, error) {
+	if req.GetSortByDistance() {
+		if len(req.GetSort()) > 0 {
+			return nil, fmt.Errorf("sort_by_distance cannot be combined with sort")
+		}
+		if req.GetNear() == nil {
+			return nil, fmt.Errorf("sort_by_distance requires near")
+		}
+		return []db.EventSort{{Field: db.SortFieldDistance}}, nil
+	}
+
+	sorts := make([]db.EventSort, 0, len(req.GetSort()))
+	seen := make(map[string]bool, len(req.GetSort()))
+	for _, spec := range req.GetSort() {
+		sort, err := db.ParseEventSort(spec.GetField(), spec.GetDirection())
+		if err != nil {
+			return nil, err
+		}
+		if seen[sort.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", sort.Field)
+		}
+		seen[sort.Field] = true
+
+		switch sort.Field {
+		case db.SortFieldRelevance:
+			if req.GetSearchText() == "" {
+				return nil, fmt.Errorf("sort by relevance requires search_text")
+			}
+		case db.SortFieldDistance:
+			if req.GetNear() == nil {
+				return nil, fmt.Errorf("sort by distance requires near")
+			}
+		}
+		sorts = append(sorts, sort)
+	}
+
+	return sorts, nil
+}
+
+// hasGeoFilters сообщает, требует ли запрос гео-поиска
+func hasGeoFilters(req *eventPb.ListEventsReq) bool {
+	return req.GetNear() != nil || req.GetBbox() != nil || req.GetSortByDistance()
+}
+
 // applyDateFilters применяет фильтры по датам с валидацией
 func applyDateFilters(req *eventPb.ListEventsReq, opts *[]db.FilterOption) error {
+	dateFrom, dateTo, err := parseDateRange(req)
+	if err != nil {
+		return err
+	}
+
+	// Применяем фильтр по датам, если хотя бы одна дата указана
+	if dateFrom != nil || dateTo != nil {
+		*opts = append(*opts, db.WithDateRange(dateFrom, dateTo))
+	}
+
+	return nil
+}
+
+// parseDateRange разбирает date_from/date_to (YYYY-MM-DD) как полночь в часовом поясе запроса.
+// Если timezone не указан, используется db.DefaultTimezone.
+func parseDateRange(req *eventPb.ListEventsReq) (*time.Time, *time.Time, error) {
+	loc := db.LoadLocation(db.DefaultTimezone)
+	if req.Timezone != nil {
+		parsedLoc, err := time.LoadLocation(req.GetTimezone())
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid timezone: %s", req.GetTimezone())
+		}
+		loc = parsedLoc
+	}
+
 	var dateFrom, dateTo *time.Time
 
 	if req.DateFrom != nil {
-		if parsed, err := time.Parse("2006-01-02", req.GetDateFrom()); err == nil {
+		if parsed, err := time.ParseInLocation(db.LegacyDateLayout, req.GetDateFrom(), loc); err == nil {
 			dateFrom = &parsed
 		} else {
-			return fmt.Errorf("invalid date_from format, expected YYYY-MM-DD: %s", req.GetDateFrom())
+			return nil, nil, fmt.Errorf("invalid date_from format, expected YYYY-MM-DD: %s", req.GetDateFrom())
 		}
 	}
 
 	if req.DateTo != nil {
-		if parsed, err := time.Parse("2006-01-02", req.GetDateTo()); err == nil {
+		if parsed, err := time.ParseInLocation(db.LegacyDateLayout, req.GetDateTo(), loc); err == nil {
 			dateTo = &parsed
 		} else {
-			return fmt.Errorf("invalid date_to format, expected YYYY-MM-DD: %s", req.GetDateTo())
+			return nil, nil, fmt.Errorf("invalid date_to format, expected YYYY-MM-DD: %s", req.GetDateTo())
 		}
 	}
 
-	// Применяем фильтр по датам, если хотя бы одна дата указана
-	if dateFrom != nil || dateTo != nil {
-		*opts = append(*opts, db.WithDateRange(dateFrom, dateTo))
+	return dateFrom, dateTo, nil
+}
+
+// protoTimestampToTime конвертирует опциональный Timestamp в *time.Time
+func protoTimestampToTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
 	}
 
-	return nil
+	t := ts.AsTime()
+	return &t
+}
+
+// timeToProtoTimestamp конвертирует опциональный *time.Time в Timestamp
+func timeToProtoTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+// protoTimestampsToTimes конвертирует список Timestamp в []time.Time
+func protoTimestampsToTimes(list []*timestamppb.Timestamp) []time.Time {
+	times := make([]time.Time, 0, len(list))
+	for _, ts := range list {
+		times = append(times, ts.AsTime())
+	}
+	return times
+}
+
+// timesToProtoTimestamps конвертирует []time.Time в список Timestamp
+func timesToProtoTimestamps(times []time.Time) []*timestamppb.Timestamp {
+	if len(times) == 0 {
+		return nil
+	}
+
+	list := make([]*timestamppb.Timestamp, 0, len(times))
+	for _, t := range times {
+		list = append(list, timestamppb.New(t))
+	}
+	return list
 }
 
 // ProtoToCreateEventParams конвертирует CreateEventReq из gRPC в db.CreateEventParams
@@ -193,12 +451,27 @@ func ProtoToCreateEventParams(req *eventPb.CreateEventReq) db.CreateEventParams
 		Price:       float32(req.GetPrice()), // proto float это float64 в Go
 		Image:       req.GetImage(),
 		Source:      req.GetSource(),
+		StartsAt:    protoTimestampToTime(req.GetStartsAt()),
+		EndsAt:      protoTimestampToTime(req.GetEndsAt()),
+		Timezone:    req.GetTimezone(),
+
+		RecurrenceRule:    req.GetRecurrenceRule(),
+		RecurrenceExDates: protoTimestampsToTimes(req.GetRecurrenceExdates()),
+		VenueID:           req.VenueId,
+		OrganizerID:       req.OrganizerId,
+		Tags:              req.GetTags(),
+		TicketTiers:       ProtoToTicketTiers(req.GetTicketTiers()),
+		Translations:      ProtoToTranslations(req.GetTranslations()),
+		Publish:           req.GetPublish(),
+		PublishAt:         protoTimestampToTime(req.GetPublishAt()),
 	}
 }
 
 // ProtoToUpdateEventParams конвертирует UpdateEventReq из gRPC в db.UpdateEventParams.
 // Также возвращает ID события.
 func ProtoToUpdateEventParams(req *eventPb.UpdateEventReq) (int64, db.UpdateEventParams) {
+	fields, _ := ProtoToEventUpdateFields(req.GetUpdateMask()) // Маска проверена в validateUpdateEventReq
+
 	return req.GetId(), db.UpdateEventParams{
 		Name:        req.GetName(),
 		Description: req.GetDescription(),
@@ -209,6 +482,109 @@ func ProtoToUpdateEventParams(req *eventPb.UpdateEventReq) (int64, db.UpdateEven
 		Price:       req.GetPrice(),
 		Image:       req.GetImage(),
 		Source:      req.GetSource(),
+		StartsAt:    protoTimestampToTime(req.GetStartsAt()),
+		EndsAt:      protoTimestampToTime(req.GetEndsAt()),
+		Timezone:    req.GetTimezone(),
+
+		RecurrenceRule:    req.GetRecurrenceRule(),
+		RecurrenceExDates: protoTimestampsToTimes(req.GetRecurrenceExdates()),
+		VenueID:           req.VenueId,
+		OrganizerID:       req.OrganizerId,
+		Tags:              req.GetTags(),
+		TicketTiers:       ProtoToTicketTiers(req.GetTicketTiers()),
+		Translations:      ProtoToTranslations(req.GetTranslations()),
+		Fields:            fields,
+	}
+}
+
+// eventMaskPathAliases пути маски UpdateEventReq, имена которых отличаются от полей события
+var eventMaskPathAliases = map[string]string{
+	"categoryID": db.EventFieldCategoryID,
+}
+
+// ProtoToEventUpdateFields конвертирует маску UpdateEventReq в поля события (db.EventField*).
+// Пустая маска означает полное обновление и возвращает nil.
+func ProtoToEventUpdateFields(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	return protoMaskToFields(mask, eventMaskPathAliases, db.IsUpdatableEventField)
+}
+
+// ProtoToCategoryUpdateFields конвертирует маску UpdateCategoryReq в поля категории (db.CategoryField*).
+func ProtoToCategoryUpdateFields(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	return protoMaskToFields(mask, nil, db.IsUpdatableCategoryField)
+}
+
+// protoMaskToFields проверяет пути маски и убирает повторы
+func protoMaskToFields(mask *fieldmaskpb.FieldMask, aliases map[string]string, valid func(string) bool) ([]string, error) {
+	if len(mask.GetPaths()) == 0 {
+		return nil, nil
+	}
+
+	fields := make([]string, 0, len(mask.GetPaths()))
+	for _, path := range mask.GetPaths() {
+		field := path
+		if alias, ok := aliases[path]; ok {
+			field = alias
+		}
+		if !valid(field) {
+			return nil, fmt.Errorf("unknown update_mask path: %q", path)
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+// ProtoToTicketTiers конвертирует билетные категории из запроса
+func ProtoToTicketTiers(tiers []*eventPb.TicketTier) []db.TicketTier {
+	if len(tiers) == 0 {
+		return nil
+	}
+
+	result := make([]db.TicketTier, 0, len(tiers))
+	for _, tier := range tiers {
+		result = append(result, db.TicketTier{
+			Name:         tier.GetName(),
+			MinPrice:     tier.GetMinPrice(),
+			MaxPrice:     tier.MaxPrice,
+			Currency:     tier.GetCurrency(),
+			Availability: tier.GetAvailability(),
+			PurchaseURL:  tier.GetPurchaseUrl(),
+		})
+	}
+	return result
+}
+
+// ProtoToTranslations конвертирует переводы из запроса
+func ProtoToTranslations(translations []*eventPb.EventTranslation) []db.EventTranslation {
+	if len(translations) == 0 {
+		return nil
+	}
+
+	result := make([]db.EventTranslation, 0, len(translations))
+	for _, translation := range translations {
+		result = append(result, db.EventTranslation{
+			Locale:      translation.GetLocale(),
+			Name:        translation.GetName(),
+			Description: translation.GetDescription(),
+		})
+	}
+	return result
+}
+
+// ProtoToOccurrenceOverride конвертирует UpdateEventOccurrenceReq в db.OccurrenceOverride
+func ProtoToOccurrenceOverride(req *eventPb.UpdateEventOccurrenceReq) *db.OccurrenceOverride {
+	return &db.OccurrenceOverride{
+		EventID:         req.GetEventId(),
+		OccurrenceStart: req.GetOccurrenceStart().AsTime(),
+		Name:            req.Name,
+		Description:     req.Description,
+		StartsAt:        protoTimestampToTime(req.GetStartsAt()),
+		EndsAt:          protoTimestampToTime(req.GetEndsAt()),
+		Location:        req.Location,
+		Price:           req.Price,
+		IsCancelled:     req.GetCancelled(),
 	}
 }
 
@@ -238,11 +614,66 @@ func DBEventToProtoEventRes(event *db.Event) *eventPb.EventRes {
 		Price:       float32(event.Price),
 		Image:       event.Image,
 		Source:      event.Source,
+		StartsAt:    timeToProtoTimestamp(event.StartsAt),
+		EndsAt:      timeToProtoTimestamp(event.EndsAt),
+		Timezone:    event.Timezone,
 		CreatedAt:   timestamppb.New(event.CreatedAt),
 		UpdatedAt:   updatedAtProto,
+
+		RecurrenceRule:    event.RecurrenceRule,
+		RecurrenceExdates: timesToProtoTimestamps(event.RecurrenceExDates),
+		OccurrenceStart:   timeToProtoTimestamp(event.OccurrenceStart),
+		IsOverride:        event.IsOverride,
+		VenueId:           event.VenueID,
+		Venue:             DBVenueToProtoVenueRes(event.Venue),
+		OrganizerId:       event.OrganizerID,
+		Organizer:         DBOrganizerToProtoOrganizerRes(event.Organizer),
+		DistanceKm:        event.DistanceKm,
+		Tags:              event.Tags,
+		PriceFrom:         event.PriceFrom,
+		PriceTo:           event.PriceTo,
+		Currency:          event.Currency,
+		TicketTiers:       TicketTiersToProto(event.TicketTiers),
+		Status:            event.Status,
+		PublishAt:         timeToProtoTimestamp(event.PublishAt),
+		StatusReason:      event.StatusReason,
+		DeletedAt:         timeToProtoTimestamp(event.DeletedAt),
+		Version:           event.Version,
+		SourceId:          event.SourceID,
+		ExternalId:        event.ExternalID,
+		Images:            ImagesToProto(event.Images),
+		Locale:            db.DefaultLocale,
+		Translations:      TranslationsToProto(event.Translations),
 	}
 }
 
+// DBEventRevisionToProto конвертирует db.EventRevision в proto EventRevision
+func DBEventRevisionToProto(revision *db.EventRevision) *eventPb.EventRevision {
+	return &eventPb.EventRevision{
+		Id:            revision.Id,
+		EventId:       revision.EventID,
+		Revision:      int32(revision.Revision),
+		Action:        revision.Action,
+		Actor:         revision.Actor,
+		ChangedFields: revision.ChangedFields,
+		Snapshot:      DBEventToProtoEventRes(revision.Snapshot.Event(revision.EventID)),
+		CreatedAt:     timestamppb.New(revision.CreatedAt),
+	}
+}
+
+// FieldChangesToProto конвертирует изменения полей в proto формат
+func FieldChangesToProto(changes []db.FieldChange) []*eventPb.FieldChange {
+	result := make([]*eventPb.FieldChange, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, &eventPb.FieldChange{
+			Field:    change.Field,
+			OldValue: string(change.OldValue),
+			NewValue: string(change.NewValue),
+		})
+	}
+	return result
+}
+
 // OpenSearchEventToProtoEventRes конвертирует opensearch.EventDocument в EventRes
 func OpenSearchEventToProtoEventRes(doc *models.EventDocument) *eventPb.EventRes {
 	if doc == nil {
@@ -265,11 +696,157 @@ func OpenSearchEventToProtoEventRes(doc *models.EventDocument) *eventPb.EventRes
 		Price:       doc.Price,
 		Image:       doc.Image,
 		Source:      doc.Source,
+		StartsAt:    timeToProtoTimestamp(doc.StartsAt),
+		EndsAt:      timeToProtoTimestamp(doc.EndsAt),
+		Timezone:    doc.Timezone,
 		CreatedAt:   timestamppb.New(doc.CreatedAt),
 		UpdatedAt:   updatedAtProto,
+
+		RecurrenceRule:    doc.RecurrenceRule,
+		RecurrenceExdates: timesToProtoTimestamps(doc.RecurrenceExDates),
+		OccurrenceStart:   timeToProtoTimestamp(doc.OccurrenceStart),
+		IsOverride:        doc.IsOverride,
+		VenueId:           doc.VenueID,
+		Venue:             DBVenueToProtoVenueRes(doc.Venue()),
+		OrganizerId:       doc.OrganizerID,
+		Organizer:         DBOrganizerToProtoOrganizerRes(doc.Organizer()),
+		DistanceKm:        doc.DistanceKm,
+		Tags:              doc.Tags,
+		PriceFrom:         doc.PriceFrom,
+		PriceTo:           doc.PriceTo,
+		Currency:          doc.Currency,
+		TicketTiers:       TicketTiersToProto(doc.TicketTiers),
+		Status:            doc.Status,
+		PublishAt:         timeToProtoTimestamp(doc.PublishAt),
+		StatusReason:      doc.StatusReason,
+		Version:           doc.Version,
+		SourceId:          doc.SourceID,
+		ExternalId:        doc.ExternalID,
+		Locale:            db.DefaultLocale,
+		Translations:      TranslationsToProto(doc.Translations),
+		Images:            ImagesToProto(doc.Images),
 	}
 }
 
+// TicketTiersToProto конвертирует билетные категории в proto
+func TicketTiersToProto(tiers []db.TicketTier) []*eventPb.TicketTier {
+	if len(tiers) == 0 {
+		return nil
+	}
+
+	result := make([]*eventPb.TicketTier, 0, len(tiers))
+	for _, tier := range tiers {
+		result = append(result, &eventPb.TicketTier{
+			Id:           tier.Id,
+			Name:         tier.Name,
+			MinPrice:     tier.MinPrice,
+			MaxPrice:     tier.MaxPrice,
+			Currency:     tier.Currency,
+			Availability: tier.Availability,
+			PurchaseUrl:  tier.PurchaseURL,
+		})
+	}
+	return result
+}
+
+// TranslationsToProto конвертирует переводы события в proto
+func TranslationsToProto(translations []db.EventTranslation) []*eventPb.EventTranslation {
+	if len(translations) == 0 {
+		return nil
+	}
+
+	result := make([]*eventPb.EventTranslation, 0, len(translations))
+	for _, translation := range translations {
+		result = append(result, &eventPb.EventTranslation{
+			Locale:      translation.Locale,
+			Name:        translation.Name,
+			Description: translation.Description,
+		})
+	}
+	return result
+}
+
+// LocalizeEventRes заменяет name и description события переводом, наиболее подходящим
+// языку locale (db.ResolveLocale). Пустые поля перевода остаются на русском.
+func LocalizeEventRes(res *eventPb.EventRes, locale string) {
+	if res == nil || locale == "" {
+		return
+	}
+
+	locales := make([]string, 0, len(res.Translations))
+	for _, translation := range res.Translations {
+		locales = append(locales, translation.GetLocale())
+	}
+
+	resolved := db.ResolveLocale(locales, locale)
+	for _, translation := range res.Translations {
+		if resolved == "" || translation.GetLocale() != resolved {
+			continue
+		}
+		if translation.GetName() != "" {
+			res.Name = translation.GetName()
+		}
+		if translation.GetDescription() != "" {
+			res.Description = translation.GetDescription()
+		}
+		res.Locale = resolved
+	}
+}
+
+// ImagesToProto конвертирует изображения события в proto
+func ImagesToProto(images []db.EventImage) []*eventPb.EventImage {
+	if len(images) == 0 {
+		return nil
+	}
+
+	result := make([]*eventPb.EventImage, 0, len(images))
+	for _, image := range images {
+		result = append(result, &eventPb.EventImage{
+			Id:     image.Id,
+			Url:    image.URL,
+			Width:  intToProtoInt32(image.Width),
+			Height: intToProtoInt32(image.Height),
+			Alt:    image.AltText,
+			Role:   image.Role,
+		})
+	}
+	return result
+}
+
+// ProtoToEventImage конвертирует AddEventImageReq в db.EventImage
+func ProtoToEventImage(req *eventPb.AddEventImageReq) *db.EventImage {
+	role := strings.ToLower(strings.TrimSpace(req.GetRole()))
+	if role == "" {
+		role = db.ImageRoleGallery
+	}
+
+	return &db.EventImage{
+		URL:     strings.TrimSpace(req.GetUrl()),
+		Width:   protoInt32ToInt(req.Width),
+		Height:  protoInt32ToInt(req.Height),
+		AltText: strings.TrimSpace(req.GetAlt()),
+		Role:    role,
+	}
+}
+
+// intToProtoInt32 конвертирует необязательное целое в proto optional int32
+func intToProtoInt32(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
+
+// protoInt32ToInt конвертирует proto optional int32 в необязательное целое
+func protoInt32ToInt(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
 // DBEventsToProtoEventsList конвертирует срез []*db.Event в []*eventPb.EventRes
 func DBEventsToProtoEventsList(events []*db.Event) []*eventPb.EventRes {
 	if events == nil {
@@ -335,9 +912,14 @@ func OpenSearchResultToListEventsRes(result *models.SearchResult) *eventPb.ListE
 
 // ProtoToCreateCategoryParams конвертирует CreateCategoryReq из gRPC в db.CreateCategoryReq
 func ProtoToCreateCategoryParams(req *eventPb.CreateCategoryReq) *db.CreateCategoryReq {
-	return &db.CreateCategoryReq{
+	params := &db.CreateCategoryReq{
 		Name: req.GetName(),
 	}
+	if req.ParentId != nil && req.GetParentId() > 0 {
+		parentID := int(req.GetParentId())
+		params.ParentID = &parentID
+	}
+	return params
 }
 
 // ProtoToUpdateCategoryParams получает ID и имя категории из запроса
@@ -345,6 +927,25 @@ func ProtoToUpdateCategoryParams(req *eventPb.UpdateCategoryReq) (int, string) {
 	return int(req.GetId()), req.GetName()
 }
 
+// ApplyProtoCategoryParent переносит категорию под нового родителя из запроса.
+// Незаданный parent_id оставляет родителя без изменений, 0 делает категорию корневой.
+// Если parent_id указан в маске, незаданное значение тоже делает категорию корневой.
+func ApplyProtoCategoryParent(category *db.Category, req *eventPb.UpdateCategoryReq) {
+	fields, _ := ProtoToCategoryUpdateFields(req.GetUpdateMask())
+	if len(fields) > 0 && !slices.Contains(fields, db.CategoryFieldParentID) {
+		return
+	}
+	if req.ParentId == nil && len(fields) == 0 {
+		return
+	}
+	if req.GetParentId() == 0 {
+		category.ParentID = nil
+		return
+	}
+	parentID := int(req.GetParentId())
+	category.ParentID = &parentID
+}
+
 // ============================================================================
 // КАТЕГОРИИ - МАППЕРЫ ИЗ DB В PROTO
 // ============================================================================
@@ -355,12 +956,22 @@ func DBCategoryToProtoCategoryRes(category *db.Category) *eventPb.CategoryRes {
 		return nil
 	}
 
-	return &eventPb.CategoryRes{
+	res := &eventPb.CategoryRes{
 		Id:        int32(category.Id),
 		Name:      category.Name,
 		CreatedAt: timestamppb.New(category.CreatedAt),
 		UpdatedAt: timestamppb.New(category.UpdatedAt),
+		Path:      category.Path,
+		Depth:     int32(category.Depth),
+		Children:  DBCategoriesToProtoList(category.Children),
+		Version:   category.Version,
+	}
+	if category.ParentID != nil {
+		parentID := int32(*category.ParentID)
+		res.ParentId = &parentID
 	}
+
+	return res
 }
 
 // DBCategoriesToProtoList конвертирует срез []*db.Category в []*eventPb.CategoryRes
@@ -377,6 +988,244 @@ func DBCategoriesToProtoList(categories []*db.Category) []*eventPb.CategoryRes {
 	return protoCategories
 }
 
+// ============================================================================
+// ПЛОЩАДКИ - МАППЕРЫ ИЗ PROTO В DB
+// ============================================================================
+
+// ProtoToCreateVenueParams конвертирует CreateVenueReq из gRPC в db.CreateVenueReq
+func ProtoToCreateVenueParams(req *eventPb.CreateVenueReq) *db.CreateVenueReq {
+	return &db.CreateVenueReq{
+		Name:      req.GetName(),
+		Address:   req.GetAddress(),
+		City:      req.GetCity(),
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	}
+}
+
+// ApplyProtoVenueUpdate переносит поля UpdateVenueReq в существующую площадку
+func ApplyProtoVenueUpdate(venue *db.Venue, req *eventPb.UpdateVenueReq) {
+	updated := db.NewVenue(&db.CreateVenueReq{
+		Name:      req.GetName(),
+		Address:   req.GetAddress(),
+		City:      req.GetCity(),
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	})
+
+	venue.Name = updated.Name
+	venue.Address = updated.Address
+	venue.City = updated.City
+	venue.Latitude = updated.Latitude
+	venue.Longitude = updated.Longitude
+}
+
+// ============================================================================
+// ПЛОЩАДКИ - МАППЕРЫ ИЗ DB В PROTO
+// ============================================================================
+
+// DBVenueToProtoVenueRes конвертирует db.Venue в VenueRes для gRPC ответа
+func DBVenueToProtoVenueRes(venue *db.Venue) *eventPb.VenueRes {
+	if venue == nil {
+		return nil
+	}
+
+	res := &eventPb.VenueRes{
+		Id:        venue.Id,
+		Name:      venue.Name,
+		Address:   venue.Address,
+		City:      venue.City,
+		Latitude:  venue.Latitude,
+		Longitude: venue.Longitude,
+		UpdatedAt: timeToProtoTimestamp(venue.UpdatedAt),
+	}
+
+	// У площадки, восстановленной из документа OpenSearch, created_at нет
+	if !venue.CreatedAt.IsZero() {
+		res.CreatedAt = timestamppb.New(venue.CreatedAt)
+	}
+
+	return res
+}
+
+// DBVenuesToProtoList конвертирует срез []*db.Venue в []*eventPb.VenueRes
+func DBVenuesToProtoList(venues []*db.Venue) []*eventPb.VenueRes {
+	if venues == nil {
+		return nil
+	}
+
+	protoVenues := make([]*eventPb.VenueRes, 0, len(venues))
+	for _, venue := range venues {
+		protoVenues = append(protoVenues, DBVenueToProtoVenueRes(venue))
+	}
+
+	return protoVenues
+}
+
+// ============================================================================
+// ЖУРНАЛ ИЗМЕНЕНИЙ - МАППЕРЫ
+// ============================================================================
+
+// EventChangeToProto конвертирует запись журнала изменений в EventChange для gRPC ответа
+func EventChangeToProto(change *db.EventChange) *eventPb.EventChange {
+	return &eventPb.EventChange{
+		Sequence:  change.Seq,
+		Type:      change.Type,
+		EventId:   change.EventID,
+		Event:     DBEventToProtoEventRes(change.Event),
+		ChangedAt: timestamppb.New(change.CreatedAt),
+	}
+}
+
+// ============================================================================
+// ОРГАНИЗАТОРЫ - МАППЕРЫ
+// ============================================================================
+
+// ProtoToCreateOrganizerParams конвертирует CreateOrganizerReq из gRPC в db.CreateOrganizerReq
+func ProtoToCreateOrganizerParams(req *eventPb.CreateOrganizerReq) *db.CreateOrganizerReq {
+	return &db.CreateOrganizerReq{
+		Name:       req.GetName(),
+		Email:      req.GetEmail(),
+		Phone:      req.GetPhone(),
+		Website:    req.GetWebsite(),
+		IsVerified: req.GetIsVerified(),
+	}
+}
+
+// ApplyProtoOrganizerUpdate переносит поля UpdateOrganizerReq в существующего организатора
+func ApplyProtoOrganizerUpdate(organizer *db.Organizer, req *eventPb.UpdateOrganizerReq) {
+	updated := db.NewOrganizer(&db.CreateOrganizerReq{
+		Name:       req.GetName(),
+		Email:      req.GetEmail(),
+		Phone:      req.GetPhone(),
+		Website:    req.GetWebsite(),
+		IsVerified: req.GetIsVerified(),
+	})
+
+	organizer.Name = updated.Name
+	organizer.Email = updated.Email
+	organizer.Phone = updated.Phone
+	organizer.Website = updated.Website
+	organizer.IsVerified = updated.IsVerified
+}
+
+// DBOrganizerToProtoOrganizerRes конвертирует db.Organizer в OrganizerRes для gRPC ответа
+func DBOrganizerToProtoOrganizerRes(organizer *db.Organizer) *eventPb.OrganizerRes {
+	if organizer == nil {
+		return nil
+	}
+
+	res := &eventPb.OrganizerRes{
+		Id:         organizer.Id,
+		Name:       organizer.Name,
+		Email:      organizer.Email,
+		Phone:      organizer.Phone,
+		Website:    organizer.Website,
+		IsVerified: organizer.IsVerified,
+		UpdatedAt:  timeToProtoTimestamp(organizer.UpdatedAt),
+	}
+
+	// У организатора, загруженного вместе с событием, created_at нет
+	if !organizer.CreatedAt.IsZero() {
+		res.CreatedAt = timestamppb.New(organizer.CreatedAt)
+	}
+
+	return res
+}
+
+// DBOrganizersToProtoList конвертирует срез []*db.Organizer в []*eventPb.OrganizerRes
+func DBOrganizersToProtoList(organizers []*db.Organizer) []*eventPb.OrganizerRes {
+	if organizers == nil {
+		return nil
+	}
+
+	protoOrganizers := make([]*eventPb.OrganizerRes, 0, len(organizers))
+	for _, organizer := range organizers {
+		protoOrganizers = append(protoOrganizers, DBOrganizerToProtoOrganizerRes(organizer))
+	}
+
+	return protoOrganizers
+}
+
+// ============================================================================
+// ИСТОЧНИКИ - МАППЕРЫ
+// ============================================================================
+
+// ProtoToCreateSourceParams конвертирует CreateSourceReq из gRPC в db.CreateSourceReq
+func ProtoToCreateSourceParams(req *eventPb.CreateSourceReq) *db.CreateSourceReq {
+	trustLevel := db.DefaultSourceTrustLevel
+	if req.TrustLevel != nil {
+		trustLevel = int(req.GetTrustLevel())
+	}
+
+	return &db.CreateSourceReq{
+		Slug:        req.GetSlug(),
+		DisplayName: req.GetDisplayName(),
+		TrustLevel:  trustLevel,
+	}
+}
+
+// ApplyProtoSourceUpdate переносит поля UpdateSourceReq в существующий источник
+func ApplyProtoSourceUpdate(source *db.Source, req *eventPb.UpdateSourceReq) {
+	source.DisplayName = strings.TrimSpace(req.GetDisplayName())
+	source.TrustLevel = int(req.GetTrustLevel())
+	source.IsActive = req.GetIsActive()
+}
+
+// ProtoToUpsertEvent конвертирует UpsertEventReq в событие источника
+func ProtoToUpsertEvent(req *eventPb.UpsertEventReq, source *db.Source) *db.Event {
+	event := db.NewEventFromCreateRequest(ProtoToCreateEventParams(req.GetEvent()))
+	event.Source = source.Slug
+	event.SourceID = &source.Id
+	event.ExternalID = strings.TrimSpace(req.GetExternalId())
+	return event
+}
+
+// DBSourceToProtoSourceRes конвертирует db.Source в SourceRes для gRPC ответа
+func DBSourceToProtoSourceRes(source *db.Source) *eventPb.SourceRes {
+	if source == nil {
+		return nil
+	}
+
+	return &eventPb.SourceRes{
+		Id:          source.Id,
+		Slug:        source.Slug,
+		DisplayName: source.DisplayName,
+		TrustLevel:  int32(source.TrustLevel),
+		IsActive:    source.IsActive,
+		CreatedAt:   timestamppb.New(source.CreatedAt),
+		UpdatedAt:   timeToProtoTimestamp(source.UpdatedAt),
+	}
+}
+
+// DBSourcesToProtoList конвертирует срез []*db.Source в []*eventPb.SourceRes
+func DBSourcesToProtoList(sources []*db.Source) []*eventPb.SourceRes {
+	protoSources := make([]*eventPb.SourceRes, 0, len(sources))
+	for _, source := range sources {
+		protoSources = append(protoSources, DBSourceToProtoSourceRes(source))
+	}
+
+	return protoSources
+}
+
+// ============================================================================
+// ДУБЛИКАТЫ - МАППЕРЫ
+// ============================================================================
+
+// DuplicateCandidatesToProto конвертирует пары кандидатов в дубликаты в proto
+func DuplicateCandidatesToProto(candidates []dedup.Candidate) []*eventPb.DuplicateCandidate {
+	result := make([]*eventPb.DuplicateCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		result = append(result, &eventPb.DuplicateCandidate{
+			Event:     DBEventToProtoEventRes(candidate.Event),
+			Duplicate: DBEventToProtoEventRes(candidate.Duplicate),
+			Score:     float32(candidate.Score),
+			Reasons:   candidate.Reasons,
+		})
+	}
+	return result
+}
+
 // ============================================================================
 // SUGGESTIONS - МАППЕРЫ ИЗ PROTO В OPENSEARCH
 // ============================================================================
@@ -387,7 +1236,7 @@ func ProtoToSuggestionRequest(req *eventPb.SuggestionReq) *suggestions.Request {
 		return &suggestions.Request{
 			Query:      "",
 			MaxResults: 10,
-			Fields:     []string{"name", "location"},
+			Fields:     []string{"name", "location", "tags"},
 		}
 	}
 
@@ -398,7 +1247,7 @@ func ProtoToSuggestionRequest(req *eventPb.SuggestionReq) *suggestions.Request {
 
 	fields := req.GetFields()
 	if len(fields) == 0 {
-		fields = []string{"name", "location"}
+		fields = []string{"name", "location", "tags"}
 	}
 
 	return &suggestions.Request{
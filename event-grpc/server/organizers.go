@@ -0,0 +1,301 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Ограничения полей организатора (соответствуют колонкам organizers)
+const (
+	maxOrganizerNameLength    = 255
+	maxOrganizerEmailLength   = 255
+	maxOrganizerPhoneLength   = 50
+	maxOrganizerWebsiteLength = 2048
+)
+
+// CreateOrganizer создает нового организатора.
+func (s *Server) CreateOrganizer(ctx context.Context, req *eventPb.CreateOrganizerReq) (*eventPb.OrganizerRes, error) {
+	s.log.Info("starting create organizer",
+		"method", "CreateOrganizer",
+		"organizer_name", req.GetName(),
+		"is_verified", req.GetIsVerified(),
+	)
+
+	organizer := db.NewOrganizer(ProtoToCreateOrganizerParams(req))
+
+	if err := validateOrganizerFields(organizer); err != nil {
+		s.log.Error("invalid create organizer request",
+			"method", "CreateOrganizer",
+			"error", err,
+			"name", req.GetName(),
+		)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.storer.CreateOrganizer(ctx, organizer); err != nil {
+		s.log.Error("failed to create organizer",
+			"method", "CreateOrganizer",
+			"error", err,
+			"organizer_name", req.GetName(),
+		)
+		return nil, wrapError(err)
+	}
+
+	s.log.Info("organizer created successfully",
+		"method", "CreateOrganizer",
+		"organizer_id", organizer.Id,
+		"name", organizer.Name,
+	)
+
+	return DBOrganizerToProtoOrganizerRes(organizer), nil
+}
+
+// GetOrganizer получает организатора по ID.
+func (s *Server) GetOrganizer(ctx context.Context, req *eventPb.GetOrganizerReq) (*eventPb.OrganizerRes, error) {
+	s.log.Info("starting get organizer",
+		"method", "GetOrganizer",
+		"organizer_id", req.GetId(),
+	)
+
+	organizer, err := s.storer.GetOrganizerByID(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get organizer",
+			"method", "GetOrganizer",
+			"organizer_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	return DBOrganizerToProtoOrganizerRes(organizer), nil
+}
+
+// ListOrganizers возвращает список организаторов, опционально только подтвержденных.
+func (s *Server) ListOrganizers(ctx context.Context, req *eventPb.ListOrganizersReq) (*eventPb.ListOrganizersRes, error) {
+	s.log.Info("starting list organizers",
+		"method", "ListOrganizers",
+		"verified_only", req.GetVerifiedOnly(),
+	)
+
+	organizers, err := s.storer.ListOrganizers(ctx, req.GetVerifiedOnly())
+	if err != nil {
+		s.log.Error("failed to list organizers",
+			"method", "ListOrganizers",
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	s.log.Info("organizers retrieved successfully",
+		"count", len(organizers),
+	)
+
+	return &eventPb.ListOrganizersRes{
+		Organizers: DBOrganizersToProtoList(organizers),
+	}, nil
+}
+
+// UpdateOrganizer обновляет организатора и переиндексирует его события в OpenSearch,
+// так как имя организатора хранится в документах событий.
+func (s *Server) UpdateOrganizer(ctx context.Context, req *eventPb.UpdateOrganizerReq) (*eventPb.OrganizerRes, error) {
+	s.log.Info("starting update organizer",
+		"method", "UpdateOrganizer",
+		"organizer_id", req.GetId(),
+	)
+
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid organizer ID")
+	}
+
+	organizer, err := s.storer.GetOrganizerByID(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get organizer for update",
+			"method", "UpdateOrganizer",
+			"organizer_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	ApplyProtoOrganizerUpdate(organizer, req)
+
+	if err := validateOrganizerFields(organizer); err != nil {
+		s.log.Error("invalid update organizer request",
+			"method", "UpdateOrganizer",
+			"organizer_id", req.GetId(),
+			"error", err,
+		)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.storer.UpdateOrganizer(ctx, organizer); err != nil {
+		s.log.Error("failed to update organizer",
+			"method", "UpdateOrganizer",
+			"organizer_id", organizer.Id,
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	s.reindexOrganizerEvents(ctx, organizer.Id)
+
+	s.log.Info("organizer updated successfully",
+		"method", "UpdateOrganizer",
+		"organizer_id", organizer.Id,
+	)
+
+	return DBOrganizerToProtoOrganizerRes(organizer), nil
+}
+
+// DeleteOrganizer удаляет организатора. События остаются, но теряют ссылку на него.
+func (s *Server) DeleteOrganizer(ctx context.Context, req *eventPb.DeleteOrganizerReq) (*emptypb.Empty, error) {
+	s.log.Info("starting delete organizer",
+		"method", "DeleteOrganizer",
+		"organizer_id", req.GetId(),
+	)
+
+	// События организатора запоминаем до удаления: после него organizer_id уже сброшен
+	events, err := s.storer.GetEventsByOrganizer(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get organizer events for deletion",
+			"method", "DeleteOrganizer",
+			"organizer_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	if err := s.storer.DeleteOrganizer(ctx, req.GetId()); err != nil {
+		s.log.Error("failed to delete organizer",
+			"method", "DeleteOrganizer",
+			"organizer_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	if len(events) > 0 {
+		for _, event := range events {
+			event.OrganizerID = nil
+			event.Organizer = nil
+		}
+		if err := s.esService.BulkIndexEvents(ctx, events); err != nil {
+			s.log.Error("failed to reindex organizer events in OpenSearch",
+				"method", "DeleteOrganizer",
+				"organizer_id", req.GetId(),
+				"events_count", len(events),
+				"error", err,
+			)
+			// Не возвращаем ошибку, так как организатор уже удален из PostgreSQL
+		}
+	}
+
+	s.log.Info("organizer deleted successfully",
+		"method", "DeleteOrganizer",
+		"organizer_id", req.GetId(),
+	)
+
+	return &emptypb.Empty{}, nil
+}
+
+// reindexOrganizerEvents переиндексирует события организатора. Ошибки только логируются.
+func (s *Server) reindexOrganizerEvents(ctx context.Context, organizerID int64) {
+	events, err := s.storer.GetEventsByOrganizer(ctx, organizerID)
+	if err != nil {
+		s.log.Error("failed to get organizer events for reindexing",
+			"organizer_id", organizerID,
+			"error", err,
+		)
+		return
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	if err := s.esService.BulkIndexEvents(ctx, events); err != nil {
+		s.log.Error("failed to reindex organizer events in OpenSearch",
+			"organizer_id", organizerID,
+			"events_count", len(events),
+			"error", err,
+		)
+	}
+}
+
+// resolveEventOrganizer загружает организатора события перед сохранением.
+// Несуществующий организатор - ошибка клиента.
+func (s *Server) resolveEventOrganizer(ctx context.Context, event *db.Event) error {
+	if event.OrganizerID == nil {
+		event.Organizer = nil
+		return nil
+	}
+
+	organizer, err := s.storer.GetOrganizerByID(ctx, *event.OrganizerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Errorf(codes.InvalidArgument, "organizer %d not found", *event.OrganizerID)
+		}
+		return wrapError(err)
+	}
+
+	event.Organizer = organizer
+
+	return nil
+}
+
+// validateOrganizerFields проверяет имя и контакты организатора.
+func validateOrganizerFields(organizer *db.Organizer) error {
+	if organizer.Name == "" {
+		return errors.New("organizer name is required")
+	}
+	if utf8.RuneCountInString(organizer.Name) > maxOrganizerNameLength {
+		return fmt.Errorf("organizer name exceeds %d characters", maxOrganizerNameLength)
+	}
+
+	if organizer.Email != "" {
+		if utf8.RuneCountInString(organizer.Email) > maxOrganizerEmailLength {
+			return fmt.Errorf("organizer email exceeds %d characters", maxOrganizerEmailLength)
+		}
+		local, domain, found := strings.Cut(organizer.Email, "@")
+		if !found || local == "" || domain == "" || strings.ContainsAny(organizer.Email, " \t") {
+			return fmt.Errorf("invalid organizer email %q", organizer.Email)
+		}
+	}
+
+	if utf8.RuneCountInString(organizer.Phone) > maxOrganizerPhoneLength {
+		return fmt.Errorf("organizer phone exceeds %d characters", maxOrganizerPhoneLength)
+	}
+
+	if organizer.Website != "" {
+		if len(organizer.Website) > maxOrganizerWebsiteLength {
+			return fmt.Errorf("organizer website exceeds %d characters", maxOrganizerWebsiteLength)
+		}
+		u, err := url.Parse(organizer.Website)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid organizer website %q, expected http(s) URL", organizer.Website)
+		}
+	}
+
+	return nil
+}
+
+// validateOrganizerIDs проверяет ID организаторов в фильтре списка событий.
+func validateOrganizerIDs(ids []int64) error {
+	for _, id := range ids {
+		if id <= 0 {
+			return fmt.Errorf("invalid organizer ID: %d", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
+)
+
+// Хранилища, выдающие токены страниц: их курсоры несовместимы
+const (
+	pageTokenPostgres   = "pg"
+	pageTokenOpenSearch = "os"
+)
+
+// errInvalidPageToken возвращается для поврежденного или чужого токена страницы
+var errInvalidPageToken = errors.New("invalid page_token")
+
+// pageToken содержимое непрозрачного токена страницы ListEvents.
+// Токен действует только с той сортировкой, с которой выдан.
+type pageToken struct {
+	Backend string `json:"b"`
+	Sort    string `json:"s"` // db.SortSignature сортировки страницы
+
+	// Курсор PostgreSQL: значения ключей сортировки и id последнего события страницы
+	Values []any `json:"v,omitempty"`
+	ID     int64 `json:"i,omitempty"`
+
+	// Курсор OpenSearch: снимок индекса и значения сортировки последнего документа
+	PitID       string `json:"p,omitempty"`
+	SearchAfter []any  `json:"a,omitempty"`
+}
+
+// encodePageToken сериализует токен в base64url. Пустая строка означает последнюю страницу.
+func encodePageToken(token *pageToken) string {
+	if token == nil {
+		return ""
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken разбирает токен и проверяет, что его выдало хранилище backend
+// для той же сортировки.
+func decodePageToken(raw, backend string, sorts []db.EventSort) (*pageToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+
+	token := new(pageToken)
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, errInvalidPageToken
+	}
+	if token.Backend != backend || token.Sort != db.SortSignature(sorts) {
+		return nil, errInvalidPageToken
+	}
+	return token, nil
+}
+
+// postgresPageToken формирует токен следующей страницы PostgreSQL, nil на последней.
+func postgresPageToken(cursor *db.EventCursor, sorts []db.EventSort) *pageToken {
+	if cursor == nil {
+		return nil
+	}
+	return &pageToken{
+		Backend: pageTokenPostgres,
+		Sort:    db.SortSignature(sorts),
+		Values:  cursor.Values,
+		ID:      cursor.ID,
+	}
+}
+
+// openSearchPageToken формирует токен следующей страницы OpenSearch, nil на последней.
+func openSearchPageToken(cursor *models.SearchCursor, sorts []db.EventSort) *pageToken {
+	if cursor == nil {
+		return nil
+	}
+	return &pageToken{
+		Backend:     pageTokenOpenSearch,
+		Sort:        db.SortSignature(sorts),
+		PitID:       cursor.PitID,
+		SearchAfter: cursor.SearchAfter,
+	}
+}
+
+// eventCursor возвращает курсор PostgreSQL из токена.
+func (t *pageToken) eventCursor(sorts []db.EventSort) (db.EventCursor, error) {
+	cursor, err := db.NewEventCursor(sorts, t.Values, t.ID)
+	if err != nil {
+		return db.EventCursor{}, errInvalidPageToken
+	}
+	return cursor, nil
+}
+
+// searchCursor возвращает курсор OpenSearch из токена.
+func (t *pageToken) searchCursor() (*models.SearchCursor, error) {
+	if len(t.SearchAfter) == 0 {
+		return nil, errInvalidPageToken
+	}
+	return &models.SearchCursor{PitID: t.PitID, SearchAfter: t.SearchAfter}, nil
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
+)
+
+func TestPostgresPageTokenRoundTrip(t *testing.T) {
+	sorts := []db.EventSort{{Field: db.SortFieldStartsAt}, {Field: db.SortFieldPrice}}
+	startsAt := time.Date(2026, 7, 1, 18, 30, 0, 0, time.UTC)
+	cursor := &db.EventCursor{Values: []any{startsAt, nil}, ID: 42}
+
+	raw := encodePageToken(postgresPageToken(cursor, sorts))
+	if raw == "" {
+		t.Fatal("encodePageToken returned an empty token")
+	}
+
+	token, err := decodePageToken(raw, pageTokenPostgres, sorts)
+	if err != nil {
+		t.Fatalf("decodePageToken: %v", err)
+	}
+	got, err := token.eventCursor(sorts)
+	if err != nil {
+		t.Fatalf("eventCursor: %v", err)
+	}
+
+	if got.ID != cursor.ID {
+		t.Errorf("ID = %d, want %d", got.ID, cursor.ID)
+	}
+	if value, ok := got.Values[0].(time.Time); !ok || !value.Equal(startsAt) {
+		t.Errorf("Values[0] = %v, want %v", got.Values[0], startsAt)
+	}
+	if got.Values[1] != nil {
+		t.Errorf("Values[1] = %v, want nil", got.Values[1])
+	}
+}
+
+func TestOpenSearchPageTokenRoundTrip(t *testing.T) {
+	sorts := []db.EventSort{{Field: db.SortFieldRelevance, Descending: true}}
+	cursor := &models.SearchCursor{PitID: "pit-1", SearchAfter: []any{1.5, "concert", float64(42)}}
+
+	raw := encodePageToken(openSearchPageToken(cursor, sorts))
+	token, err := decodePageToken(raw, pageTokenOpenSearch, sorts)
+	if err != nil {
+		t.Fatalf("decodePageToken: %v", err)
+	}
+	got, err := token.searchCursor()
+	if err != nil {
+		t.Fatalf("searchCursor: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, cursor) {
+		t.Errorf("searchCursor() = %+v, want %+v", got, cursor)
+	}
+}
+
+func TestLastPageHasNoToken(t *testing.T) {
+	sorts := []db.EventSort{{Field: db.SortFieldCreatedAt, Descending: true}}
+
+	if raw := encodePageToken(postgresPageToken(nil, sorts)); raw != "" {
+		t.Errorf("postgres token for the last page = %q, want empty", raw)
+	}
+	if raw := encodePageToken(openSearchPageToken(nil, sorts)); raw != "" {
+		t.Errorf("OpenSearch token for the last page = %q, want empty", raw)
+	}
+}
+
+func TestDecodePageTokenRejectsInvalid(t *testing.T) {
+	sorts := []db.EventSort{{Field: db.SortFieldCreatedAt, Descending: true}}
+	postgres := encodePageToken(postgresPageToken(&db.EventCursor{Values: []any{time.Now()}, ID: 1}, sorts))
+
+	tests := []struct {
+		name    string
+		raw     string
+		backend string
+	}{
+		{"not base64", "%%%", pageTokenPostgres},
+		{"not json", "bm90LWpzb24", pageTokenPostgres},
+		{"other backend", postgres, pageTokenOpenSearch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodePageToken(tt.raw, tt.backend, sorts); !errors.Is(err, errInvalidPageToken) {
+				t.Errorf("decodePageToken() error = %v, want errInvalidPageToken", err)
+			}
+		})
+	}
+}
+
+func TestPageTokenCursorValidation(t *testing.T) {
+	sorts := []db.EventSort{{Field: db.SortFieldName}}
+
+	tests := []struct {
+		name  string
+		token pageToken
+	}{
+		{"missing id", pageToken{Values: []any{"a"}}},
+		{"too few values", pageToken{ID: 1}},
+		{"wrong value type", pageToken{Values: []any{float64(1)}, ID: 1}},
+		{"empty value of a required key", pageToken{Values: []any{nil}, ID: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.token.eventCursor(sorts); !errors.Is(err, errInvalidPageToken) {
+				t.Errorf("eventCursor() error = %v, want errInvalidPageToken", err)
+			}
+		})
+	}
+
+	if _, err := (&pageToken{PitID: "pit-1"}).searchCursor(); !errors.Is(err, errInvalidPageToken) {
+		t.Errorf("searchCursor() without search_after error = %v, want errInvalidPageToken", err)
+	}
+}
+
+func TestDecodePageTokenRejectsOtherSort(t *testing.T) {
+	issued := []db.EventSort{{Field: db.SortFieldPrice}, {Field: db.SortFieldName}}
+	raw := encodePageToken(postgresPageToken(&db.EventCursor{Values: []any{float32(10), "a"}, ID: 3}, issued))
+
+	tests := []struct {
+		name  string
+		sorts []db.EventSort
+	}{
+		{"direction changed", []db.EventSort{{Field: db.SortFieldPrice, Descending: true}, {Field: db.SortFieldName}}},
+		{"keys reordered", []db.EventSort{{Field: db.SortFieldName}, {Field: db.SortFieldPrice}}},
+		{"key dropped", []db.EventSort{{Field: db.SortFieldPrice}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodePageToken(raw, pageTokenPostgres, tt.sorts); !errors.Is(err, errInvalidPageToken) {
+				t.Errorf("decodePageToken() error = %v, want errInvalidPageToken", err)
+			}
+		})
+	}
+
+	if _, err := decodePageToken(raw, pageTokenPostgres, issued); err != nil {
+		t.Errorf("decodePageToken() with the issuing sort: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultRevisionsLimit = 20
+	maxRevisionsLimit     = 100
+)
+
+// ListEventRevisions возвращает историю изменений события, начиная с последней ревизии.
+func (s *Server) ListEventRevisions(ctx context.Context, req *eventPb.ListEventRevisionsReq) (*eventPb.ListEventRevisionsRes, error) {
+	s.log.Info("starting list event revisions",
+		"method", "ListEventRevisions",
+		"event_id", req.GetEventId(),
+		"limit", req.GetLimit(),
+		"offset", req.GetOffset(),
+	)
+
+	if req.GetEventId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event ID")
+	}
+
+	limit := int(req.GetLimit())
+	offset := int(req.GetOffset())
+	if limit < 0 || limit > maxRevisionsLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxRevisionsLimit)
+	}
+	if offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset cannot be negative")
+	}
+	if limit == 0 {
+		limit = defaultRevisionsLimit
+	}
+
+	revisions, total, err := s.storer.ListEventRevisions(ctx, req.GetEventId(), limit, offset)
+	if err != nil {
+		s.log.Error("failed to list event revisions",
+			"method", "ListEventRevisions",
+			"event_id", req.GetEventId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	response := &eventPb.ListEventRevisionsRes{
+		Revisions:  make([]*eventPb.EventRevision, 0, len(revisions)),
+		Pagination: CreatePaginationMeta(total, limit, offset),
+	}
+	for _, revision := range revisions {
+		response.Revisions = append(response.Revisions, DBEventRevisionToProto(revision))
+	}
+
+	return response, nil
+}
+
+// GetEventRevisionDiff возвращает изменения полей между двумя ревизиями события.
+// Без from_revision ревизия сравнивается с предыдущей.
+func (s *Server) GetEventRevisionDiff(ctx context.Context, req *eventPb.GetEventRevisionDiffReq) (*eventPb.EventRevisionDiffRes, error) {
+	s.log.Info("starting get event revision diff",
+		"method", "GetEventRevisionDiff",
+		"event_id", req.GetEventId(),
+		"from_revision", req.GetFromRevision(),
+		"to_revision", req.GetToRevision(),
+	)
+
+	if req.GetEventId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event ID")
+	}
+	if req.GetToRevision() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "to_revision must be positive")
+	}
+
+	fromRevision := req.GetToRevision() - 1
+	if req.FromRevision != nil {
+		fromRevision = req.GetFromRevision()
+	}
+	if fromRevision < 0 {
+		return nil, status.Error(codes.InvalidArgument, "from_revision cannot be negative")
+	}
+
+	to, err := s.storer.GetEventRevision(ctx, req.GetEventId(), int(req.GetToRevision()))
+	if err != nil {
+		s.log.Error("failed to get event revision",
+			"method", "GetEventRevisionDiff",
+			"event_id", req.GetEventId(),
+			"revision", req.GetToRevision(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	// Ревизия 0 означает пустое событие: diff показывает все заполненные поля
+	var fromSnapshot *db.EventSnapshot
+	if fromRevision > 0 {
+		from, err := s.storer.GetEventRevision(ctx, req.GetEventId(), int(fromRevision))
+		if err != nil {
+			s.log.Error("failed to get event revision",
+				"method", "GetEventRevisionDiff",
+				"event_id", req.GetEventId(),
+				"revision", fromRevision,
+				"error", err,
+			)
+			return nil, wrapError(err)
+		}
+		fromSnapshot = &from.Snapshot
+	}
+
+	changes, err := db.DiffEventSnapshots(fromSnapshot, &to.Snapshot)
+	if err != nil {
+		s.log.Error("failed to diff event revisions",
+			"method", "GetEventRevisionDiff",
+			"event_id", req.GetEventId(),
+			"error", err,
+		)
+		return nil, status.Error(codes.Internal, "failed to compare revisions")
+	}
+
+	return &eventPb.EventRevisionDiffRes{
+		EventId:      req.GetEventId(),
+		FromRevision: fromRevision,
+		ToRevision:   req.GetToRevision(),
+		Changes:      FieldChangesToProto(changes),
+	}, nil
+}
+
+// RevertEventToRevision возвращает содержимое события к состоянию ревизии
+// и переиндексирует его. Статус события не меняется.
+func (s *Server) RevertEventToRevision(ctx context.Context, req *eventPb.RevertEventToRevisionReq) (*eventPb.EventRes, error) {
+	s.log.Info("starting revert event to revision",
+		"method", "RevertEventToRevision",
+		"event_id", req.GetEventId(),
+		"revision", req.GetRevision(),
+	)
+
+	if req.GetEventId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event ID")
+	}
+	if req.GetRevision() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "revision must be positive")
+	}
+
+	event, err := s.storer.RevertEventToRevision(ctx, req.GetEventId(), int(req.GetRevision()))
+	if err != nil {
+		s.log.Error("failed to revert event",
+			"method", "RevertEventToRevision",
+			"event_id", req.GetEventId(),
+			"revision", req.GetRevision(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	// Билетные категории ревизии могли поменять доступность билетов
+	s.syncSoldOutStatus(ctx, "RevertEventToRevision", event)
+
+	if err := s.esService.UpdateEvent(ctx, event); err != nil {
+		s.log.Error("failed to reindex reverted event in OpenSearch",
+			"method", "RevertEventToRevision",
+			"event_id", event.Id,
+			"error", err,
+		)
+		// Не возвращаем ошибку, так как событие уже сохранено в PostgreSQL
+	}
+
+	s.log.Info("event reverted successfully",
+		"method", "RevertEventToRevision",
+		"event_id", event.Id,
+		"revision", req.GetRevision(),
+	)
+
+	return DBEventToProtoEventRes(event), nil
+}
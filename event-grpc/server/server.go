@@ -3,15 +3,26 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
 	"github.com/rx3lixir/event-service/internal/db"
 	"github.com/rx3lixir/event-service/internal/opensearch"
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
+	"github.com/rx3lixir/event-service/internal/opensearch/search"
+	"github.com/rx3lixir/event-service/internal/recurrence"
 	"github.com/rx3lixir/event-service/pkg/logger"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Server struct {
@@ -97,19 +108,11 @@ func (s *Server) CreateEvent(ctx context.Context, req *eventPb.CreateEventReq) (
 		"event_name", req.GetName(),
 	)
 
-	// Валидация запроса
-	if err := validateCreateEventReq(req); err != nil {
-		s.log.Error("invalid create event request",
-			"method", "CreateEvent",
-			"error", err,
-			"name", req.GetName(),
-		)
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+	dbEventToCreate, err := s.prepareCreateEvent(ctx, "CreateEvent", req)
+	if err != nil {
+		return nil, err
 	}
 
-	params := ProtoToCreateEventParams(req)
-	dbEventToCreate := db.NewEventFromCreateRequest(params)
-
 	// Создаем событие в PostgreSQL
 	createdEvent, err := s.storer.CreateEvent(ctx, dbEventToCreate)
 	if err != nil {
@@ -121,6 +124,8 @@ func (s *Server) CreateEvent(ctx context.Context, req *eventPb.CreateEventReq) (
 		return nil, wrapError(err)
 	}
 
+	s.syncSoldOutStatus(ctx, "CreateEvent", createdEvent)
+
 	// Индексируем событие в OpenSearch
 	if err := s.esService.IndexEvent(ctx, createdEvent); err != nil {
 		s.log.Error("failed to index event in OpenSearch",
@@ -142,14 +147,55 @@ func (s *Server) CreateEvent(ctx context.Context, req *eventPb.CreateEventReq) (
 	return DBEventToProtoEventRes(createdEvent), nil
 }
 
-// GetEvent получает событие по ID.
+// prepareCreateEvent проверяет запрос на создание и собирает событие, загружая его площадку
+// и организатора. Возвращает gRPC ошибку.
+func (s *Server) prepareCreateEvent(ctx context.Context, method string, req *eventPb.CreateEventReq) (*db.Event, error) {
+	// Валидация запроса
+	if err := validateCreateEventReq(req); err != nil {
+		s.log.Error("invalid create event request",
+			"method", method,
+			"error", err,
+			"name", req.GetName(),
+		)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	event := db.NewEventFromCreateRequest(ProtoToCreateEventParams(req))
+
+	if err := s.resolveEventVenue(ctx, event); err != nil {
+		s.log.Error("failed to resolve event venue",
+			"method", method,
+			"venue_id", req.GetVenueId(),
+			"error", err,
+		)
+		return nil, err
+	}
+
+	if err := s.resolveEventOrganizer(ctx, event); err != nil {
+		s.log.Error("failed to resolve event organizer",
+			"method", method,
+			"organizer_id", req.GetOrganizerId(),
+			"error", err,
+		)
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// GetEvent получает событие по ID. Для ID объединенного дубликата возвращает каноническое событие.
 func (s *Server) GetEvent(ctx context.Context, req *eventPb.GetEventReq) (*eventPb.EventRes, error) {
 	s.log.Info("starting get event",
 		"method", "GetEvent",
 		"event_id", req.GetId(),
+		"locale", req.GetLocale(),
 	)
 
-	event, err := s.storer.GetEventByID(ctx, req.GetId())
+	if err := validateRequestLocale(req.GetLocale()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	event, err := s.getEventOrRedirect(ctx, req.GetId())
 	if err != nil {
 		s.log.Error("failed to get event",
 			"method", "GetEvent",
@@ -159,17 +205,30 @@ func (s *Server) GetEvent(ctx context.Context, req *eventPb.GetEventReq) (*event
 		return nil, wrapError(err)
 	}
 
+	// Черновики и снятые с публикации события видны только административным клиентам
+	if !isPrivileged(ctx) && !db.IsPublicStatus(event.Status) {
+		s.log.Debug("unpublished event hidden",
+			"method", "GetEvent",
+			"event_id", event.Id,
+			"status", event.Status,
+		)
+		return nil, status.Error(codes.NotFound, "resource not found")
+	}
+
 	s.log.Debug("event retrieved successfully",
 		"method", "GetEvent",
 		"event_id", event.Id,
 		"name", event.Name,
 	)
 
-	return DBEventToProtoEventRes(event), nil
+	res := DBEventToProtoEventRes(event)
+	LocalizeEventRes(res, req.GetLocale())
+
+	return res, nil
 }
 
 // ListEvents получает список событий.
-// Если есть поисковый запрос (search_text), использует OpenSearch. Иначе использует PostgreSQL с фильтрами.
+// Если есть поисковый запрос (search_text) или гео-фильтры, использует OpenSearch. Иначе использует PostgreSQL с фильтрами.
 func (s *Server) ListEvents(ctx context.Context, req *eventPb.ListEventsReq) (*eventPb.ListEventsRes, error) {
 	s.log.Info("starting list events",
 		"method", "ListEvents",
@@ -180,24 +239,62 @@ func (s *Server) ListEvents(ctx context.Context, req *eventPb.ListEventsReq) (*e
 		"max_price", req.GetMaxPrice(),
 		"date_from", req.GetDateFrom(),
 		"date_to", req.GetDateTo(),
+		"timezone", req.GetTimezone(),
 		"location", req.GetLocation(),
 		"source", req.GetSource(),
+		"tags_any", req.GetTagsAny(),
+		"tags_all", req.GetTagsAll(),
+		"organizer_ids", req.GetOrganizerIds(),
 		"limit", req.GetLimit(),
 		"offset", req.GetOffset(),
+		"has_page_token", req.PageToken != nil,
 		"include_count", req.GetIncludeCount(),
+		"has_near", req.GetNear() != nil,
+		"has_bbox", req.GetBbox() != nil,
+		"sort_by_distance", req.GetSortByDistance(),
+		"sort", len(req.GetSort()),
+		"has_facets", req.GetFacets() != nil,
+		"venue_ids", req.GetVenueIds(),
+		"cities", req.GetCities(),
+		"locale", req.GetLocale(),
 	)
 
-	// Если есть поисковый запрос, используем OpenSearch
-	if req.SearchText != nil && req.GetSearchText() != "" {
-		return s.searchEventsWithElasticsearch(ctx, req)
+	if err := validateRequestLocale(req.GetLocale()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateOrganizerIDs(req.GetOrganizerIds()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := authorizeIncludeUnpublished(ctx, req.GetIncludeUnpublished()); err != nil {
+		return nil, err
+	}
+	facetReq, err := ProtoToFacetRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	// Иначе используем PostgreSQL
-	return s.listEventsWithPostgreSQL(ctx, req)
+	var res *eventPb.ListEventsRes
+	if (req.SearchText != nil && req.GetSearchText() != "") || hasGeoFilters(req) {
+		// Если есть поисковый запрос или гео-фильтры, используем OpenSearch
+		res, err = s.searchEventsWithElasticsearch(ctx, req, facetReq)
+	} else {
+		// Иначе используем PostgreSQL
+		res, err = s.listEventsWithPostgreSQL(ctx, req, facetReq)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range res.GetEvents() {
+		LocalizeEventRes(event, req.GetLocale())
+	}
+
+	return res, nil
 }
 
-// searchEventsWithElasticsearch выполняет поиск через OpenSearch
-func (s *Server) searchEventsWithElasticsearch(ctx context.Context, req *eventPb.ListEventsReq) (*eventPb.ListEventsRes, error) {
+// searchEventsWithElasticsearch выполняет поиск через OpenSearch.
+// Фасеты считаются агрегациями в том же запросе.
+func (s *Server) searchEventsWithElasticsearch(ctx context.Context, req *eventPb.ListEventsReq, facetReq *db.FacetRequest) (*eventPb.ListEventsRes, error) {
 	s.log.Debug("using OpenSearch for search",
 		"search_text", req.GetSearchText(),
 	)
@@ -212,9 +309,20 @@ func (s *Server) searchEventsWithElasticsearch(ctx context.Context, req *eventPb
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if err := s.expandSearchCategories(ctx, "ListEvents", filter); err != nil {
+		return nil, err
+	}
+
+	if facetReq != nil {
+		filter.WithFacets(facetReq)
+	}
+
 	// Выполняем поиск
 	result, err := s.esService.SearchEvents(ctx, filter)
 	if err != nil {
+		if errors.Is(err, search.ErrCursorExpired) {
+			return nil, status.Error(codes.InvalidArgument, "page_token expired, restart from the first page")
+		}
 		s.log.Error("failed to search events in OpenSearch",
 			"method", "ListEvents",
 			"error", err,
@@ -223,6 +331,20 @@ func (s *Server) searchEventsWithElasticsearch(ctx context.Context, req *eventPb
 		return nil, status.Error(codes.Internal, "search failed")
 	}
 
+	// Разворачиваем найденные серии во вхождения внутри диапазона дат
+	if filter.DateFrom != nil || filter.DateTo != nil {
+		from, to := filter.DateWindow()
+		events, err := s.expandRecurringEvents(ctx, models.ToDBEvents(result.Events), from, to)
+		if err != nil {
+			s.log.Error("failed to expand recurring events",
+				"method", "ListEvents",
+				"error", err,
+			)
+			return nil, wrapError(err)
+		}
+		result.Events = models.FromDBEvents(events)
+	}
+
 	s.log.Info("OpenSearch search completed",
 		"method", "ListEvents",
 		"events_found", result.Total,
@@ -230,11 +352,39 @@ func (s *Server) searchEventsWithElasticsearch(ctx context.Context, req *eventPb
 		"search_time", result.SearchTime,
 	)
 
-	return OpenSearchResultToListEventsRes(result), nil
+	response := OpenSearchResultToListEventsRes(result)
+	response.NextPageToken = encodePageToken(openSearchPageToken(result.Next, filter.GetSort()))
+	response.Facets = EventFacetsToProto(result.Facets)
+
+	return response, nil
+}
+
+// expandSearchCategories дополняет фильтр OpenSearch потомками категорий:
+// родительская категория включает всех потомков.
+func (s *Server) expandSearchCategories(ctx context.Context, method string, filter *search.Filter) error {
+	if len(filter.CategoryIDs) == 0 {
+		return nil
+	}
+
+	categoryIDs, err := s.storer.GetCategoryDescendantIDs(ctx, filter.CategoryIDs)
+	if err != nil {
+		s.log.Error("failed to expand category descendants",
+			"method", method,
+			"category_ids", filter.CategoryIDs,
+			"error", err,
+		)
+		return wrapError(err)
+	}
+	// Несуществующие категории оставляем как есть, иначе фильтр пропадет
+	if len(categoryIDs) > 0 {
+		filter.WithCategories(categoryIDs...)
+	}
+	return nil
 }
 
-// listEventsWithPostgreSQL выполняет запрос через PostgreSQL
-func (s *Server) listEventsWithPostgreSQL(ctx context.Context, req *eventPb.ListEventsReq) (*eventPb.ListEventsRes, error) {
+// listEventsWithPostgreSQL выполняет запрос через PostgreSQL.
+// Фасеты считаются отдельными GROUP BY запросами.
+func (s *Server) listEventsWithPostgreSQL(ctx context.Context, req *eventPb.ListEventsReq, facetReq *db.FacetRequest) (*eventPb.ListEventsRes, error) {
 	s.log.Debug("using PostgreSQL for filtered list")
 
 	// Конвертируем в фильтр PostgreSQL
@@ -303,6 +453,23 @@ func (s *Server) listEventsWithPostgreSQL(ctx context.Context, req *eventPb.List
 		)
 	}
 
+	// Курсор берется до разворачивания: страницы считаются по сериям
+	nextCursor := filter.NextCursor(events)
+
+	// Разворачиваем серии во вхождения внутри диапазона дат.
+	// Пагинация при этом считается по сериям, а не по вхождениям.
+	if filter.HasDateRange() {
+		from, to := filter.DateWindow()
+		events, err = s.expandRecurringEvents(ctx, events, from, to)
+		if err != nil {
+			s.log.Error("failed to expand recurring events",
+				"method", "ListEvents",
+				"error", err,
+			)
+			return nil, wrapError(err)
+		}
+	}
+
 	// Конвертируем результат в gRPC ответ
 	response := EventsToListEventsRes(
 		events,
@@ -310,52 +477,146 @@ func (s *Server) listEventsWithPostgreSQL(ctx context.Context, req *eventPb.List
 		filter.GetLimit(),
 		filter.GetOffset(),
 	)
+	response.NextPageToken = encodePageToken(postgresPageToken(nextCursor, filter.GetSort()))
+
+	if facetReq != nil {
+		if response.Facets, err = s.listEventFacets(ctx, filter, facetReq); err != nil {
+			return nil, err
+		}
+	}
 
 	return response, nil
 }
 
-// UpdateEvent обновляет существующее событие в PostgreSQL и OpenSearch
-func (s *Server) UpdateEvent(ctx context.Context, req *eventPb.UpdateEventReq) (*eventPb.EventRes, error) {
-	s.log.Info("starting update event",
-		"method", "UpdateEvent",
-		"event_id", req.GetId(),
+// expandRecurringEvents заменяет повторяющиеся события их вхождениями в диапазоне [from, to).
+// Разовые события остаются на своих местах.
+func (s *Server) expandRecurringEvents(ctx context.Context, events []*db.Event, from, to time.Time) ([]*db.Event, error) {
+	var recurringIDs []int64
+	for _, event := range events {
+		if event.IsRecurring() {
+			recurringIDs = append(recurringIDs, event.Id)
+		}
+	}
+	if len(recurringIDs) == 0 {
+		return events, nil
+	}
+
+	overrides, err := s.storer.GetOccurrenceOverrides(ctx, recurringIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := make([]*db.Event, 0, len(events))
+	for _, event := range events {
+		if !event.IsRecurring() {
+			expanded = append(expanded, event)
+			continue
+		}
+
+		occurrences, err := db.ExpandOccurrences(event, overrides[event.Id], from, to)
+		if err != nil {
+			// Некорректное правило не должно ломать весь список
+			s.log.Warn("failed to expand recurring event",
+				"event_id", event.Id,
+				"recurrence_rule", event.RecurrenceRule,
+				"error", err,
+			)
+			expanded = append(expanded, event)
+			continue
+		}
+		expanded = append(expanded, occurrences...)
+	}
+
+	return expanded, nil
+}
+
+// UpdateEventOccurrence изменяет или отменяет одно вхождение повторяющегося события.
+// Серия и ее индекс в OpenSearch не меняются.
+func (s *Server) UpdateEventOccurrence(ctx context.Context, req *eventPb.UpdateEventOccurrenceReq) (*eventPb.EventRes, error) {
+	s.log.Info("starting update event occurrence",
+		"method", "UpdateEventOccurrence",
+		"event_id", req.GetEventId(),
+		"occurrence_start", req.GetOccurrenceStart().AsTime(),
+		"cancelled", req.GetCancelled(),
 	)
 
-	// Валидация запроса
-	if err := validateUpdateEventReq(req); err != nil {
-		s.log.Error("invalid update event request",
-			"method", "UpdateEvent",
-			"event_id", req.GetId(),
+	if err := validateUpdateEventOccurrenceReq(req); err != nil {
+		s.log.Error("invalid update event occurrence request",
+			"method", "UpdateEventOccurrence",
+			"event_id", req.GetEventId(),
 			"error", err,
 		)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	// Получаем ID и параметры обновления
-	id, updateParams := ProtoToUpdateEventParams(req)
+	event, err := s.storer.GetEventByID(ctx, req.GetEventId())
+	if err != nil {
+		s.log.Error("failed to get event for occurrence update",
+			"method", "UpdateEventOccurrence",
+			"event_id", req.GetEventId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
 
-	// Получаем текущее событие
-	currentEvent, err := s.storer.GetEventByID(ctx, id)
+	set, err := event.RecurrenceSet()
 	if err != nil {
-		s.log.Error("failed to get event for update",
-			"method", "UpdateEvent",
-			"event_id", id,
+		s.log.Error("failed to parse event recurrence",
+			"method", "UpdateEventOccurrence",
+			"event_id", event.Id,
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+	if set == nil {
+		return nil, status.Error(codes.FailedPrecondition, "event is not recurring")
+	}
+
+	override := ProtoToOccurrenceOverride(req)
+	if !set.Includes(override.OccurrenceStart.In(event.TimeLocation())) {
+		return nil, status.Error(codes.InvalidArgument, "occurrence_start does not match any occurrence of the event")
+	}
+
+	if err := s.storer.UpsertOccurrenceOverride(ctx, override); err != nil {
+		s.log.Error("failed to save occurrence override",
+			"method", "UpdateEventOccurrence",
+			"event_id", event.Id,
 			"error", err,
 		)
 		return nil, wrapError(err)
 	}
 
-	// Применяем обновления
-	currentEvent.ApplyUpdate(updateParams)
+	s.log.Info("event occurrence updated successfully",
+		"method", "UpdateEventOccurrence",
+		"event_id", event.Id,
+		"occurrence_start", override.OccurrenceStart,
+	)
+
+	return DBEventToProtoEventRes(event.Occurrence(override.OccurrenceStart, override)), nil
+}
+
+// UpdateEvent обновляет существующее событие в PostgreSQL и OpenSearch
+func (s *Server) UpdateEvent(ctx context.Context, req *eventPb.UpdateEventReq) (*eventPb.EventRes, error) {
+	s.log.Info("starting update event",
+		"method", "UpdateEvent",
+		"event_id", req.GetId(),
+	)
+
+	currentEvent, fields, err := s.prepareEventUpdate(ctx, "UpdateEvent", req)
+	if err != nil {
+		return nil, err
+	}
 
-	// Обновляем в PostgreSQL
-	updatedEvent, err := s.storer.UpdateEvent(ctx, currentEvent)
+	// Обновляем в PostgreSQL только поля из маски
+	updatedEvent, err := s.storer.UpdateEventFields(ctx, currentEvent, fields)
 	if err != nil {
-		s.log.Error("Failed to update event in PostgreSQL", "id", id, "error", err)
+		s.log.Error("Failed to update event in PostgreSQL", "id", req.GetId(), "error", err)
 		return nil, wrapError(err)
 	}
 
-	// Обновляем в OpenSearch
+	s.syncSoldOutStatus(ctx, "UpdateEvent", updatedEvent)
+
+	// Документ в OpenSearch переиндексируется целиком с новой версией
 	if err := s.esService.UpdateEvent(ctx, updatedEvent); err != nil {
 		s.log.Error("failed to update event in OpenSearch",
 			"method", "UpdateEvent",
@@ -373,6 +634,76 @@ func (s *Server) UpdateEvent(ctx context.Context, req *eventPb.UpdateEventReq) (
 	return DBEventToProtoEventRes(updatedEvent), nil
 }
 
+// prepareEventUpdate проверяет запрос на обновление и применяет его к текущему состоянию события.
+// Возвращает измененное событие с версией из запроса и маску полей. Возвращает gRPC ошибку.
+func (s *Server) prepareEventUpdate(ctx context.Context, method string, req *eventPb.UpdateEventReq) (*db.Event, []string, error) {
+	// Валидация запроса
+	if err := validateUpdateEventReq(req); err != nil {
+		s.log.Error("invalid update event request",
+			"method", method,
+			"event_id", req.GetId(),
+			"error", err,
+		)
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Получаем ID и параметры обновления
+	id, updateParams := ProtoToUpdateEventParams(req)
+
+	// Получаем текущее событие
+	event, err := s.storer.GetEventByID(ctx, id)
+	if err != nil {
+		s.log.Error("failed to get event for update",
+			"method", method,
+			"event_id", id,
+			"error", err,
+		)
+		return nil, nil, wrapError(err)
+	}
+
+	// Применяем обновления. Версия из запроса сверяется с текущей при записи
+	if err := event.ApplyUpdate(updateParams); err != nil {
+		s.log.Error("invalid event update",
+			"method", method,
+			"event_id", id,
+			"error", err,
+		)
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	event.Version = req.GetVersion()
+
+	if err := validateUpdatedEventSchedule(event); err != nil {
+		s.log.Error("invalid updated event schedule",
+			"method", method,
+			"event_id", id,
+			"error", err,
+		)
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.resolveEventVenue(ctx, event); err != nil {
+		s.log.Error("failed to resolve event venue",
+			"method", method,
+			"event_id", id,
+			"venue_id", req.GetVenueId(),
+			"error", err,
+		)
+		return nil, nil, err
+	}
+
+	if err := s.resolveEventOrganizer(ctx, event); err != nil {
+		s.log.Error("failed to resolve event organizer",
+			"method", method,
+			"event_id", id,
+			"organizer_id", req.GetOrganizerId(),
+			"error", err,
+		)
+		return nil, nil, err
+	}
+
+	return event, updateParams.Fields, nil
+}
+
 // DeleteEvent удаляет событие из PostgreSQL и OpenSearch
 func (s *Server) DeleteEvent(ctx context.Context, req *eventPb.DeleteEventReq) (*emptypb.Empty, error) {
 	s.log.Info("starting delete event",
@@ -429,6 +760,15 @@ func (s *Server) CreateCategory(ctx context.Context, req *eventPb.CreateCategory
 	params := ProtoToCreateCategoryParams(req)
 	categoryToCreate := db.NewCategory(params)
 
+	if err := s.checkCategoryParent(ctx, categoryToCreate.ParentID); err != nil {
+		s.log.Error("invalid category parent",
+			"method", "CreateCategory",
+			"error", err,
+			"parent_id", req.GetParentId(),
+		)
+		return nil, err
+	}
+
 	err := s.storer.CreateCategory(ctx, categoryToCreate)
 	if err != nil {
 		s.log.Error("failed to create category",
@@ -444,7 +784,19 @@ func (s *Server) CreateCategory(ctx context.Context, req *eventPb.CreateCategory
 		"category_id", categoryToCreate.Id,
 		"name", categoryToCreate.Name,
 	)
-	return DBCategoryToProtoCategoryRes(categoryToCreate), nil
+
+	// Перечитываем категорию, чтобы вернуть вычисленные path и depth
+	created, err := s.storer.GetCategoryByID(ctx, categoryToCreate.Id)
+	if err != nil {
+		s.log.Warn("failed to reload created category",
+			"method", "CreateCategory",
+			"category_id", categoryToCreate.Id,
+			"error", err,
+		)
+		return DBCategoryToProtoCategoryRes(categoryToCreate), nil
+	}
+
+	return DBCategoryToProtoCategoryRes(created), nil
 }
 
 // GetCategory получает категорию по ID.
@@ -473,10 +825,12 @@ func (s *Server) GetCategory(ctx context.Context, req *eventPb.GetCategoryReq) (
 	return DBCategoryToProtoCategoryRes(category), nil
 }
 
-// ListCategories возвращает список всех категорий.
+// ListCategories возвращает список всех категорий, упорядоченный по пути.
+// С as_tree возвращаются только корни с вложенными потомками.
 func (s *Server) ListCategories(ctx context.Context, req *eventPb.ListCategoriesReq) (*eventPb.ListCategoriesRes, error) {
 	s.log.Info("starting list categories",
 		"method", "ListCategories",
+		"as_tree", req.GetAsTree(),
 	)
 
 	categories, err := s.storer.ListCategories(ctx)
@@ -492,6 +846,10 @@ func (s *Server) ListCategories(ctx context.Context, req *eventPb.ListCategories
 		"count", len(categories),
 	)
 
+	if req.GetAsTree() {
+		categories = db.BuildCategoryTree(categories)
+	}
+
 	protoCategories := DBCategoriesToProtoList(categories)
 	return &eventPb.ListCategoriesRes{
 		Categories: protoCategories,
@@ -529,16 +887,33 @@ func (s *Server) UpdateCategory(ctx context.Context, req *eventPb.UpdateCategory
 		return nil, wrapError(err)
 	}
 
-	// Обновляем имя
-	currentCategory.Name = name
+	// Обновляем имя и родителя. Версия из запроса сверяется с текущей при записи
+	fields, _ := ProtoToCategoryUpdateFields(req.GetUpdateMask())
+	if db.InMask(fields, db.CategoryFieldName) {
+		currentCategory.Name = name
+	}
+	currentCategory.Version = req.GetVersion()
+	ApplyProtoCategoryParent(currentCategory, req)
+
+	if err := s.checkCategoryParent(ctx, currentCategory.ParentID); err != nil {
+		s.log.Error("invalid category parent",
+			"method", "UpdateCategory",
+			"category_id", id,
+			"error", err,
+		)
+		return nil, err
+	}
 
-	// Обновляем в базе
+	// Обновляем в базе (здесь же проверяется отсутствие циклов)
 	err = s.storer.UpdateCategory(ctx, currentCategory)
 	if err != nil {
 		s.log.Error("failed to update category",
 			"id", id,
 			"error", err,
 		)
+		if errors.Is(err, db.ErrCategoryCycle) {
+			return nil, status.Error(codes.InvalidArgument, db.ErrCategoryCycle.Error())
+		}
 		return nil, wrapError(err)
 	}
 
@@ -547,7 +922,18 @@ func (s *Server) UpdateCategory(ctx context.Context, req *eventPb.UpdateCategory
 		"category_id", currentCategory.Id,
 	)
 
-	return DBCategoryToProtoCategoryRes(currentCategory), nil
+	// Путь меняется у всего поддерева, перечитываем актуальные path и depth
+	updated, err := s.storer.GetCategoryByID(ctx, currentCategory.Id)
+	if err != nil {
+		s.log.Warn("failed to reload updated category",
+			"method", "UpdateCategory",
+			"category_id", currentCategory.Id,
+			"error", err,
+		)
+		return DBCategoryToProtoCategoryRes(currentCategory), nil
+	}
+
+	return DBCategoryToProtoCategoryRes(updated), nil
 }
 
 // DeleteCategory удаляет категорию по ID.
@@ -576,6 +962,9 @@ func (s *Server) DeleteCategory(ctx context.Context, req *eventPb.DeleteCategory
 			"category_id", req.GetId(),
 			"error", err,
 		)
+		if errors.Is(err, db.ErrCategoryInUse) {
+			return nil, status.Error(codes.FailedPrecondition, db.ErrCategoryInUse.Error())
+		}
 		return nil, wrapError(err)
 	}
 
@@ -587,11 +976,30 @@ func (s *Server) DeleteCategory(ctx context.Context, req *eventPb.DeleteCategory
 	return &emptypb.Empty{}, nil
 }
 
+// checkCategoryParent проверяет, что родительская категория существует.
+func (s *Server) checkCategoryParent(ctx context.Context, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+
+	if _, err := s.storer.GetCategoryByID(ctx, *parentID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Errorf(codes.InvalidArgument, "parent category %d not found", *parentID)
+		}
+		return wrapError(err)
+	}
+
+	return nil
+}
+
 // validateCreateCategoryReq проверяет корректность запроса на создание категории.
 func validateCreateCategoryReq(req *eventPb.CreateCategoryReq) error {
 	if req.GetName() == "" {
 		return errors.New("category name is required")
 	}
+	if req.ParentId != nil && req.GetParentId() <= 0 {
+		return errors.New("invalid parent category ID")
+	}
 	return nil
 }
 
@@ -600,20 +1008,54 @@ func validateUpdateCategoryReq(req *eventPb.UpdateCategoryReq) error {
 	if req.GetId() <= 0 {
 		return errors.New("invalid category ID")
 	}
-	if req.GetName() == "" {
+	fields, err := ProtoToCategoryUpdateFields(req.GetUpdateMask())
+	if err != nil {
+		return err
+	}
+	if db.InMask(fields, db.CategoryFieldName) && req.GetName() == "" {
 		return errors.New("category name is required")
 	}
+	if req.GetVersion() <= 0 {
+		return errors.New("category version is required")
+	}
+	if req.GetParentId() < 0 {
+		return errors.New("invalid parent category ID")
+	}
+	if req.GetParentId() == req.GetId() {
+		return errors.New("category cannot be its own parent")
+	}
 	return nil
 }
 
+const (
+	// pgUniqueViolation SQLSTATE нарушения уникальности
+	pgUniqueViolation = "23505"
+	// pgForeignKeyViolation SQLSTATE нарушения внешнего ключа
+	pgForeignKeyViolation = "23503"
+)
+
 // wrapError преобразует ошибки БД в gRPC ошибки со статусами.
 func wrapError(err error) error {
 	if errors.Is(err, pgx.ErrNoRows) {
 		return status.Error(codes.NotFound, "resource not found")
 	}
 
-	// Здесь можно добавить обработку других специфичных для PostgreSQL ошибок
-	// Например, нарушение unique constraint, foreign key constraint и т.д.
+	// Запись изменили с момента чтения: клиент должен перечитать ее и повторить
+	var conflictErr *db.VersionConflictError
+	if errors.As(err, &conflictErr) {
+		return status.Errorf(codes.Aborted, "version mismatch: current version is %d", conflictErr.Current)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return status.Error(codes.AlreadyExists, "resource already exists")
+		case pgForeignKeyViolation:
+			// Например, удаление категории, у которой есть подкатегории
+			return status.Error(codes.FailedPrecondition, "resource is referenced by other resources")
+		}
+	}
 
 	return status.Error(codes.Internal, "internal server error")
 }
@@ -624,9 +1066,35 @@ func validateCreateEventReq(req *eventPb.CreateEventReq) error {
 		return errors.New("event name is required")
 	}
 
+	if err := validateEventSchedule(req.GetStartsAt(), req.GetEndsAt(), req.GetTimezone()); err != nil {
+		return err
+	}
+
+	if err := validateRecurrence(req.GetRecurrenceRule(), req.GetStartsAt() != nil || req.GetDate() != ""); err != nil {
+		return err
+	}
+
+	if err := validateTags(req.GetTags()); err != nil {
+		return err
+	}
+
+	if len(req.GetImage()) > db.MaxImageURLLength {
+		return fmt.Errorf("image exceeds %d characters", db.MaxImageURLLength)
+	}
+
+	if err := validateTranslations(req.GetTranslations()); err != nil {
+		return err
+	}
+
+	if err := validateTicketTiers(req.GetTicketTiers()); err != nil {
+		return err
+	}
+
+	if err := validatePublishAt(req); err != nil {
+		return err
+	}
+
 	// Здесь можно добавить другие проверки
-	// - Формат даты
-	// - Формат времени
 	// - Валидность категории
 	// и т.д.
 
@@ -639,11 +1107,300 @@ func validateUpdateEventReq(req *eventPb.UpdateEventReq) error {
 		return errors.New("invalid event ID")
 	}
 
-	if req.GetName() == "" {
+	if req.GetVersion() <= 0 {
+		return errors.New("event version is required")
+	}
+
+	// Проверяются только поля из маски, пустая маска означает все поля
+	fields, err := ProtoToEventUpdateFields(req.GetUpdateMask())
+	if err != nil {
+		return err
+	}
+	has := func(field string) bool { return db.InMask(fields, field) }
+
+	if has(db.EventFieldName) && req.GetName() == "" {
 		return errors.New("event name is required")
 	}
 
-	// Другие проверки, как и в validateCreateEventReq
+	// Согласованность начала, окончания и серии проверяется по событию после применения
+	// маски (validateUpdatedEventSchedule): в маске может быть только одно из полей
+	if has(db.EventFieldStartsAt) || has(db.EventFieldEndsAt) || has(db.EventFieldTimezone) {
+		if err := validateScheduleValues(req.GetStartsAt(), req.GetEndsAt(), req.GetTimezone()); err != nil {
+			return err
+		}
+	}
+
+	if has(db.EventFieldRecurrenceRule) {
+		if err := validateRecurrence(req.GetRecurrenceRule(), true); err != nil {
+			return err
+		}
+	}
+
+	if has(db.EventFieldTags) {
+		if err := validateTags(req.GetTags()); err != nil {
+			return err
+		}
+	}
+
+	if has(db.EventFieldImage) && len(req.GetImage()) > db.MaxImageURLLength {
+		return fmt.Errorf("image exceeds %d characters", db.MaxImageURLLength)
+	}
+
+	if has(db.EventFieldTranslations) {
+		if err := validateTranslations(req.GetTranslations()); err != nil {
+			return err
+		}
+	}
+
+	if has(db.EventFieldTicketTiers) {
+		if err := validateTicketTiers(req.GetTicketTiers()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// maxEventTags максимальное количество тегов у события
+const maxEventTags = 20
+
+// validateTags проверяет количество и длину тегов.
+func validateTags(tags []string) error {
+	normalized := db.NormalizeTags(tags)
+	if len(normalized) > maxEventTags {
+		return fmt.Errorf("too many tags: maximum %d, got: %d", maxEventTags, len(normalized))
+	}
+
+	for _, tag := range normalized {
+		if utf8.RuneCountInString(tag) > db.MaxTagLength {
+			return fmt.Errorf("tag %q exceeds %d characters", tag, db.MaxTagLength)
+		}
+	}
+
+	return nil
+}
 
+// maxEventTranslations максимальное количество переводов у события
+const maxEventTranslations = 20
+
+// validateTranslations проверяет теги языков и длину переведенных названий.
+// Перевод на язык по умолчанию не принимается: это содержимое самого события.
+func validateTranslations(translations []*eventPb.EventTranslation) error {
+	if len(translations) > maxEventTranslations {
+		return fmt.Errorf("too many translations: maximum %d, got: %d", maxEventTranslations, len(translations))
+	}
+
+	seen := make(map[string]bool, len(translations))
+	for _, translation := range translations {
+		locale := db.NormalizeLocale(translation.GetLocale())
+		if !db.IsValidLocale(locale) {
+			return fmt.Errorf("invalid translation locale %q, expected language tag like en or en-GB", translation.GetLocale())
+		}
+		if db.LocaleLanguage(locale) == db.DefaultLocale {
+			return fmt.Errorf("translation locale %q: content in %s belongs to the event itself", locale, db.DefaultLocale)
+		}
+		if seen[locale] {
+			return fmt.Errorf("duplicate translation locale %q", locale)
+		}
+		seen[locale] = true
+
+		if strings.TrimSpace(translation.GetName()) == "" && strings.TrimSpace(translation.GetDescription()) == "" {
+			return fmt.Errorf("translation %q: name or description is required", locale)
+		}
+		if utf8.RuneCountInString(translation.GetName()) > db.MaxTranslationNameLength {
+			return fmt.Errorf("translation %q: name exceeds %d characters", locale, db.MaxTranslationNameLength)
+		}
+	}
+
+	return nil
+}
+
+// validateRequestLocale проверяет язык содержимого из запроса. Пустой язык допустим.
+func validateRequestLocale(locale string) error {
+	if locale != "" && !db.IsValidLocale(db.NormalizeLocale(locale)) {
+		return fmt.Errorf("invalid locale %q, expected language tag like en or en-GB", locale)
+	}
+	return nil
+}
+
+// validatePublishAt проверяет момент публикации нового события.
+func validatePublishAt(req *eventPb.CreateEventReq) error {
+	if req.GetPublishAt() == nil {
+		return nil
+	}
+	if err := req.GetPublishAt().CheckValid(); err != nil {
+		return fmt.Errorf("invalid publish_at: %w", err)
+	}
+	return nil
+}
+
+// maxTicketTiers максимальное количество билетных категорий у события
+const maxTicketTiers = 20
+
+// maxPurchaseURLLength максимальная длина ссылки на покупку (соответствует event_ticket_tiers.purchase_url)
+const maxPurchaseURLLength = 2048
+
+// validateTicketTiers проверяет билетные категории. Все категории события
+// должны быть в одной валюте, иначе диапазон цен не имеет смысла.
+func validateTicketTiers(tiers []*eventPb.TicketTier) error {
+	if len(tiers) > maxTicketTiers {
+		return fmt.Errorf("too many ticket tiers: maximum %d, got: %d", maxTicketTiers, len(tiers))
+	}
+
+	var currency string
+	for i, tier := range db.NormalizeTicketTiers(ProtoToTicketTiers(tiers)) {
+		if tier.Name == "" {
+			return fmt.Errorf("ticket tier %d: name is required", i)
+		}
+		if utf8.RuneCountInString(tier.Name) > db.MaxTicketTierNameLength {
+			return fmt.Errorf("ticket tier %d: name exceeds %d characters", i, db.MaxTicketTierNameLength)
+		}
+		if tier.MinPrice < 0 || math.IsNaN(float64(tier.MinPrice)) {
+			return fmt.Errorf("ticket tier %d: min_price cannot be negative", i)
+		}
+		if tier.MaxPrice != nil && (*tier.MaxPrice < tier.MinPrice || math.IsNaN(float64(*tier.MaxPrice))) {
+			return fmt.Errorf("ticket tier %d: max_price cannot be less than min_price", i)
+		}
+		if !isCurrencyCode(tier.Currency) {
+			return fmt.Errorf("ticket tier %d: invalid currency %q, expected ISO 4217 code", i, tier.Currency)
+		}
+		if currency == "" {
+			currency = tier.Currency
+		} else if tier.Currency != currency {
+			return fmt.Errorf("ticket tier %d: currency %s differs from %s, all tiers must use one currency", i, tier.Currency, currency)
+		}
+		if !db.IsValidTicketAvailability(tier.Availability) {
+			return fmt.Errorf("ticket tier %d: invalid availability %q", i, tier.Availability)
+		}
+		if err := validatePurchaseURL(tier.PurchaseURL); err != nil {
+			return fmt.Errorf("ticket tier %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// isCurrencyCode проверяет, что строка похожа на код валюты ISO 4217 (три латинские буквы)
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// validatePurchaseURL проверяет ссылку на покупку билетов. Пустая ссылка допустима.
+func validatePurchaseURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	if len(raw) > maxPurchaseURLLength {
+		return fmt.Errorf("purchase_url exceeds %d characters", maxPurchaseURLLength)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid purchase_url %q, expected http(s) URL", raw)
+	}
+
+	return nil
+}
+
+// validateRecurrence проверяет правило повторения. Серии нужна точка отсчета (starts_at или date).
+func validateRecurrence(rule string, hasStart bool) error {
+	if rule == "" {
+		return nil
+	}
+
+	if !hasStart {
+		return errors.New("recurrence_rule requires starts_at")
+	}
+
+	if _, err := recurrence.Parse(rule, time.UTC); err != nil {
+		return fmt.Errorf("invalid recurrence_rule: %w", err)
+	}
+
+	return nil
+}
+
+// validateUpdateEventOccurrenceReq проверяет корректность запроса на изменение вхождения.
+func validateUpdateEventOccurrenceReq(req *eventPb.UpdateEventOccurrenceReq) error {
+	if req.GetEventId() <= 0 {
+		return errors.New("invalid event ID")
+	}
+
+	if req.GetOccurrenceStart() == nil {
+		return errors.New("occurrence_start is required")
+	}
+	if err := req.GetOccurrenceStart().CheckValid(); err != nil {
+		return fmt.Errorf("invalid occurrence_start: %w", err)
+	}
+
+	if req.GetEndsAt() != nil && req.GetStartsAt() == nil {
+		// Без нового начала окончание сравнивается с исходным началом вхождения
+		if req.GetEndsAt().AsTime().Before(req.GetOccurrenceStart().AsTime()) {
+			return errors.New("ends_at cannot be before occurrence start")
+		}
+		return nil
+	}
+
+	return validateEventSchedule(req.GetStartsAt(), req.GetEndsAt(), "")
+}
+
+// validateEventSchedule проверяет время начала/окончания и часовой пояс события.
+func validateEventSchedule(startsAt, endsAt *timestamppb.Timestamp, timezone string) error {
+	if err := validateScheduleValues(startsAt, endsAt, timezone); err != nil {
+		return err
+	}
+
+	if endsAt != nil {
+		if startsAt == nil {
+			return errors.New("ends_at requires starts_at")
+		}
+		if endsAt.AsTime().Before(startsAt.AsTime()) {
+			return errors.New("ends_at cannot be before starts_at")
+		}
+	}
+
+	return nil
+}
+
+// validateScheduleValues проверяет значения начала, окончания и часового пояса
+// по отдельности, без их согласованности.
+func validateScheduleValues(startsAt, endsAt *timestamppb.Timestamp, timezone string) error {
+	if startsAt != nil {
+		if err := startsAt.CheckValid(); err != nil {
+			return fmt.Errorf("invalid starts_at: %w", err)
+		}
+	}
+
+	if endsAt != nil {
+		if err := endsAt.CheckValid(); err != nil {
+			return fmt.Errorf("invalid ends_at: %w", err)
+		}
+	}
+
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %s", timezone)
+		}
+	}
+
+	return nil
+}
+
+// validateUpdatedEventSchedule проверяет расписание события после применения частичного
+// обновления: поля не из маски берутся из сохраненного события.
+func validateUpdatedEventSchedule(event *db.Event) error {
+	if err := validateEventSchedule(timeToProtoTimestamp(event.StartsAt), timeToProtoTimestamp(event.EndsAt), event.Timezone); err != nil {
+		return err
+	}
+	if event.IsRecurring() && event.StartsAt == nil {
+		return errors.New("recurrence_rule requires starts_at")
+	}
 	return nil
 }
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
+	"github.com/rx3lixir/event-service/internal/opensearch/search"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Количество рекомендаций
+const (
+	defaultSimilarLimit = 10
+	maxSimilarLimit     = 50
+
+	// maxRelatedRounds ограничивает добор по категории и цене, когда часть
+	// подобранных серий выпадает без предстоящих вхождений
+	maxRelatedRounds = 3
+)
+
+// similarTextFields поля, по которым рекомендации сравниваются с событием
+var similarTextFields = []string{"name", "description"}
+
+// GetSimilarEvents подбирает события, похожие на событие event_id, для блока
+// "вам может понравиться". Сначала ищет по тексту через more_like_this с усилением той же
+// категории и близких дат, отбрасывая слабые совпадения ниже порога оценки. Недостающие
+// места заполняет подбором по категории и цене.
+func (s *Server) GetSimilarEvents(ctx context.Context, req *eventPb.GetSimilarEventsReq) (*eventPb.GetSimilarEventsRes, error) {
+	s.log.Info("starting get similar events",
+		"method", "GetSimilarEvents",
+		"event_id", req.GetEventId(),
+		"limit", req.GetLimit(),
+		"category_ids", req.GetCategoryIDs(),
+		"date_from", req.GetDateFrom(),
+		"date_to", req.GetDateTo(),
+		"venue_ids", req.GetVenueIds(),
+		"cities", req.GetCities(),
+		"has_near", req.GetNear() != nil,
+		"locale", req.GetLocale(),
+	)
+
+	if req.GetEventId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "event_id must be positive")
+	}
+	if err := validateRequestLocale(req.GetLocale()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateOrganizerIDs(req.GetOrganizerIds()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter, limit, err := ProtoToSimilarFilter(req, time.Now())
+	if err != nil {
+		s.log.Error("invalid similar events parameters",
+			"method", "GetSimilarEvents",
+			"error", err,
+		)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	event, err := s.getEventOrRedirect(ctx, req.GetEventId())
+	if err != nil {
+		s.log.Error("failed to get event",
+			"method", "GetSimilarEvents",
+			"event_id", req.GetEventId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+	if !isPrivileged(ctx) && !db.IsPublicStatus(event.Status) {
+		return nil, status.Error(codes.NotFound, "resource not found")
+	}
+
+	if err := s.expandSearchCategories(ctx, "GetSimilarEvents", filter); err != nil {
+		return nil, err
+	}
+
+	similar, err := s.esService.FindSimilarEvents(ctx, &search.SimilarFilter{
+		EventID:    event.Id,
+		Fields:     similarTextFields,
+		Filter:     filter,
+		CategoryID: event.CategoryID,
+		StartsNear: event.StartsAt,
+		MinScore:   search.DefaultSimilarMinScore,
+		Size:       limit,
+	})
+	if err != nil {
+		s.log.Error("failed to find similar events in OpenSearch",
+			"method", "GetSimilarEvents",
+			"event_id", event.Id,
+			"error", err,
+		)
+		return nil, status.Error(codes.Internal, "search failed")
+	}
+
+	// Серия показывается ближайшим вхождением, а не первым
+	events, err := s.upcomingOccurrences(ctx, models.ToDBEvents(similar.Events), *filter.DateFrom)
+	if err != nil {
+		s.log.Error("failed to expand recurring events",
+			"method", "GetSimilarEvents",
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	// Слабые текстовые совпадения отсечены порогом оценки: недостающие места добираем
+	// по категории и цене. Серии без предстоящих вхождений выпадают при разворачивании,
+	// поэтому размер добора считается по уже развернутой выдаче
+	excludeIDs := append(documentIDs(similar.Events), event.Id)
+	related := 0
+	for round := 0; len(events) < limit && round < maxRelatedRounds; round++ {
+		size := limit - len(events)
+		result, err := s.esService.FindRelatedEvents(ctx, &search.RelatedFilter{
+			Filter:     filter,
+			ExcludeIDs: excludeIDs,
+			CategoryID: event.CategoryID,
+			PriceFrom:  event.PriceFrom,
+			Size:       size,
+		})
+		if err != nil {
+			// Похожие по тексту уже найдены, отдаем хотя бы их
+			s.log.Warn("failed to find related events, returning text matches only",
+				"method", "GetSimilarEvents",
+				"event_id", event.Id,
+				"error", err,
+			)
+			break
+		}
+
+		upcoming, err := s.upcomingOccurrences(ctx, models.ToDBEvents(result.Events), *filter.DateFrom)
+		if err != nil {
+			s.log.Error("failed to expand recurring events",
+				"method", "GetSimilarEvents",
+				"error", err,
+			)
+			return nil, wrapError(err)
+		}
+		related += len(upcoming)
+		events = append(events, upcoming...)
+
+		// Подходящих событий больше нет
+		if len(result.Events) < size {
+			break
+		}
+		excludeIDs = append(excludeIDs, documentIDs(result.Events)...)
+	}
+
+	s.log.Info("similar events found",
+		"method", "GetSimilarEvents",
+		"event_id", event.Id,
+		"similar", len(similar.Events),
+		"related", related,
+		"returned", len(events),
+	)
+
+	response := &eventPb.GetSimilarEventsRes{
+		Events: OpenSearchEventsToProtoEventsList(models.FromDBEvents(events)),
+	}
+	for _, res := range response.GetEvents() {
+		LocalizeEventRes(res, req.GetLocale())
+	}
+
+	return response, nil
+}
+
+// upcomingOccurrences заменяет серии их ближайшим вхождением, идущим или начинающимся после from.
+// Серии без таких вхождений пропускаются, разовые события остаются на своих местах.
+func (s *Server) upcomingOccurrences(ctx context.Context, events []*db.Event, from time.Time) ([]*db.Event, error) {
+	expanded, err := s.expandRecurringEvents(ctx, events, from, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Вхождения серии идут подряд по возрастанию начала
+	seen := make(map[int64]bool, len(events))
+	upcoming := make([]*db.Event, 0, len(events))
+	for _, event := range expanded {
+		if seen[event.Id] {
+			continue
+		}
+		seen[event.Id] = true
+		upcoming = append(upcoming, event)
+	}
+	return upcoming, nil
+}
+
+// ProtoToSimilarFilter разбирает фильтры рекомендаций и их количество. Фильтры разбираются
+// так же, как в ListEvents, но выдача всегда ограничена событиями, которые еще не прошли
+// к моменту now и не отменены.
+func ProtoToSimilarFilter(req *eventPb.GetSimilarEventsReq, now time.Time) (*search.Filter, int, error) {
+	limit := defaultSimilarLimit
+	if req.Limit != nil {
+		if req.GetLimit() <= 0 || req.GetLimit() > maxSimilarLimit {
+			return nil, 0, fmt.Errorf("limit must be in [1, %d], got: %d", maxSimilarLimit, req.GetLimit())
+		}
+		limit = int(req.GetLimit())
+	}
+
+	filter, err := ProtoToOpenSearchFilter(&eventPb.ListEventsReq{
+		CategoryIDs:  req.GetCategoryIDs(),
+		MinPrice:     req.MinPrice,
+		MaxPrice:     req.MaxPrice,
+		IsFree:       req.IsFree,
+		DateFrom:     req.DateFrom,
+		DateTo:       req.DateTo,
+		Timezone:     req.Timezone,
+		TagsAny:      req.GetTagsAny(),
+		OrganizerIds: req.GetOrganizerIds(),
+		VenueIds:     req.GetVenueIds(),
+		Cities:       req.GetCities(),
+		Near:         req.GetNear(),
+		Locale:       req.Locale,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if filter.DateFrom == nil || filter.DateFrom.Before(now) {
+		filter.DateFrom = &now
+	}
+	filter.WithStatuses(slices.DeleteFunc(db.PublicStatuses(), func(status string) bool {
+		return status == db.StatusCancelled
+	})...)
+
+	return filter, limit, nil
+}
@@ -0,0 +1,290 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Ограничения полей источника, совпадают с колонками sources и events.external_id
+const (
+	maxSourceSlugLength = 64
+	maxSourceNameLength = 255
+	maxExternalIDLength = 255
+	maxSourceTrustLevel = 100
+)
+
+// CreateSource регистрирует новый внешний источник событий.
+func (s *Server) CreateSource(ctx context.Context, req *eventPb.CreateSourceReq) (*eventPb.SourceRes, error) {
+	s.log.Info("starting create source",
+		"method", "CreateSource",
+		"slug", req.GetSlug(),
+	)
+
+	params := ProtoToCreateSourceParams(req)
+	if err := validateSourceFields(strings.TrimSpace(params.DisplayName), params.TrustLevel); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateSourceSlug(db.NormalizeSourceSlug(params.Slug)); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	source := db.NewSource(params)
+
+	if err := s.storer.CreateSource(ctx, source); err != nil {
+		s.log.Error("failed to create source",
+			"method", "CreateSource",
+			"slug", source.Slug,
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	s.log.Info("source created successfully",
+		"method", "CreateSource",
+		"source_id", source.Id,
+		"slug", source.Slug,
+	)
+
+	return DBSourceToProtoSourceRes(source), nil
+}
+
+// GetSource получает источник по ID.
+func (s *Server) GetSource(ctx context.Context, req *eventPb.GetSourceReq) (*eventPb.SourceRes, error) {
+	source, err := s.storer.GetSourceByID(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get source",
+			"method", "GetSource",
+			"source_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	return DBSourceToProtoSourceRes(source), nil
+}
+
+// ListSources возвращает список источников, опционально только активных.
+func (s *Server) ListSources(ctx context.Context, req *eventPb.ListSourcesReq) (*eventPb.ListSourcesRes, error) {
+	sources, err := s.storer.ListSources(ctx, req.GetActiveOnly())
+	if err != nil {
+		s.log.Error("failed to list sources",
+			"method", "ListSources",
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	return &eventPb.ListSourcesRes{
+		Sources: DBSourcesToProtoList(sources),
+	}, nil
+}
+
+// UpdateSource обновляет имя, уровень доверия и активность источника.
+// События отключенного источника остаются, но новые импорты отклоняются.
+func (s *Server) UpdateSource(ctx context.Context, req *eventPb.UpdateSourceReq) (*eventPb.SourceRes, error) {
+	s.log.Info("starting update source",
+		"method", "UpdateSource",
+		"source_id", req.GetId(),
+	)
+
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid source ID")
+	}
+	if err := validateSourceFields(strings.TrimSpace(req.GetDisplayName()), int(req.GetTrustLevel())); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	source, err := s.storer.GetSourceByID(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get source for update",
+			"method", "UpdateSource",
+			"source_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	ApplyProtoSourceUpdate(source, req)
+
+	if err := s.storer.UpdateSource(ctx, source); err != nil {
+		s.log.Error("failed to update source",
+			"method", "UpdateSource",
+			"source_id", source.Id,
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	s.log.Info("source updated successfully",
+		"method", "UpdateSource",
+		"source_id", source.Id,
+		"is_active", source.IsActive,
+	)
+
+	return DBSourceToProtoSourceRes(source), nil
+}
+
+// UpsertEvent импортирует событие из внешнего источника: создает его при первом импорте
+// external_id и обновляет при повторных. Индекс OpenSearch обновляется, только если событие изменилось.
+func (s *Server) UpsertEvent(ctx context.Context, req *eventPb.UpsertEventReq) (*eventPb.UpsertEventRes, error) {
+	s.log.Info("starting upsert event",
+		"method", "UpsertEvent",
+		"source", req.GetSource(),
+		"external_id", req.GetExternalId(),
+	)
+
+	if err := validateUpsertEventReq(req); err != nil {
+		s.log.Error("invalid upsert event request",
+			"method", "UpsertEvent",
+			"source", req.GetSource(),
+			"external_id", req.GetExternalId(),
+			"error", err,
+		)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	source, err := s.storer.GetSourceBySlug(ctx, req.GetSource())
+	if err != nil {
+		s.log.Error("failed to get source for upsert",
+			"method", "UpsertEvent",
+			"source", req.GetSource(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+	if !source.IsActive {
+		return nil, status.Errorf(codes.FailedPrecondition, "source %q is inactive", source.Slug)
+	}
+
+	event := ProtoToUpsertEvent(req, source)
+
+	if err := s.resolveEventVenue(ctx, event); err != nil {
+		s.log.Error("failed to resolve event venue",
+			"method", "UpsertEvent",
+			"venue_id", req.GetEvent().GetVenueId(),
+			"error", err,
+		)
+		return nil, err
+	}
+
+	if err := s.resolveEventOrganizer(ctx, event); err != nil {
+		s.log.Error("failed to resolve event organizer",
+			"method", "UpsertEvent",
+			"organizer_id", req.GetEvent().GetOrganizerId(),
+			"error", err,
+		)
+		return nil, err
+	}
+
+	saved, result, err := s.storer.UpsertEvent(ctx, event)
+	if err != nil {
+		s.log.Error("failed to upsert event in PostgreSQL",
+			"method", "UpsertEvent",
+			"source", source.Slug,
+			"external_id", event.ExternalID,
+			"error", err,
+		)
+		if errors.Is(err, db.ErrExternalEventDeleted) {
+			return nil, status.Error(codes.FailedPrecondition, "event was deleted and is not re-imported")
+		}
+		return nil, wrapError(err)
+	}
+
+	switch result {
+	case db.UpsertCreated:
+		s.syncSoldOutStatus(ctx, "UpsertEvent", saved)
+		if err := s.esService.IndexEvent(ctx, saved); err != nil {
+			s.log.Error("failed to index event in OpenSearch",
+				"method", "UpsertEvent",
+				"event_id", saved.Id,
+				"error", err,
+			)
+			// Не возвращаем ошибку, так как событие уже сохранено в PostgreSQL
+		}
+	case db.UpsertUpdated:
+		s.syncSoldOutStatus(ctx, "UpsertEvent", saved)
+		if err := s.esService.UpdateEvent(ctx, saved); err != nil {
+			s.log.Error("failed to update event in OpenSearch",
+				"method", "UpsertEvent",
+				"event_id", saved.Id,
+				"error", err,
+			)
+		}
+	}
+
+	s.log.Info("event upserted successfully",
+		"method", "UpsertEvent",
+		"event_id", saved.Id,
+		"source", source.Slug,
+		"external_id", saved.ExternalID,
+		"result", result,
+	)
+
+	return &eventPb.UpsertEventRes{
+		Event:  DBEventToProtoEventRes(saved),
+		Result: result,
+	}, nil
+}
+
+// validateUpsertEventReq проверяет источник, внешний ID и данные импортируемого события.
+func validateUpsertEventReq(req *eventPb.UpsertEventReq) error {
+	if strings.TrimSpace(req.GetSource()) == "" {
+		return errors.New("source is required")
+	}
+
+	externalID := strings.TrimSpace(req.GetExternalId())
+	if externalID == "" {
+		return errors.New("external_id is required")
+	}
+	if utf8.RuneCountInString(externalID) > maxExternalIDLength {
+		return fmt.Errorf("external_id must be at most %d characters", maxExternalIDLength)
+	}
+
+	if req.GetEvent() == nil {
+		return errors.New("event is required")
+	}
+
+	return validateCreateEventReq(req.GetEvent())
+}
+
+// validateSourceSlug проверяет slug источника: латиница в нижнем регистре, цифры, "-" и "_".
+func validateSourceSlug(slug string) error {
+	if slug == "" {
+		return errors.New("source slug is required")
+	}
+	if len(slug) > maxSourceSlugLength {
+		return fmt.Errorf("source slug must be at most %d characters", maxSourceSlugLength)
+	}
+
+	for _, r := range slug {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return fmt.Errorf("source slug may contain only latin letters, digits, '-' and '_', got: %q", slug)
+		}
+	}
+
+	return nil
+}
+
+// validateSourceFields проверяет имя и уровень доверия источника.
+func validateSourceFields(displayName string, trustLevel int) error {
+	if displayName == "" {
+		return errors.New("source display name is required")
+	}
+	if utf8.RuneCountInString(displayName) > maxSourceNameLength {
+		return fmt.Errorf("source display name must be at most %d characters", maxSourceNameLength)
+	}
+
+	if trustLevel < 0 || trustLevel > maxSourceTrustLevel {
+		return fmt.Errorf("trust level must be between 0 and %d, got: %d", maxSourceTrustLevel, trustLevel)
+	}
+
+	return nil
+}
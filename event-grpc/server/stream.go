@@ -0,0 +1,92 @@
+package server
+
+import (
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StreamEvents выгружает все события фильтра потоком. События читаются из PostgreSQL
+// через курсор порциями: следующая порция читается, только когда клиент принял предыдущую.
+func (s *Server) StreamEvents(req *eventPb.ListEventsReq, stream eventPb.EventService_StreamEventsServer) error {
+	ctx := stream.Context()
+
+	s.log.Info("starting stream events",
+		"method", "StreamEvents",
+		"category_ids", req.GetCategoryIDs(),
+		"date_from", req.GetDateFrom(),
+		"date_to", req.GetDateTo(),
+		"tags_any", req.GetTagsAny(),
+		"tags_all", req.GetTagsAll(),
+		"organizer_ids", req.GetOrganizerIds(),
+		"include_unpublished", req.GetIncludeUnpublished(),
+		"locale", req.GetLocale(),
+	)
+
+	if req.GetSearchText() != "" || hasGeoFilters(req) {
+		return status.Error(codes.InvalidArgument, "search_text and geo filters are not supported by StreamEvents")
+	}
+	if err := validateRequestLocale(req.GetLocale()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateOrganizerIDs(req.GetOrganizerIds()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := authorizeIncludeUnpublished(ctx, req.GetIncludeUnpublished()); err != nil {
+		return err
+	}
+
+	filter, err := ProtoToEventFilter(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var sent int
+	var sendErr error
+	err = s.storer.StreamEventsWithFilter(ctx, filter, func(events []*db.Event) error {
+		// Серии разворачиваются во вхождения так же, как в ListEvents
+		if filter.HasDateRange() {
+			from, to := filter.DateWindow()
+			expanded, err := s.expandRecurringEvents(ctx, events, from, to)
+			if err != nil {
+				return err
+			}
+			events = expanded
+		}
+
+		for _, event := range events {
+			res := DBEventToProtoEventRes(event)
+			LocalizeEventRes(res, req.GetLocale())
+
+			// Send блокируется, пока у клиента нет места в окне потока
+			if sendErr = stream.Send(res); sendErr != nil {
+				return sendErr
+			}
+			sent++
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("failed to stream events",
+			"method", "StreamEvents",
+			"sent", sent,
+			"error", err,
+		)
+		if sendErr != nil {
+			return sendErr
+		}
+		// Клиент отключился или истек дедлайн вызова
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return wrapError(err)
+	}
+
+	s.log.Info("events streamed successfully",
+		"method", "StreamEvents",
+		"sent", sent,
+	)
+
+	return nil
+}
@@ -0,0 +1,289 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CreateVenue создает новую площадку.
+func (s *Server) CreateVenue(ctx context.Context, req *eventPb.CreateVenueReq) (*eventPb.VenueRes, error) {
+	s.log.Info("starting create venue",
+		"method", "CreateVenue",
+		"venue_name", req.GetName(),
+		"city", req.GetCity(),
+	)
+
+	if err := validateVenueFields(req.GetName(), req.Latitude, req.Longitude); err != nil {
+		s.log.Error("invalid create venue request",
+			"method", "CreateVenue",
+			"error", err,
+			"name", req.GetName(),
+		)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	venue := db.NewVenue(ProtoToCreateVenueParams(req))
+
+	if err := s.storer.CreateVenue(ctx, venue); err != nil {
+		s.log.Error("failed to create venue",
+			"method", "CreateVenue",
+			"error", err,
+			"venue_name", req.GetName(),
+		)
+		return nil, wrapError(err)
+	}
+
+	s.log.Info("venue created successfully",
+		"method", "CreateVenue",
+		"venue_id", venue.Id,
+		"name", venue.Name,
+	)
+
+	return DBVenueToProtoVenueRes(venue), nil
+}
+
+// GetVenue получает площадку по ID.
+func (s *Server) GetVenue(ctx context.Context, req *eventPb.GetVenueReq) (*eventPb.VenueRes, error) {
+	s.log.Info("starting get venue",
+		"method", "GetVenue",
+		"venue_id", req.GetId(),
+	)
+
+	venue, err := s.storer.GetVenueByID(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get venue",
+			"method", "GetVenue",
+			"venue_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	return DBVenueToProtoVenueRes(venue), nil
+}
+
+// ListVenues возвращает список площадок, опционально по одному городу.
+func (s *Server) ListVenues(ctx context.Context, req *eventPb.ListVenuesReq) (*eventPb.ListVenuesRes, error) {
+	s.log.Info("starting list venues",
+		"method", "ListVenues",
+		"city", req.GetCity(),
+	)
+
+	venues, err := s.storer.ListVenues(ctx, strings.TrimSpace(req.GetCity()))
+	if err != nil {
+		s.log.Error("failed to list venues",
+			"method", "ListVenues",
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	s.log.Info("venues retrieved successfully",
+		"count", len(venues),
+	)
+
+	return &eventPb.ListVenuesRes{
+		Venues: DBVenuesToProtoList(venues),
+	}, nil
+}
+
+// UpdateVenue обновляет площадку и переиндексирует ее события в OpenSearch,
+// так как название, город и координаты площадки хранятся в документах событий.
+func (s *Server) UpdateVenue(ctx context.Context, req *eventPb.UpdateVenueReq) (*eventPb.VenueRes, error) {
+	s.log.Info("starting update venue",
+		"method", "UpdateVenue",
+		"venue_id", req.GetId(),
+	)
+
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid venue ID")
+	}
+	if err := validateVenueFields(req.GetName(), req.Latitude, req.Longitude); err != nil {
+		s.log.Error("invalid update venue request",
+			"method", "UpdateVenue",
+			"venue_id", req.GetId(),
+			"error", err,
+		)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	venue, err := s.storer.GetVenueByID(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get venue for update",
+			"method", "UpdateVenue",
+			"venue_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	ApplyProtoVenueUpdate(venue, req)
+
+	if err := s.storer.UpdateVenue(ctx, venue); err != nil {
+		s.log.Error("failed to update venue",
+			"method", "UpdateVenue",
+			"venue_id", venue.Id,
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	s.reindexVenueEvents(ctx, venue.Id)
+
+	s.log.Info("venue updated successfully",
+		"method", "UpdateVenue",
+		"venue_id", venue.Id,
+	)
+
+	return DBVenueToProtoVenueRes(venue), nil
+}
+
+// DeleteVenue удаляет площадку. События остаются, но теряют ссылку на нее.
+func (s *Server) DeleteVenue(ctx context.Context, req *eventPb.DeleteVenueReq) (*emptypb.Empty, error) {
+	s.log.Info("starting delete venue",
+		"method", "DeleteVenue",
+		"venue_id", req.GetId(),
+	)
+
+	// События площадки запоминаем до удаления: после него venue_id уже сброшен
+	events, err := s.storer.GetEventsByVenue(ctx, req.GetId())
+	if err != nil {
+		s.log.Error("failed to get venue events for deletion",
+			"method", "DeleteVenue",
+			"venue_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	if err := s.storer.DeleteVenue(ctx, req.GetId()); err != nil {
+		s.log.Error("failed to delete venue",
+			"method", "DeleteVenue",
+			"venue_id", req.GetId(),
+			"error", err,
+		)
+		return nil, wrapError(err)
+	}
+
+	if len(events) > 0 {
+		for _, event := range events {
+			event.VenueID = nil
+			event.Venue = nil
+		}
+		if err := s.esService.BulkIndexEvents(ctx, events); err != nil {
+			s.log.Error("failed to reindex venue events in OpenSearch",
+				"method", "DeleteVenue",
+				"venue_id", req.GetId(),
+				"events_count", len(events),
+				"error", err,
+			)
+			// Не возвращаем ошибку, так как площадка уже удалена из PostgreSQL
+		}
+	}
+
+	s.log.Info("venue deleted successfully",
+		"method", "DeleteVenue",
+		"venue_id", req.GetId(),
+	)
+
+	return &emptypb.Empty{}, nil
+}
+
+// reindexVenueEvents переиндексирует события площадки. Ошибки только логируются.
+func (s *Server) reindexVenueEvents(ctx context.Context, venueID int64) {
+	events, err := s.storer.GetEventsByVenue(ctx, venueID)
+	if err != nil {
+		s.log.Error("failed to get venue events for reindexing",
+			"venue_id", venueID,
+			"error", err,
+		)
+		return
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	if err := s.esService.BulkIndexEvents(ctx, events); err != nil {
+		s.log.Error("failed to reindex venue events in OpenSearch",
+			"venue_id", venueID,
+			"events_count", len(events),
+			"error", err,
+		)
+	}
+}
+
+// resolveEventVenue загружает площадку события перед сохранением.
+// Несуществующая площадка - ошибка клиента. Пустой location заполняется названием площадки.
+func (s *Server) resolveEventVenue(ctx context.Context, event *db.Event) error {
+	if event.VenueID == nil {
+		event.Venue = nil
+		return nil
+	}
+
+	venue, err := s.storer.GetVenueByID(ctx, *event.VenueID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Errorf(codes.InvalidArgument, "venue %d not found", *event.VenueID)
+		}
+		return wrapError(err)
+	}
+
+	event.Venue = venue
+	if strings.TrimSpace(event.Location) == "" {
+		event.Location = venue.Name
+	}
+
+	return nil
+}
+
+// maxGeoRadiusKm максимальный радиус гео-поиска (половина длины экватора)
+const maxGeoRadiusKm = 20000.0
+
+// validateVenueIDs проверяет ID площадок в фильтре списка событий.
+func validateVenueIDs(ids []int64) error {
+	for _, id := range ids {
+		if id <= 0 {
+			return fmt.Errorf("invalid venue ID: %d", id)
+		}
+	}
+	return nil
+}
+
+// validateCoordinates проверяет широту и долготу.
+func validateCoordinates(lat, lon float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90, got: %v", lat)
+	}
+	if math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180, got: %v", lon)
+	}
+	return nil
+}
+
+// validateVenueFields проверяет имя и координаты площадки.
+func validateVenueFields(name string, latitude, longitude *float64) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("venue name is required")
+	}
+
+	if (latitude == nil) != (longitude == nil) {
+		return errors.New("latitude and longitude must be set together")
+	}
+
+	if latitude != nil {
+		return validateCoordinates(*latitude, *longitude)
+	}
+
+	return nil
+}
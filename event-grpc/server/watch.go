@@ -0,0 +1,178 @@
+package server
+
+import (
+	"time"
+
+	eventPb "github.com/rx3lixir/event-service/event-grpc/gen/go"
+	"github.com/rx3lixir/event-service/internal/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// watchPollInterval период опроса журнала изменений, когда новых изменений нет
+	watchPollInterval = time.Second
+	// watchBatchSize количество изменений, читаемых из журнала за раз
+	watchBatchSize = 500
+)
+
+// WatchEvents передает изменения событий из журнала event_changes по мере их появления.
+// Клиент продолжает поток после переподключения, передавая sequence последнего полученного изменения.
+// Состояние неопубликованных и удаленных событий передается только администраторам.
+func (s *Server) WatchEvents(req *eventPb.WatchEventsReq, stream eventPb.EventService_WatchEventsServer) error {
+	ctx := stream.Context()
+
+	s.log.Info("starting watch events",
+		"method", "WatchEvents",
+		"after_sequence", req.GetAfterSequence(),
+		"has_since", req.GetSince() != nil,
+		"category_ids", req.GetCategoryIDs(),
+		"sources", req.GetSources(),
+	)
+
+	if req.AfterSequence != nil && req.GetSince() != nil {
+		return status.Error(codes.InvalidArgument, "after_sequence and since are mutually exclusive")
+	}
+	if req.GetAfterSequence() < 0 {
+		return status.Error(codes.InvalidArgument, "after_sequence cannot be negative")
+	}
+	for _, id := range req.GetCategoryIDs() {
+		if id <= 0 {
+			return status.Errorf(codes.InvalidArgument, "invalid category ID: %d", id)
+		}
+	}
+
+	filter := &db.EventChangeFilter{
+		Sources: req.GetSources(),
+		Limit:   watchBatchSize,
+	}
+
+	// Родительская категория включает всех потомков, как в ListEvents
+	if len(req.GetCategoryIDs()) > 0 {
+		categoryIDs, err := s.storer.GetCategoryDescendantIDs(ctx, req.GetCategoryIDs())
+		if err != nil {
+			s.log.Error("failed to resolve watched categories",
+				"method", "WatchEvents",
+				"error", err,
+			)
+			return wrapError(err)
+		}
+		filter.CategoryIDs = categoryIDs
+	}
+
+	switch {
+	case req.AfterSequence != nil:
+		// Изменения после after_sequence могли быть удалены очисткой журнала:
+		// продолжать поток с пропуском нельзя, клиенту нужна полная ресинхронизация
+		oldest, err := s.storer.GetOldestEventChangeSeq(ctx)
+		if err != nil {
+			s.log.Error("failed to get oldest event change",
+				"method", "WatchEvents",
+				"error", err,
+			)
+			return wrapError(err)
+		}
+		if oldest > req.GetAfterSequence()+1 {
+			s.log.Warn("watch position is older than the change journal",
+				"method", "WatchEvents",
+				"after_sequence", req.GetAfterSequence(),
+				"oldest_sequence", oldest,
+			)
+			return status.Errorf(codes.OutOfRange,
+				"after_sequence %d is older than the change journal, which starts at %d", req.GetAfterSequence(), oldest)
+		}
+		filter.AfterSeq = req.GetAfterSequence()
+	case req.GetSince() != nil:
+		// Изменения раньше начала журнала удалены очисткой, как и для after_sequence
+		since := req.GetSince().AsTime()
+		oldest, err := s.storer.GetOldestEventChangeTime(ctx)
+		if err != nil {
+			s.log.Error("failed to get oldest event change time",
+				"method", "WatchEvents",
+				"error", err,
+			)
+			return wrapError(err)
+		}
+		if oldest != nil && since.Before(*oldest) {
+			s.log.Warn("watch position is older than the change journal",
+				"method", "WatchEvents",
+				"since", since,
+				"oldest_change_at", *oldest,
+			)
+			return status.Errorf(codes.OutOfRange,
+				"since %s is older than the change journal, which starts at %s",
+				since.Format(time.RFC3339), oldest.Format(time.RFC3339))
+		}
+		filter.Since = &since
+	default:
+		latest, err := s.storer.GetLatestEventChangeSeq(ctx)
+		if err != nil {
+			s.log.Error("failed to get latest event change",
+				"method", "WatchEvents",
+				"error", err,
+			)
+			return wrapError(err)
+		}
+		filter.AfterSeq = latest
+	}
+
+	// Неопубликованные события видны в потоке только администраторам
+	privileged := isPrivileged(ctx)
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		changes, err := s.storer.GetEventChanges(ctx, filter)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			s.log.Error("failed to read event changes",
+				"method", "WatchEvents",
+				"after_sequence", filter.AfterSeq,
+				"error", err,
+			)
+			return wrapError(err)
+		}
+
+		for _, change := range changes {
+			if err := stream.Send(EventChangeToProto(visibleEventChange(change, privileged))); err != nil {
+				return err
+			}
+			filter.AfterSeq = change.Seq
+		}
+
+		// Полная порция: в журнале могут быть еще изменения, читаем без ожидания
+		if len(changes) == watchBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			s.log.Info("watch events finished",
+				"method", "WatchEvents",
+				"last_sequence", filter.AfterSeq,
+			)
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// visibleEventChange возвращает изменение в том виде, в каком его видит подписчик.
+// Для неадминистратора удаление и переход события в неопубликованный статус
+// передаются как удаление без состояния события.
+func visibleEventChange(change *db.EventChange, privileged bool) *db.EventChange {
+	if privileged {
+		return change
+	}
+	if change.Type != db.ChangeDeleted && change.Event != nil && db.IsPublicStatus(change.Event.Status) {
+		return change
+	}
+
+	tombstone := *change
+	tombstone.Type = db.ChangeDeleted
+	tombstone.Event = nil
+	return &tombstone
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/rx3lixir/event-service/internal/db"
+)
+
+func TestVisibleEventChange(t *testing.T) {
+	tests := []struct {
+		name          string
+		change        db.EventChange
+		privileged    bool
+		wantType      string
+		wantEventBody bool
+	}{
+		{
+			name:          "published event",
+			change:        db.EventChange{Type: db.ChangeUpdated, Event: &db.Event{Status: db.StatusPublished}},
+			wantType:      db.ChangeUpdated,
+			wantEventBody: true,
+		},
+		{
+			name:     "draft becomes a tombstone",
+			change:   db.EventChange{Type: db.ChangeCreated, Event: &db.Event{Status: db.StatusDraft}},
+			wantType: db.ChangeDeleted,
+		},
+		{
+			name:     "deleted event loses its body",
+			change:   db.EventChange{Type: db.ChangeDeleted, Event: &db.Event{Status: db.StatusPublished}},
+			wantType: db.ChangeDeleted,
+		},
+		{
+			name:          "admin sees a scheduled event",
+			change:        db.EventChange{Type: db.ChangeUpdated, Event: &db.Event{Status: db.StatusScheduled}},
+			privileged:    true,
+			wantType:      db.ChangeUpdated,
+			wantEventBody: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := tt.change
+			change.Seq, change.EventID = 3, 7
+
+			got := visibleEventChange(&change, tt.privileged)
+			if got.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", got.Type, tt.wantType)
+			}
+			if (got.Event != nil) != tt.wantEventBody {
+				t.Errorf("Event = %v, want body %v", got.Event, tt.wantEventBody)
+			}
+			if got.Seq != 3 || got.EventID != 7 {
+				t.Errorf("Seq/EventID = %d/%d, want 3/7", got.Seq, got.EventID)
+			}
+			if change.Event == nil {
+				t.Error("visibleEventChange modified the journal entry")
+			}
+		})
+	}
+}
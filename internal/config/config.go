@@ -27,7 +27,12 @@ const (
 	opensearchTimeoutKey    = "opensearch_params.timeout"
 	opensearchMaxRetriesKey = "opensearch_params.max_retries"
 
-	serviceAddress = "server_params.address"
+	serviceAddress    = "server_params.address"
+	serviceAdminToken = "server_params.admin_token"
+
+	publishIntervalKey = "jobs_params.publish_interval"
+	purgeIntervalKey   = "jobs_params.purge_interval"
+	purgeRetentionKey  = "jobs_params.purge_retention"
 )
 
 // AppConfig представляет конфигурацию всего приложения
@@ -36,6 +41,7 @@ type AppConfig struct {
 	DB         DBParams         `mapstructure:"db_params" validate:"required"`
 	OpenSearch OpenSearchParams `mapstructure:"opensearch_params" validate:"required"`
 	Server     ServerParams     `mapstructure:"server_params" validate:"required"`
+	Jobs       JobsParams       `mapstructure:"jobs_params"`
 }
 
 // ApplicationParams содержит общие параметры приложения
@@ -45,6 +51,20 @@ type ServiceParams struct {
 
 type ServerParams struct {
 	Address string `mapstructure:"address" validate:"required"`
+	// Токен администратора (метаданные authorization: Bearer <token>).
+	// Пустой токен отключает административный доступ к неопубликованным событиям
+	AdminToken string `mapstructure:"admin_token"`
+}
+
+// JobsParams содержит параметры фоновых задач. Незаданные (нулевые) значения
+// заменяются значениями по умолчанию планировщика (pkg/scheduler)
+type JobsParams struct {
+	// Период проверки запланированных публикаций
+	PublishInterval time.Duration `mapstructure:"publish_interval" validate:"min=0"`
+	// Период запуска очистки удаленных записей
+	PurgeInterval time.Duration `mapstructure:"purge_interval" validate:"min=0"`
+	// Сколько хранятся мягко удаленные записи до окончательного удаления
+	PurgeRetention time.Duration `mapstructure:"purge_retention" validate:"min=0"`
 }
 
 // DBParams содержит параметры подключения к базе данных
@@ -101,10 +121,14 @@ func envBindings() map[string]string {
 		portKey:                 "DB_PORT",
 		connectTimeoutKey:       "DB_CONNECT_TIMEOUT",
 		serviceAddress:          "SERVICE_ADDRESS",
+		serviceAdminToken:       "SERVICE_ADMIN_TOKEN",
 		opensearchURLKey:        "OPENSEARCH_URL",
 		opensearchIndexKey:      "OPENSEARCH_INDEX",
 		opensearchTimeoutKey:    "OPENSEARCH_TIMEOUT",
 		opensearchMaxRetriesKey: "OPENSEARCH_MAX_RETRIES",
+		publishIntervalKey:      "JOBS_PUBLISH_INTERVAL",
+		purgeIntervalKey:        "JOBS_PURGE_INTERVAL",
+		purgeRetentionKey:       "JOBS_PURGE_RETENTION",
 	}
 }
 
@@ -157,6 +181,9 @@ func New() (*AppConfig, error) {
 		config.OpenSearch.MaxRetries = 3
 	}
 
+	// Значения по умолчанию для фоновых задач задает pkg/scheduler: нулевые периоды
+	// заменяются на scheduler.DefaultPublishInterval, DefaultPurgeInterval и DefaultPurgeRetention
+
 	// Валидация конфигурации
 	validate := validator.New()
 
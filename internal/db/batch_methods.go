@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBatchAborted возвращается для элементов пакета, отмененных из-за ошибки другого элемента
+// в транзакционном режиме
+var ErrBatchAborted = errors.New("batch aborted")
+
+// batchTimeout ограничивает транзакцию пакета целиком
+const batchTimeout = 30 * time.Second
+
+// BatchCreateEvents создает события пакетом. В транзакционном режиме (atomic) все события
+// создаются в одной транзакции, иначе каждое в своей. Возвращает созданные события и ошибки
+// по элементам: срезы той же длины, что и events.
+func (s *PostgresStore) BatchCreateEvents(parentCtx context.Context, events []*Event, atomic bool) ([]*Event, []error) {
+	return s.runEventBatch(parentCtx, len(events), atomic, func(ctx context.Context, tx *PostgresStore, i int) (*Event, error) {
+		return tx.CreateEvent(ctx, events[i])
+	})
+}
+
+// BatchUpdateEvents обновляет события пакетом. fields[i] - маска обновления events[i]
+// (пустая маска обновляет все поля). Режимы и результат как у BatchCreateEvents.
+func (s *PostgresStore) BatchUpdateEvents(parentCtx context.Context, events []*Event, fields [][]string, atomic bool) ([]*Event, []error) {
+	return s.runEventBatch(parentCtx, len(events), atomic, func(ctx context.Context, tx *PostgresStore, i int) (*Event, error) {
+		return tx.UpdateEventFields(ctx, events[i], fields[i])
+	})
+}
+
+// BatchDeleteEvents мягко удаляет события пакетом. Режимы и результат как у BatchCreateEvents.
+func (s *PostgresStore) BatchDeleteEvents(parentCtx context.Context, ids []int64, atomic bool) ([]*Event, []error) {
+	return s.runEventBatch(parentCtx, len(ids), atomic, func(ctx context.Context, tx *PostgresStore, i int) (*Event, error) {
+		return tx.DeleteEvent(ctx, ids[i])
+	})
+}
+
+// runEventBatch выполняет op для каждого из n элементов. В транзакционном режиме первая ошибка
+// откатывает весь пакет: у остальных элементов ошибка ErrBatchAborted и нет результата.
+func (s *PostgresStore) runEventBatch(parentCtx context.Context, n int, atomic bool, op func(ctx context.Context, tx *PostgresStore, i int) (*Event, error)) ([]*Event, []error) {
+	results := make([]*Event, n)
+	errs := make([]error, n)
+
+	if !atomic {
+		for i := range n {
+			results[i], errs[i] = op(parentCtx, s, i)
+		}
+		return results, errs
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, batchTimeout)
+	defer cancel()
+
+	failed := -1
+	err := s.inTx(ctx, func(tx *PostgresStore) error {
+		for i := range n {
+			result, err := op(ctx, tx, i)
+			if err != nil {
+				failed = i
+				return err
+			}
+			results[i] = result
+		}
+		return nil
+	})
+	if err == nil {
+		return results, errs
+	}
+
+	for i := range n {
+		results[i] = nil
+		switch {
+		case i == failed:
+			errs[i] = err
+		case failed >= 0:
+			errs[i] = fmt.Errorf("item %d rolled back after item %d failed: %w", i, failed, ErrBatchAborted)
+		default:
+			// Ошибка начала или фиксации транзакции относится ко всем элементам
+			errs[i] = err
+		}
+	}
+
+	return results, errs
+}
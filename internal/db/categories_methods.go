@@ -2,19 +2,86 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 )
 
+var (
+	// ErrCategoryCycle возвращается, если новый родитель категории является ею самой или ее потомком
+	ErrCategoryCycle = errors.New("category cannot be moved under itself or its descendant")
+	// ErrCategoryInUse возвращается при удалении категории, у которой есть неудаленные подкатегории или события
+	ErrCategoryInUse = errors.New("category has active subcategories or events")
+)
+
+const (
+	// categoryTreeCTE обходит дерево от корней и вычисляет путь и глубину каждой категории.
+	// Категории, попавшие в цикл (чего не допускает UpdateCategory), в дерево не попадают.
+	// Мягко удаленные категории исключаются вместе с поддеревом.
+	categoryTreeCTE = `WITH RECURSIVE tree AS (
+							SELECT id, name, parent_id, name::text AS path, 0 AS depth, version, created_at, updated_at
+							FROM categories
+							WHERE parent_id IS NULL AND deleted_at IS NULL
+							UNION ALL
+							SELECT c.id, c.name, c.parent_id, tree.path || ' / ' || c.name, tree.depth + 1, c.version, c.created_at, c.updated_at
+							FROM categories c
+							JOIN tree ON c.parent_id = tree.id
+							WHERE c.deleted_at IS NULL
+						)`
+
+	categoryColumns = `id, name, parent_id, path, depth, version, created_at, updated_at`
+
+	listCategoriesQuery  = categoryTreeCTE + ` SELECT ` + categoryColumns + ` FROM tree ORDER BY path`
+	getCategoryByIdQuery = categoryTreeCTE + ` SELECT ` + categoryColumns + ` FROM tree WHERE id = $1`
+
+	// categoryDescendantsQuery возвращает сами категории и всех их потомков.
+	// UNION (а не UNION ALL) гарантирует завершение даже при цикле в данных.
+	categoryDescendantsQuery = `WITH RECURSIVE sub AS (
+							SELECT id FROM categories WHERE id = ANY($1) AND deleted_at IS NULL
+							UNION
+							SELECT c.id FROM categories c JOIN sub ON c.parent_id = sub.id WHERE c.deleted_at IS NULL
+						)
+						SELECT id FROM sub ORDER BY id`
+
+	// categoryIsDescendantQuery проверяет, входит ли $2 в поддерево категории $1 (включая ее саму)
+	categoryIsDescendantQuery = `WITH RECURSIVE sub AS (
+							SELECT id FROM categories WHERE id = $1
+							UNION
+							SELECT c.id FROM categories c JOIN sub ON c.parent_id = sub.id
+						)
+						SELECT EXISTS(SELECT 1 FROM sub WHERE id = $2)`
+
+	// categoryInUseQuery проверяет, остались ли у категории неудаленные подкатегории или события
+	categoryInUseQuery = `SELECT EXISTS(SELECT 1 FROM categories WHERE parent_id = $1 AND deleted_at IS NULL)
+						OR EXISTS(SELECT 1 FROM events WHERE category_id = $1 AND deleted_at IS NULL)`
+
+	// categoryVersionForUpdateQuery блокирует категорию до конца транзакции изменения
+	categoryVersionForUpdateQuery = `SELECT version FROM categories WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	// lockCategoryTreeQuery сериализует переносы категорий: два встречных переноса,
+	// каждый из которых проходит проверку на цикл по старому дереву, вместе образовали бы цикл
+	lockCategoryTreeQuery = `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`
+
+	deleteCategoryQuery = `UPDATE categories SET deleted_at = NOW(), version = version + 1 WHERE id = $1 AND deleted_at IS NULL`
+
+	// purgeDeletedCategoriesQuery окончательно удаляет категории, удаленные раньше $1.
+	// Категории, на которые еще ссылаются события или подкатегории (в том числе удаленные), пропускаются
+	// и будут удалены при следующих запусках после очистки ссылающихся строк.
+	purgeDeletedCategoriesQuery = `DELETE FROM categories c
+						WHERE c.deleted_at < $1
+						  AND NOT EXISTS(SELECT 1 FROM events e WHERE e.category_id = c.id)
+						  AND NOT EXISTS(SELECT 1 FROM categories child WHERE child.parent_id = c.id)`
+)
+
 func (s *PostgresStore) CreateCategory(parentCtx context.Context, category *Category) error {
 	ctx, cancel := context.WithTimeout(parentCtx, time.Second*3)
 	defer cancel()
 
-	query := `INSERT INTO categories (name) VALUES ($1) RETURNING id, created_at, updated_at`
+	query := `INSERT INTO categories (name, parent_id) VALUES ($1, $2) RETURNING id, version, created_at, updated_at`
 
-	err := s.db.QueryRow(ctx, query, category.Name).Scan(&category.Id, &category.CreatedAt, &category.UpdatedAt)
+	err := s.db.QueryRow(ctx, query, category.Name, category.ParentID).Scan(&category.Id, &category.Version, &category.CreatedAt, &category.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create category: %w", err)
 	}
@@ -26,8 +93,7 @@ func (s *PostgresStore) ListCategories(parentCtx context.Context) ([]*Category,
 	ctx, cancel := context.WithTimeout(parentCtx, time.Second*3)
 	defer cancel()
 
-	rows, err := s.db.Query(ctx,
-		"SELECT id, name, created_at, updated_at FROM categories")
+	rows, err := s.db.Query(ctx, listCategoriesQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -36,8 +102,8 @@ func (s *PostgresStore) ListCategories(parentCtx context.Context) ([]*Category,
 	categories := []*Category{}
 
 	for rows.Next() {
-		category := new(Category)
-		if err := rows.Scan(&category.Id, &category.Name, &category.CreatedAt, &category.UpdatedAt); err != nil {
+		category, err := scanCategory(rows)
+		if err != nil {
 			return nil, err
 		}
 
@@ -54,19 +120,10 @@ func (s *PostgresStore) GetCategoryByID(parentCtx context.Context, id int) (*Cat
 	ctx, cancel := context.WithTimeout(parentCtx, time.Second*3)
 	defer cancel()
 
-	row := s.db.QueryRow(ctx, "SELECT id, name, created_at, updated_at FROM categories WHERE id = $1", id)
-
-	category := new(Category)
-	err := row.Scan(
-		&category.Id,
-		&category.Name,
-		&category.CreatedAt,
-		&category.UpdatedAt,
-	)
-
+	category, err := scanCategory(s.db.QueryRow(ctx, getCategoryByIdQuery, id))
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("category %d not found", id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("category %d not found: %w", id, err)
 		}
 		return nil, fmt.Errorf("failed to get category by id %d: %w", id, err)
 	}
@@ -78,48 +135,145 @@ func (s *PostgresStore) UpdateCategory(parentCtx context.Context, category *Cate
 	ctx, cancel := context.WithTimeout(parentCtx, time.Second*3)
 	defer cancel()
 
-	var exists bool
+	// Проверка версии, проверка на цикл и запись выполняются в одной транзакции
+	// под блокировкой строки, поэтому между ними категорию никто не изменит
+	return s.inTx(ctx, func(tx *PostgresStore) error {
+		if category.ParentID != nil {
+			if _, err := tx.db.Exec(ctx, lockCategoryTreeQuery); err != nil {
+				return fmt.Errorf("failed to lock categories: %w", err)
+			}
+		}
+
+		var currentVersion int64
+		err := tx.db.QueryRow(ctx, categoryVersionForUpdateQuery, category.Id).Scan(&currentVersion)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("category with ID %d not found: %w", category.Id, err)
+			}
+			return fmt.Errorf("failed to get category %d version: %w", category.Id, err)
+		}
+
+		if currentVersion != category.Version {
+			return &VersionConflictError{Entity: "category", ID: int64(category.Id), Current: currentVersion}
+		}
+
+		// Защита от циклов: новый родитель не может лежать в поддереве категории
+		if category.ParentID != nil {
+			var cycle bool
+			err = tx.db.QueryRow(ctx, categoryIsDescendantQuery, category.Id, *category.ParentID).Scan(&cycle)
+			if err != nil {
+				return fmt.Errorf("failed to check category %d hierarchy: %w", category.Id, err)
+			}
+			if cycle {
+				return fmt.Errorf("category %d, parent %d: %w", category.Id, *category.ParentID, ErrCategoryCycle)
+			}
+		}
+
+		query := `
+			UPDATE categories
+			SET name = $1, parent_id = $2, version = version + 1, updated_at = NOW()
+			WHERE id = $3
+			RETURNING version, updated_at
+		`
+		err = tx.db.QueryRow(
+			ctx,
+			query,
+			category.Name,
+			category.ParentID,
+			category.Id).Scan(&category.Version, &category.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to update category %d: %w", category.Id, err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteCategory мягко удаляет категорию. Категорию с неудаленными подкатегориями
+// или событиями удалить нельзя: возвращается ErrCategoryInUse.
+func (s *PostgresStore) DeleteCategory(parentCtx context.Context, id int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, time.Second*3)
+	defer cancel()
 
-	// Проверка существования категории
-	err := s.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)", category.Id).Scan(&exists)
+	var inUse bool
+	if err := s.db.QueryRow(ctx, categoryInUseQuery, id).Scan(&inUse); err != nil {
+		return fmt.Errorf("failed to check usage of category %d: %w", id, err)
+	}
+	if inUse {
+		return fmt.Errorf("category %d: %w", id, ErrCategoryInUse)
+	}
+
+	cmdTag, err := s.db.Exec(ctx, deleteCategoryQuery, id)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to delete category %d: %w", id, err)
 	}
 
-	if !exists {
-		return fmt.Errorf("category with ID %d not found", category.Id)
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("category with ID %d not found for deletion: %w", id, pgx.ErrNoRows)
 	}
 
-	query := `
-		UPDATE categories
-		SET name = $1, updated_at = NOW()
-		WHERE id = $2
-		RETURNING updated_at
-	`
-	err = s.db.QueryRow(
-		ctx,
-		query,
-		category.Name,
-		category.Id).Scan(&category.UpdatedAt)
+	return nil
+}
+
+// PurgeDeletedCategories окончательно удаляет категории, мягко удаленные раньше before.
+// Возвращает количество удаленных строк.
+func (s *PostgresStore) PurgeDeletedCategories(parentCtx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, time.Second*3)
+	defer cancel()
+
+	cmdTag, err := s.db.Exec(ctx, purgeDeletedCategoriesQuery, before)
 	if err != nil {
-		return fmt.Errorf("failed to update category %d: %w", category.Id, err)
+		return 0, fmt.Errorf("failed to purge deleted categories: %w", err)
 	}
 
-	return nil
+	return cmdTag.RowsAffected(), nil
 }
 
-func (s *PostgresStore) DeleteCategory(parentCtx context.Context, id int) error {
+// GetCategoryDescendantIDs возвращает переданные категории вместе со всеми их потомками.
+func (s *PostgresStore) GetCategoryDescendantIDs(parentCtx context.Context, ids []int64) ([]int64, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
 	ctx, cancel := context.WithTimeout(parentCtx, time.Second*3)
 	defer cancel()
 
-	cmdTag, err := s.db.Exec(ctx, "DELETE FROM categories WHERE id = $1", id)
+	rows, err := s.db.Query(ctx, categoryDescendantsQuery, ids)
 	if err != nil {
-		return fmt.Errorf("failed to delete category %d: %w", id, err)
+		return nil, fmt.Errorf("failed to query category descendants: %w", err)
 	}
+	defer rows.Close()
 
-	if cmdTag.RowsAffected() == 0 {
-		return fmt.Errorf("category with ID %d not found for deletion", id)
+	var result []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan category id: %w", err)
+		}
+		result = append(result, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating category descendant rows: %w", err)
 	}
 
-	return nil
+	return result, nil
+}
+
+// scanCategory сканирует строку дерева категорий (categoryColumns).
+func scanCategory(scanner pgxScanner) (*Category, error) {
+	category := new(Category)
+	err := scanner.Scan(
+		&category.Id,
+		&category.Name,
+		&category.ParentID,
+		&category.Path,
+		&category.Depth,
+		&category.Version,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
 }
@@ -0,0 +1,221 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Типы изменений в журнале event_changes
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated" // В том числе восстановление из корзины
+	ChangeDeleted = "deleted" // В том числе слияние дубликата с каноническим событием
+)
+
+// eventChangesLockKey ключ advisory-блокировки записи в журнал изменений
+const eventChangesLockKey = 7310020
+
+const (
+	// Блокировка держится до конца транзакции: следующая запись в журнал получит seq
+	// только после фиксации предыдущей, поэтому читатель не пропустит меньший seq.
+	// Берется только перед фиксацией (см. inTx), так что долгие транзакции, например
+	// атомарные пакетные изменения, не задерживают запись остальных
+	lockEventChangesQuery = `SELECT pg_advisory_xact_lock($1)`
+
+	insertEventChangeQuery = `INSERT INTO event_changes (event_id, change_type, category_id, source, payload)
+						VALUES ($1, $2, $3, $4, $5)`
+
+	getEventChangesQuery = `SELECT seq, event_id, change_type, payload, created_at FROM event_changes
+						WHERE seq > $1
+						  AND ($2::timestamptz IS NULL OR created_at >= $2)
+						  AND (cardinality($3::bigint[]) = 0 OR category_id = ANY($3))
+						  AND (cardinality($4::text[]) = 0 OR source = ANY($4))
+						ORDER BY seq LIMIT $5`
+
+	getLatestEventChangeSeqQuery  = `SELECT COALESCE(MAX(seq), 0) FROM event_changes`
+	getOldestEventChangeSeqQuery  = `SELECT COALESCE(MIN(seq), 0) FROM event_changes`
+	getOldestEventChangeTimeQuery = `SELECT MIN(created_at) FROM event_changes`
+
+	// Последнее изменение не удаляется: по нему видно, до какого seq журнал был очищен
+	purgeEventChangesQuery = `DELETE FROM event_changes
+						WHERE created_at < $1
+						  AND seq < (SELECT MAX(seq) FROM event_changes)`
+)
+
+// EventChange запись журнала изменений: тип изменения и состояние события сразу после него
+type EventChange struct {
+	Seq       int64 // Возрастает в порядке фиксации изменений
+	EventID   int64
+	Type      string // Одно из Change*
+	Event     *Event
+	CreatedAt time.Time
+}
+
+// EventChangeFilter условия чтения журнала изменений
+type EventChangeFilter struct {
+	AfterSeq    int64      // Только изменения с seq больше этого
+	Since       *time.Time // Только изменения не раньше этого момента
+	CategoryIDs []int64    // Пусто - любые категории
+	Sources     []string   // Пусто - любые источники
+	Limit       int
+}
+
+// pendingEventChange изменение события, ожидающее записи в журнал при фиксации транзакции
+type pendingEventChange struct {
+	eventID    int64
+	changeType string
+	categoryID int64
+	source     string
+	payload    []byte
+}
+
+// recordEventChange добавляет изменение события в журнал транзакции изменения.
+// Запись в event_changes выполняется при ее фиксации.
+func (s *PostgresStore) recordEventChange(ctx context.Context, action string, current *Event) error {
+	if s.changes == nil {
+		return s.inTx(ctx, func(tx *PostgresStore) error {
+			return tx.recordEventChange(ctx, action, current)
+		})
+	}
+
+	changeType := ChangeUpdated
+	switch {
+	case current.DeletedAt != nil:
+		changeType = ChangeDeleted
+	case action == RevisionCreate:
+		changeType = ChangeCreated
+	}
+
+	payload, err := json.Marshal(current)
+	if err != nil {
+		return fmt.Errorf("failed to encode change of event %d: %w", current.Id, err)
+	}
+
+	*s.changes = append(*s.changes, pendingEventChange{
+		eventID:    current.Id,
+		changeType: changeType,
+		categoryID: current.CategoryID,
+		source:     current.Source,
+		payload:    payload,
+	})
+
+	return nil
+}
+
+// writeEventChanges записывает изменения в журнал под блокировкой журнала.
+// Вызывается в транзакции изменения непосредственно перед ее фиксацией.
+func (s *PostgresStore) writeEventChanges(ctx context.Context, changes []pendingEventChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if _, err := s.db.Exec(ctx, lockEventChangesQuery, eventChangesLockKey); err != nil {
+		return fmt.Errorf("failed to lock event changes: %w", err)
+	}
+
+	for _, change := range changes {
+		_, err := s.db.Exec(ctx, insertEventChangeQuery, change.eventID, change.changeType, change.categoryID, change.source, change.payload)
+		if err != nil {
+			return fmt.Errorf("failed to record change of event %d: %w", change.eventID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetEventChanges возвращает изменения из журнала в порядке seq.
+func (s *PostgresStore) GetEventChanges(parentCtx context.Context, filter *EventChangeFilter) ([]*EventChange, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	categoryIDs, sources := filter.CategoryIDs, filter.Sources
+	if categoryIDs == nil {
+		categoryIDs = []int64{}
+	}
+	if sources == nil {
+		sources = []string{}
+	}
+
+	rows, err := s.db.Query(ctx, getEventChangesQuery, filter.AfterSeq, filter.Since, categoryIDs, sources, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*EventChange{}
+	for rows.Next() {
+		change := &EventChange{}
+		var payload []byte
+		if err := rows.Scan(&change.Seq, &change.EventID, &change.Type, &payload, &change.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event change: %w", err)
+		}
+		if err := json.Unmarshal(payload, &change.Event); err != nil {
+			return nil, fmt.Errorf("failed to decode event change %d: %w", change.Seq, err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event change rows: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetLatestEventChangeSeq возвращает seq последнего изменения в журнале или 0 для пустого журнала.
+func (s *PostgresStore) GetLatestEventChangeSeq(parentCtx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var seq int64
+	if err := s.db.QueryRow(ctx, getLatestEventChangeSeqQuery).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("failed to get latest event change: %w", err)
+	}
+
+	return seq, nil
+}
+
+// GetOldestEventChangeSeq возвращает seq самого раннего изменения, оставшегося в журнале
+// после очистки, или 0 для пустого журнала.
+func (s *PostgresStore) GetOldestEventChangeSeq(parentCtx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var seq int64
+	if err := s.db.QueryRow(ctx, getOldestEventChangeSeqQuery).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("failed to get oldest event change: %w", err)
+	}
+
+	return seq, nil
+}
+
+// GetOldestEventChangeTime возвращает момент самого раннего изменения, оставшегося в журнале
+// после очистки, или nil для пустого журнала.
+func (s *PostgresStore) GetOldestEventChangeTime(parentCtx context.Context) (*time.Time, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var oldest *time.Time
+	if err := s.db.QueryRow(ctx, getOldestEventChangeTimeQuery).Scan(&oldest); err != nil {
+		return nil, fmt.Errorf("failed to get oldest event change time: %w", err)
+	}
+
+	return oldest, nil
+}
+
+// PurgeEventChanges удаляет из журнала изменения, записанные раньше before.
+// Последнее изменение остается, даже если оно старше before.
+// Возвращает количество удаленных строк.
+func (s *PostgresStore) PurgeEventChanges(parentCtx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	cmdTag, err := s.db.Exec(ctx, purgeEventChangesQuery, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge event changes: %w", err)
+	}
+
+	return cmdTag.RowsAffected(), nil
+}
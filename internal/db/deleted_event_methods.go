@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	deletedEventsCondition = `events.deleted_at IS NOT NULL`
+
+	getDeletedEventByIdQuery = getEventsQueryBaseFields + ` WHERE ` + deletedEventsCondition + ` AND events.id = $1`
+	getDeletedEventsQuery    = getEventsQueryBaseFields + ` WHERE ` + deletedEventsCondition +
+		` ORDER BY events.deleted_at DESC, events.id DESC LIMIT $1 OFFSET $2`
+	countDeletedEventsQuery = `SELECT COUNT(*) FROM events WHERE deleted_at IS NOT NULL`
+
+	restoreEventQuery = `UPDATE events SET deleted_at = NULL, version = version + 1, updated_at = NOW()
+						WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	// Связанные теги, билетные категории и переопределения вхождений удаляются каскадно
+	purgeDeletedEventsQuery = `DELETE FROM events WHERE deleted_at < $1`
+)
+
+// GetDeletedEventByID извлекает мягко удаленное событие по ID.
+func (s *PostgresStore) GetDeletedEventByID(parentCtx context.Context, id int64) (*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	event, err := scanEvent(s.db.QueryRow(ctx, getDeletedEventByIdQuery, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("deleted event with ID %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to get deleted event by ID %d: %w", id, err)
+	}
+
+	return event, nil
+}
+
+// GetDeletedEvents возвращает страницу мягко удаленных событий, начиная с последних удаленных,
+// и их общее количество.
+func (s *PostgresStore) GetDeletedEvents(parentCtx context.Context, limit, offset int) ([]*Event, int64, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, getDeletedEventsQuery, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query deleted events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*Event{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan deleted event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating deleted event rows: %w", err)
+	}
+
+	var total int64
+	if err := s.db.QueryRow(ctx, countDeletedEventsQuery).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted events: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// RestoreEvent восстанавливает мягко удаленное событие и возвращает его.
+// Восстановление и его ревизия записываются в одной транзакции.
+func (s *PostgresStore) RestoreEvent(parentCtx context.Context, id int64) (*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var restored *Event
+	err := s.inTx(ctx, func(tx *PostgresStore) error {
+		previous, err := scanEvent(tx.db.QueryRow(ctx, getAnyEventByIdQuery, id))
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to lock event %d: %w", id, err)
+		}
+
+		cmdTag, err := tx.db.Exec(ctx, restoreEventQuery, id)
+		if err != nil {
+			return fmt.Errorf("failed to restore event %d: %w", id, err)
+		}
+
+		if cmdTag.RowsAffected() == 0 {
+			return fmt.Errorf("deleted event with ID %d not found for restore: %w", id, pgx.ErrNoRows)
+		}
+
+		restored, err = tx.recordEventRevision(ctx, id, RevisionRestore, previous)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// PurgeDeletedEvents окончательно удаляет события, мягко удаленные раньше before.
+// Возвращает количество удаленных строк.
+func (s *PostgresStore) PurgeDeletedEvents(parentCtx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	cmdTag, err := s.db.Exec(ctx, purgeDeletedEventsQuery, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted events: %w", err)
+	}
+
+	return cmdTag.RowsAffected(), nil
+}
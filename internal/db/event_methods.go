@@ -13,36 +13,78 @@ const (
 	// INSERT INTO events ... RETURNING id, created_at, updated_at
 	// created_at должно иметь DEFAULT CURRENT_TIMESTAMP в схеме БД,
 	// updated_at может быть NULL или DEFAULT CURRENT_TIMESTAMP и обновляться через NOW() в UPDATE.
-	// Количество VALUES ($1-$9) должно соответствовать количеству передаваемых полей.
-	createEventQuery = `INSERT INTO events (name, description, category_id, date, time, location, price, image, source) 
-						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
-						RETURNING id, created_at, updated_at`
-
-	// UPDATE events SET ..., updated_at = NOW() WHERE id = $N RETURNING updated_at
-	// Количество SET полей + id ($1-$10)
-	updateEventQuery = `UPDATE events 
-						SET name = $1, description = $2, category_id = $3, date = $4, time = $5, 
-						    location = $6, price = $7, image = $8, source = $9, updated_at = NOW() 
-						WHERE id = $10 
-						RETURNING updated_at` // Можно возвращать все поля: RETURNING id, name, ..., updated_at
-
-	// SELECT запросы
-	getEventsQueryBaseFields = `SELECT id, name, description, category_id, date, time, location, price, image, source, created_at, updated_at FROM events`
-	getEventsQuery           = getEventsQueryBaseFields
-	getEventByIdQuery        = getEventsQueryBaseFields + ` WHERE id = $1`
-	getEventsByCategoryQuery = getEventsQueryBaseFields + ` WHERE category_id = $1`
-	deleteEventQuery         = `DELETE FROM events WHERE id = $1`
+	// Количество VALUES ($1-$24) должно соответствовать количеству передаваемых полей.
+	insertEventQuery = `INSERT INTO events (name, description, category_id, date, time, location, price, image, source, starts_at, ends_at, timezone,
+						                    recurrence_rule, recurrence_exdates, recurrence_until, venue_id, price_from, price_to, currency,
+						                    status, publish_at, source_id, external_id, organizer_id) 
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), $14, $15, $16, $17, $18, $19, $20, $21,
+						        $22, NULLIF($23, ''), $24)`
+
+	createEventQuery = insertEventQuery + ` RETURNING id, created_at, updated_at`
+
+	// eventColumns список колонок в порядке, который ожидает scanEvent.
+	// Колонки квалифицированы, так как запросы соединяют events с venues и organizers.
+	eventColumns = `events.id, events.name, events.description, events.category_id, events.date, events.time, events.location,
+					events.price, events.image, events.source, events.starts_at, events.ends_at, events.timezone,
+					events.recurrence_rule, events.recurrence_exdates, events.recurrence_until, events.created_at, events.updated_at,
+					events.venue_id, venues.name, venues.address, venues.city, venues.latitude, venues.longitude,
+					events.price_from, events.price_to, events.currency, events.status, events.publish_at, events.status_reason,
+					events.deleted_at, events.version, events.source_id, events.external_id,
+					events.organizer_id, organizers.name, organizers.is_verified, ` + eventTagsColumn + `, ` + eventTicketTiersColumn + `, ` + eventImagesColumn + `, ` + eventTranslationsColumn
+
+	// eventsFromClause источник строк для чтения событий вместе с площадкой и организатором
+	eventsFromClause = ` FROM events LEFT JOIN venues ON venues.id = events.venue_id
+					LEFT JOIN organizers ON organizers.id = events.organizer_id`
+
+	// activeEventsCondition исключает мягко удаленные события
+	activeEventsCondition = `events.deleted_at IS NULL`
+
+	// SELECT запросы. Все, кроме getEventsQueryBaseFields, видят только неудаленные события
+	getEventsQueryBaseFields = `SELECT ` + eventColumns + eventsFromClause
+	getEventsQuery           = getEventsQueryBaseFields + ` WHERE ` + activeEventsCondition
+	getEventByIdQuery        = getEventsQuery + ` AND events.id = $1`
+	getEventsByCategoryQuery = getEventsQuery + ` AND events.category_id = $1`
+	getEventsByIdsQuery      = getEventsQuery + ` AND events.id = ANY($1) ORDER BY events.id`
+
+	// Мягкое удаление: строка остается до очистки по сроку хранения
+	deleteEventQuery = `UPDATE events SET deleted_at = NOW(), version = version + 1 WHERE id = $1 AND deleted_at IS NULL`
 )
 
 // CreateEvent создает новое событие.
+// Событие, теги, билетные категории и первая ревизия записываются в одной транзакции.
 func (s *PostgresStore) CreateEvent(parentCtx context.Context, event *Event) (*Event, error) {
 	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
 	defer cancel()
 
+	if err := event.prepareRecurrence(); err != nil {
+		return nil, fmt.Errorf("failed to create event: %w", err)
+	}
+
+	var created *Event
+	err := s.inTx(ctx, func(tx *PostgresStore) error {
+		err := tx.insertEvent(ctx, createEventQuery, event)
+		if err != nil {
+			// Можно добавить более специфическую обработку ошибок PostgreSQL (например, unique_violation)
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+
+		created, err = tx.createEventDetails(ctx, event)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// insertEvent выполняет INSERT события (insertEventQuery с RETURNING id, created_at, updated_at)
+// и заполняет ID и таймстемпы. Без возвращенной строки возвращает pgx.ErrNoRows.
+func (s *PostgresStore) insertEvent(ctx context.Context, query string, event *Event) error {
 	// ВАЖНО: убедись, что event.Time не конфликтует с ключевым словом TIME в SQL, если это так, используй кавычки: "time"
-	err := s.db.QueryRow(
+	return s.db.QueryRow(
 		ctx,
-		createEventQuery,
+		query,
 		event.Name,
 		event.Description,
 		event.CategoryID,
@@ -52,37 +94,92 @@ func (s *PostgresStore) CreateEvent(parentCtx context.Context, event *Event) (*E
 		event.Price,
 		event.Image,
 		event.Source,
+		event.StartsAt,
+		event.EndsAt,
+		event.Timezone,
+		event.RecurrenceRule,
+		event.RecurrenceExDates,
+		event.RecurrenceUntil,
+		event.VenueID,
+		event.PriceFrom,
+		event.PriceTo,
+		event.Currency,
+		event.Status,
+		event.PublishAt,
+		event.SourceID,
+		event.ExternalID,
+		event.OrganizerID,
 	).Scan(&event.Id, &event.CreatedAt, &event.UpdatedAt) // Сканируем ID и таймстемпы, установленные БД
+}
 
-	if err != nil {
-		// Можно добавить более специфическую обработку ошибок PostgreSQL (например, unique_violation)
-		return nil, fmt.Errorf("failed to create event: %w", err)
+// createEventDetails сохраняет обложку, теги, билетные категории и переводы вставленного события
+// и записывает первую ревизию. Вызывается внутри транзакции создания.
+func (s *PostgresStore) createEventDetails(ctx context.Context, event *Event) (*Event, error) {
+	if err := s.setEventCover(ctx, event.Id, event.Image); err != nil {
+		return nil, err
 	}
 
-	return event, nil
+	if err := s.SetEventTags(ctx, event.Id, event.Tags); err != nil {
+		return nil, err
+	}
+
+	if err := s.SetEventTicketTiers(ctx, event.Id, event.TicketTiers); err != nil {
+		return nil, err
+	}
+
+	if err := s.SetEventTranslations(ctx, event.Id, event.Translations); err != nil {
+		return nil, err
+	}
+
+	return s.recordEventRevision(ctx, event.Id, RevisionCreate, nil)
 }
 
 // UpdateEvent обновляет существующее событие.
+// Изменение и его ревизия записываются в одной транзакции. Возвращает сохраненное состояние.
 func (s *PostgresStore) UpdateEvent(parentCtx context.Context, event *Event) (*Event, error) {
+	return s.UpdateEventFields(parentCtx, event, nil)
+}
+
+// UpdateEventFields обновляет только колонки, соответствующие полям маски (EventField*).
+// Пустая маска обновляет все поля, как UpdateEvent.
+func (s *PostgresStore) UpdateEventFields(parentCtx context.Context, event *Event, fields []string) (*Event, error) {
 	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
 	defer cancel()
 
+	var updated *Event
+	err := s.inTx(ctx, func(tx *PostgresStore) error {
+		var err error
+		updated, err = tx.updateEventWithRevision(ctx, event, RevisionUpdate, fields)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// updateEventWithRevision сохраняет поля маски вместе с обложкой, тегами, билетными категориями и переводами
+// и записывает ревизию action. Пустая маска сохраняет все поля. Вызывается внутри транзакции.
+func (s *PostgresStore) updateEventWithRevision(ctx context.Context, event *Event, action string, fields []string) (*Event, error) {
+	if err := event.prepareRecurrence(); err != nil {
+		return nil, fmt.Errorf("failed to update event %d: %w", event.Id, err)
+	}
+
+	previous, err := s.getEventForUpdate(ctx, event.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Строка заблокирована, поэтому сравнение версий не может устареть до записи
+	if event.Version != previous.Version {
+		return nil, &VersionConflictError{Entity: "event", ID: event.Id, Current: previous.Version}
+	}
+
 	var newUpdatedAt time.Time // Для сканирования значения из RETURNING updated_at
 
-	err := s.db.QueryRow(
-		ctx,
-		updateEventQuery,
-		event.Name,
-		event.Description,
-		event.CategoryID,
-		event.Date,
-		event.Time, // Если имя колонки "time", оно должно быть в кавычках в SQL
-		event.Location,
-		event.Price,
-		event.Image,
-		event.Source,
-		event.Id,
-	).Scan(&newUpdatedAt)
+	query, args := buildUpdateEventQuery(event, fields)
+	err = s.db.QueryRow(ctx, query, args...).Scan(&newUpdatedAt)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -94,12 +191,31 @@ func (s *PostgresStore) UpdateEvent(parentCtx context.Context, event *Event) (*E
 
 	event.UpdatedAt = &newUpdatedAt // Обновляем поле в объекте event
 
-	// Если updateEventQuery возвращал все поля (RETURNING *), то можно было бы пересканировать весь event:
-	// _, err := s.GetEventByID(ctx, event.Id) // или scanIntoEvent, если QueryRow вернул все поля.
-	// Это гарантирует, что все поля в объекте event актуальны, а не только UpdatedAt.
-	// Но для этого updateEventQuery должен возвращать все поля.
+	if InMask(fields, EventFieldImage) {
+		if err := s.setEventCover(ctx, event.Id, event.Image); err != nil {
+			return nil, err
+		}
+	}
 
-	return event, nil
+	if InMask(fields, EventFieldTags) {
+		if err := s.SetEventTags(ctx, event.Id, event.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if InMask(fields, EventFieldTicketTiers) {
+		if err := s.SetEventTicketTiers(ctx, event.Id, event.TicketTiers); err != nil {
+			return nil, err
+		}
+	}
+
+	if InMask(fields, EventFieldTranslations) {
+		if err := s.SetEventTranslations(ctx, event.Id, event.Translations); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.recordEventRevision(ctx, event.Id, action, previous)
 }
 
 // GetEvents извлекает все события.
@@ -147,35 +263,62 @@ func (s *PostgresStore) GetEventByID(parentCtx context.Context, id int64) (*Even
 	return event, nil
 }
 
-// DeleteEvent удаляет событие по ID.
-// Возвращает удаленный объект, если он был найден и удален.
-func (s *PostgresStore) DeleteEvent(parentCtx context.Context, id int64) (*Event, error) {
+// GetEventsByIDs извлекает события по списку ID, упорядоченные по ID.
+// Отсутствующие ID пропускаются.
+func (s *PostgresStore) GetEventsByIDs(parentCtx context.Context, ids []int64) ([]*Event, error) {
 	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
 	defer cancel()
 
-	// Для соответствия proto `DeleteEvent(Req) returns (EventRes)`,
-	// мы сначала получаем событие, затем удаляем.
-	// Это не атомарно. Если нужна атомарность и возврат удаленных данных,
-	// можно использовать `DELETE ... RETURNING *;` если СУБД поддерживает.
-
-	eventToDelete, err := s.GetEventByID(ctx, id) // Сначала получаем, чтобы было что вернуть
+	rows, err := s.db.Query(ctx, getEventsByIdsQuery, ids)
 	if err != nil {
-		// Ошибка уже включает "not found" если это так
-		return nil, fmt.Errorf("cannot delete event, failed to retrieve event ID %d: %w", id, err)
+		return nil, fmt.Errorf("failed to query events by ids: %w", err)
 	}
+	defer rows.Close()
 
-	cmdTag, err := s.db.Exec(ctx, deleteEventQuery, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute delete for event %d: %w", id, err)
+	events := []*Event{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event during GetEventsByIDs: %w", err)
+		}
+		events = append(events, event)
 	}
 
-	if cmdTag.RowsAffected() == 0 {
-		// Это странная ситуация, если GetEventByID выше нашел событие,
-		// но Exec не нашел строк для удаления (возможно, гонка состояний).
-		return nil, fmt.Errorf("event with ID %d was found but not deleted (0 rows affected)", id)
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event rows: %w", err)
 	}
 
-	return eventToDelete, nil // Возвращаем ранее полученные данные удаленного события
+	return events, nil
+}
+
+// DeleteEvent мягко удаляет событие по ID: оно пропадает из выборок,
+// но может быть восстановлено через RestoreEvent до очистки.
+// Удаление и его ревизия записываются в одной транзакции.
+// Возвращает удаленный объект, если он был найден и удален.
+func (s *PostgresStore) DeleteEvent(parentCtx context.Context, id int64) (*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var deleted *Event
+	err := s.inTx(ctx, func(tx *PostgresStore) error {
+		eventToDelete, err := tx.getEventForUpdate(ctx, id) // Блокируем строку до конца транзакции
+		if err != nil {
+			// Ошибка уже включает "not found" если это так
+			return fmt.Errorf("cannot delete event, failed to retrieve event ID %d: %w", id, err)
+		}
+
+		if _, err := tx.db.Exec(ctx, deleteEventQuery, id); err != nil {
+			return fmt.Errorf("failed to execute delete for event %d: %w", id, err)
+		}
+
+		deleted, err = tx.recordEventRevision(ctx, id, RevisionDelete, eventToDelete)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, nil // Возвращаем данные удаленного события
 }
 
 // GetEventsByCategory извлекает события по ID категории.
@@ -218,7 +361,7 @@ func (s *PostgresStore) GetEventsWithFilter(parentCtx context.Context, filter *E
 	}
 
 	// Если фильтр пустой, используем обычный GetEvents
-	if filter.IsEmpty() && !filter.HasPagination() {
+	if filter.IsEmpty() && !filter.HasPagination() && len(filter.Sort) == 0 {
 		return s.GetEvents(parentCtx)
 	}
 
@@ -333,6 +476,15 @@ type pgxScanner interface {
 // Работает как с pgx.Rows (через rows.Scan), так и с pgx.Row (через row.Scan).
 func scanEvent(scanner pgxScanner) (*Event, error) {
 	event := new(Event)
+	var recurrenceRule *string // recurrence_rule может быть NULL
+	var externalID *string     // external_id заполнен только у импортированных событий
+
+	// Колонки venues равны NULL, если площадка не указана
+	var venueName, venueAddress, venueCity *string
+	var venueLatitude, venueLongitude *float64
+	var organizerName *string
+	var organizerVerified *bool
+
 	err := scanner.Scan(
 		&event.Id,
 		&event.Name,
@@ -344,11 +496,72 @@ func scanEvent(scanner pgxScanner) (*Event, error) {
 		&event.Price,
 		&event.Image,
 		&event.Source,
+		&event.StartsAt,
+		&event.EndsAt,
+		&event.Timezone,
+		&recurrenceRule,
+		&event.RecurrenceExDates,
+		&event.RecurrenceUntil,
 		&event.CreatedAt,
 		&event.UpdatedAt, // UpdatedAt это *time.Time, Scan обработает NULL корректно
+		&event.VenueID,
+		&venueName,
+		&venueAddress,
+		&venueCity,
+		&venueLatitude,
+		&venueLongitude,
+		&event.PriceFrom,
+		&event.PriceTo,
+		&event.Currency,
+		&event.Status,
+		&event.PublishAt,
+		&event.StatusReason,
+		&event.DeletedAt,
+		&event.Version,
+		&event.SourceID,
+		&externalID,
+		&event.OrganizerID,
+		&organizerName,
+		&organizerVerified,
+		&event.Tags,
+		&event.TicketTiers,
+		&event.Images,
+		&event.Translations,
 	)
 	if err != nil {
 		return nil, err // Ошибка будет обработана вызывающей функцией (например, pgx.ErrNoRows)
 	}
+
+	if recurrenceRule != nil {
+		event.RecurrenceRule = *recurrenceRule
+	}
+
+	if externalID != nil {
+		event.ExternalID = *externalID
+	}
+
+	if event.VenueID != nil && venueName != nil {
+		event.Venue = &Venue{
+			Id:        *event.VenueID,
+			Name:      *venueName,
+			Latitude:  venueLatitude,
+			Longitude: venueLongitude,
+		}
+		if venueAddress != nil {
+			event.Venue.Address = *venueAddress
+		}
+		if venueCity != nil {
+			event.Venue.City = *venueCity
+		}
+	}
+
+	if event.OrganizerID != nil && organizerName != nil {
+		event.Organizer = &Organizer{
+			Id:         *event.OrganizerID,
+			Name:       *organizerName,
+			IsVerified: organizerVerified != nil && *organizerVerified,
+		}
+	}
+
 	return event, nil
 }
@@ -0,0 +1,205 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Фасеты списка событий. Фасет соответствует фильтру, которым выбирается его значение
+const (
+	FacetCategories = "categories" // CategoryIDs
+	FacetSources    = "sources"    // Source
+	FacetCities     = "cities"     // Cities
+	FacetVenues     = "venues"     // VenueIDs
+	FacetPrice      = "price"      // MinPrice, MaxPrice
+	FacetDates      = "dates"      // DateFrom, DateTo
+)
+
+// FacetRequest набор запрошенных фасетов
+type FacetRequest struct {
+	Categories bool
+	Sources    bool
+	Cities     bool
+	Venues     bool
+	Dates      bool
+
+	PriceInterval float32 // Шаг гистограммы цен по price_from, 0 - без гистограммы
+	Size          int     // Максимум значений в фасетах категорий, источников, городов и площадок
+	Timezone      string  // Часовой пояс дней гистограммы дат (IANA)
+}
+
+// FacetBucket значение фасета и количество событий с ним
+type FacetBucket struct {
+	Key   string // ID категории или площадки, источник, город, начало интервала цены, день YYYY-MM-DD
+	Label string // Название категории или площадки
+	Count int64
+}
+
+// EventFacets счетчики фасетов. Счетчики фасета считаются без его собственного фильтра:
+// выбранное значение не обнуляет остальные значения того же фасета.
+type EventFacets struct {
+	Categories []FacetBucket
+	Sources    []FacetBucket
+	Cities     []FacetBucket
+	Venues     []FacetBucket
+	Price      []FacetBucket
+	Dates      []FacetBucket
+}
+
+// facetTimeout ограничивает расчет всех фасетов
+const facetTimeout = 5 * time.Second
+
+// GetEventFacets считает фасеты событий фильтра через GROUP BY, по запросу на фасет.
+// Пагинация, курсор и сортировка фильтра не учитываются.
+// Повторяющиеся события попадают в гистограмму дат по первому вхождению.
+func (s *PostgresStore) GetEventFacets(parentCtx context.Context, filter *EventFilter, req *FacetRequest) (*EventFacets, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, facetTimeout)
+	defer cancel()
+
+	if err := validateFilter(filter); err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	facets := &EventFacets{}
+	queries := []struct {
+		enabled bool
+		target  *[]FacetBucket
+		query   facetQuery
+	}{
+		{req.Categories, &facets.Categories, facetQuery{
+			facet:     FacetCategories,
+			keyLabel:  `events.category_id::text, categories.name`,
+			join:      ` LEFT JOIN categories ON categories.id = events.category_id`,
+			condition: `events.category_id IS NOT NULL`,
+			orderBy:   `3 DESC, 1`,
+			limit:     req.Size,
+		}},
+		{req.Sources, &facets.Sources, facetQuery{
+			facet:     FacetSources,
+			keyLabel:  `events.source, NULL`,
+			condition: `events.source <> ''`,
+			orderBy:   `3 DESC, 1`,
+			limit:     req.Size,
+		}},
+		{req.Cities, &facets.Cities, facetQuery{
+			facet:     FacetCities,
+			keyLabel:  `venues.city, NULL`,
+			condition: `venues.city <> ''`,
+			orderBy:   `3 DESC, 1`,
+			limit:     req.Size,
+		}},
+		{req.Venues, &facets.Venues, facetQuery{
+			facet:     FacetVenues,
+			keyLabel:  `events.venue_id::text, venues.name`,
+			condition: `events.venue_id IS NOT NULL`,
+			orderBy:   `3 DESC, 1`,
+			limit:     req.Size,
+		}},
+		{req.PriceInterval > 0, &facets.Price, facetQuery{
+			facet:     FacetPrice,
+			keyLabel:  `(floor(events.price_from / $key) * $key)::text, NULL`,
+			condition: `events.price_from IS NOT NULL`,
+			orderBy:   `MIN(events.price_from)`,
+			keyArg:    req.PriceInterval,
+		}},
+		{req.Dates, &facets.Dates, facetQuery{
+			facet:     FacetDates,
+			keyLabel:  `to_char(events.starts_at AT TIME ZONE $key, 'YYYY-MM-DD'), NULL`,
+			condition: `events.starts_at IS NOT NULL`,
+			orderBy:   `1`,
+			keyArg:    LoadLocation(req.Timezone).String(),
+		}},
+	}
+
+	for _, q := range queries {
+		if !q.enabled {
+			continue
+		}
+		buckets, err := s.queryFacet(ctx, filter, q.query)
+		if err != nil {
+			return nil, err
+		}
+		*q.target = buckets
+	}
+
+	return facets, nil
+}
+
+// facetQuery описывает GROUP BY одного фасета
+type facetQuery struct {
+	facet     string
+	keyLabel  string // Выражения ключа и подписи, $key - плейсхолдер keyArg
+	join      string // Дополнительное соединение
+	condition string // Условие наличия значения фасета
+	orderBy   string
+	keyArg    any
+	limit     int // 0 - без ограничения
+}
+
+// queryFacet считает фасет для событий фильтра без собственного фильтра фасета.
+func (s *PostgresStore) queryFacet(ctx context.Context, filter *EventFilter, q facetQuery) ([]FacetBucket, error) {
+	conditions, args := buildFilterConditions(withoutFacetFilter(filter, q.facet))
+	conditions = append(conditions, q.condition)
+
+	keyLabel := q.keyLabel
+	if q.keyArg != nil {
+		args = append(args, q.keyArg)
+		keyLabel = strings.ReplaceAll(keyLabel, "$key", fmt.Sprintf("$%d", len(args)))
+	}
+
+	query := `SELECT ` + keyLabel + `, COUNT(*)` + eventsFromClause + q.join +
+		` WHERE ` + strings.Join(conditions, " AND ") +
+		` GROUP BY 1, 2 ORDER BY ` + q.orderBy
+	if q.limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.limit)
+	}
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s facet: %w", q.facet, err)
+	}
+
+	buckets, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (FacetBucket, error) {
+		var bucket FacetBucket
+		var label *string
+		if err := row.Scan(&bucket.Key, &label, &bucket.Count); err != nil {
+			return bucket, err
+		}
+		if label != nil {
+			bucket.Label = *label
+		}
+		return bucket, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s facet: %w", q.facet, err)
+	}
+
+	return buckets, nil
+}
+
+// withoutFacetFilter возвращает копию фильтра без фильтра, которым выбирается значение фасета.
+func withoutFacetFilter(filter *EventFilter, facet string) *EventFilter {
+	unfiltered := *filter
+	unfiltered.Limit, unfiltered.Offset, unfiltered.After = nil, nil, nil
+
+	switch facet {
+	case FacetCategories:
+		unfiltered.CategoryIDs = nil
+	case FacetSources:
+		unfiltered.Source = nil
+	case FacetCities:
+		unfiltered.Cities = nil
+	case FacetVenues:
+		unfiltered.VenueIDs = nil
+	case FacetPrice:
+		unfiltered.MinPrice, unfiltered.MaxPrice = nil, nil
+	case FacetDates:
+		unfiltered.DateFrom, unfiltered.DateTo = nil, nil
+	}
+
+	return &unfiltered
+}
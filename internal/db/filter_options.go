@@ -5,21 +5,39 @@ import "time"
 // EventFilter содержит фильтры для событий в PostgreSQL
 // Полнотекстовый поиск и некоторые сложные фильтры теперь обрабатываются через Elasticsearch
 type EventFilter struct {
-	CategoryIDs []int64    // Фильтр по массиву ID категорий
-	MinPrice    *float32   // Минимальная цена (включительно)
-	MaxPrice    *float32   // Максимальная цена (включительно)
-	DateFrom    *time.Time // Дата начала диапазона (включительно)
-	DateTo      *time.Time // Дата окончания диапазона (включительно)
-	Location    *string    // Фильтр по локации (точное совпадение)
-	Source      *string    // Фильтр по источнику события (точное совпадение)
+	CategoryIDs  []int64    // Фильтр по массиву ID категорий
+	MinPrice     *float32   // Минимальная цена (включительно)
+	MaxPrice     *float32   // Максимальная цена (включительно)
+	IsFree       *bool      // Только бесплатные (true) или только платные (false) события
+	DateFrom     *time.Time // Начало диапазона (включительно), момент времени с учетом часового пояса
+	DateTo       *time.Time // День окончания диапазона (включительно, до конца суток в его часовом поясе)
+	Location     *string    // Фильтр по локации (точное совпадение)
+	Source       *string    // Фильтр по источнику события (точное совпадение)
+	TagsAny      []string   // Событие имеет хотя бы один из тегов (slug)
+	TagsAll      []string   // Событие имеет все перечисленные теги (slug)
+	Statuses     []string   // Допустимые статусы события, пусто - любые
+	OrganizerIDs []int64    // События любого из организаторов
+	VenueIDs     []int64    // События на любой из площадок
+	Cities       []string   // События в любом из городов площадки (точное совпадение)
 
 	// Пагинация
-	Limit  *int // Лимит количества записей для пагинации
-	Offset *int // Смещение для пагинации
+	Limit  *int         // Лимит количества записей для пагинации
+	Offset *int         // Смещение для пагинации
+	After  *EventCursor // Keyset-пагинация: события строго после курсора, несовместима с Offset
+
+	// Сортировка, пусто - DefaultEventSort
+	Sort []EventSort
 
 	// Примечание: SearchText удален - теперь используется Elasticsearch
 }
 
+// EventCursor позиция keyset-пагинации: значения ключей сортировки и ID последнего
+// события страницы. Пустое значение nullable ключа - nil.
+type EventCursor struct {
+	Values []any
+	ID     int64
+}
+
 // FilterOption функциональная опция для конфигурации фильтра.
 type FilterOption func(*EventFilter)
 
@@ -97,6 +115,60 @@ func WithSource(source string) FilterOption {
 	}
 }
 
+// WithIsFree добавляет фильтр по бесплатности события.
+// Бесплатным считается событие, у которого все билеты бесплатны.
+func WithIsFree(isFree bool) FilterOption {
+	return func(f *EventFilter) {
+		f.IsFree = &isFree
+	}
+}
+
+// WithTagsAny добавляет фильтр "любой из тегов".
+// Теги сравниваются без учета регистра и лишних пробелов.
+func WithTagsAny(tags ...string) FilterOption {
+	return func(f *EventFilter) {
+		f.TagsAny = TagSlugs(tags)
+	}
+}
+
+// WithTagsAll добавляет фильтр "все теги сразу".
+// Теги сравниваются без учета регистра и лишних пробелов.
+func WithTagsAll(tags ...string) FilterOption {
+	return func(f *EventFilter) {
+		f.TagsAll = TagSlugs(tags)
+	}
+}
+
+// WithStatuses ограничивает выборку событиями в перечисленных статусах.
+// Публичные списки используют WithStatuses(PublicStatuses()...).
+func WithStatuses(statuses ...string) FilterOption {
+	return func(f *EventFilter) {
+		f.Statuses = statuses
+	}
+}
+
+// WithOrganizers ограничивает выборку событиями перечисленных организаторов.
+func WithOrganizers(organizerIDs ...int64) FilterOption {
+	return func(f *EventFilter) {
+		f.OrganizerIDs = organizerIDs
+	}
+}
+
+// WithVenues ограничивает выборку событиями на перечисленных площадках.
+func WithVenues(venueIDs ...int64) FilterOption {
+	return func(f *EventFilter) {
+		f.VenueIDs = venueIDs
+	}
+}
+
+// WithCities ограничивает выборку событиями в перечисленных городах площадки.
+// Поиск регистрозависимый.
+func WithCities(cities ...string) FilterOption {
+	return func(f *EventFilter) {
+		f.Cities = cities
+	}
+}
+
 // WithPagination добавляет параметры пагинации.
 // limit - максимальное количество записей в ответе.
 // offset - количество записей, которые нужно пропустить.
@@ -116,6 +188,21 @@ func WithLimit(limit int) FilterOption {
 	}
 }
 
+// WithAfter продолжает выборку после курсора предыдущей страницы.
+// В отличие от offset, не просматривает пропущенные строки.
+func WithAfter(cursor EventCursor) FilterOption {
+	return func(f *EventFilter) {
+		f.After = &cursor
+	}
+}
+
+// WithSort задает порядок выборки. Поддерживаются поля из eventSortColumns.
+func WithSort(sorts ...EventSort) FilterOption {
+	return func(f *EventFilter) {
+		f.Sort = sorts
+	}
+}
+
 // NewEventFilter создает новый фильтр с применением переданных опций.
 func NewEventFilter(opts ...FilterOption) *EventFilter {
 	filter := &EventFilter{}
@@ -131,15 +218,66 @@ func (f *EventFilter) IsEmpty() bool {
 	return len(f.CategoryIDs) == 0 &&
 		f.MinPrice == nil &&
 		f.MaxPrice == nil &&
+		f.IsFree == nil &&
 		f.DateFrom == nil &&
 		f.DateTo == nil &&
 		f.Location == nil &&
-		f.Source == nil
+		f.Source == nil &&
+		len(f.TagsAny) == 0 &&
+		len(f.TagsAll) == 0 &&
+		len(f.Statuses) == 0 &&
+		len(f.OrganizerIDs) == 0 &&
+		len(f.VenueIDs) == 0 &&
+		len(f.Cities) == 0
 }
 
 // HasPagination проверяет, установлены ли параметры пагинации.
 func (f *EventFilter) HasPagination() bool {
-	return f.Limit != nil || f.Offset != nil
+	return f.Limit != nil || f.Offset != nil || f.After != nil
+}
+
+// NextCursor возвращает курсор следующей страницы по событиям текущей.
+// Курсор есть только у полной страницы: при limit без следующих событий
+// последняя страница окажется пустой.
+func (f *EventFilter) NextCursor(events []*Event) *EventCursor {
+	if f.Limit == nil || len(events) == 0 || len(events) < *f.Limit {
+		return nil
+	}
+	last := events[len(events)-1]
+
+	sorts := f.GetSort()
+	values := make([]any, len(sorts))
+	for i, sort := range sorts {
+		values[i] = sortValue(last, sort.Field)
+	}
+	return &EventCursor{Values: values, ID: last.Id}
+}
+
+// GetSort возвращает сортировку или сортировку по умолчанию.
+func (f *EventFilter) GetSort() []EventSort {
+	if len(f.Sort) == 0 {
+		return DefaultEventSort()
+	}
+	return f.Sort
+}
+
+// HasDateRange проверяет, задан ли диапазон дат.
+// Повторяющиеся события разворачиваются во вхождения только при заданном диапазоне.
+func (f *EventFilter) HasDateRange() bool {
+	return f.DateFrom != nil || f.DateTo != nil
+}
+
+// DateWindow возвращает диапазон дат как полуинтервал [from, to).
+// Незаданные границы возвращаются нулевыми.
+func (f *EventFilter) DateWindow() (time.Time, time.Time) {
+	var from, to time.Time
+	if f.DateFrom != nil {
+		from = *f.DateFrom
+	}
+	if f.DateTo != nil {
+		to = f.DateTo.AddDate(0, 0, 1)
+	}
+	return from, to
 }
 
 // GetLimit возвращает лимит или значение по умолчанию.
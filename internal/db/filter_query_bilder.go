@@ -10,67 +10,20 @@ import (
 // Полнотекстовый поиск убран - теперь используется Elasticsearch.
 func (s *PostgresStore) buildFilteredQuery(filter *EventFilter) (string, []any) {
 	// Базовый SELECT запрос с теми же полями что и в других методах
-	baseQuery := `SELECT id, name, description, category_id, date, time, location, price, image, source, created_at, updated_at FROM events`
-
-	var conditions []string
-	var args []any
-	argIndex := 1
+	baseQuery := `SELECT ` + eventColumns + eventsFromClause
 
 	// == Условия фильтрации == \\
 
-	// Фильтр по категориям (IN clause для множественного выбора)
-	if len(filter.CategoryIDs) > 0 {
-		placeholders := make([]string, len(filter.CategoryIDs))
-		for i, categoryID := range filter.CategoryIDs {
-			placeholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, categoryID)
-			argIndex++
-		}
-		conditions = append(conditions, fmt.Sprintf("category_id IN (%s)", strings.Join(placeholders, ",")))
-	}
-
-	// Фильтр по минимальной цене
-	if filter.MinPrice != nil {
-		conditions = append(conditions, fmt.Sprintf("price >= $%d", argIndex))
-		args = append(args, *filter.MinPrice)
-		argIndex++
-	}
-
-	// Фильтр по максимальной цене
-	if filter.MaxPrice != nil {
-		conditions = append(conditions, fmt.Sprintf("price <= $%d", argIndex))
-		args = append(args, *filter.MaxPrice)
-		argIndex++
-	}
-
-	// Фильтр по дате от (больше или равно)
-	if filter.DateFrom != nil {
-		dateFromStr := filter.DateFrom.Format("2006-01-02")
-		conditions = append(conditions, fmt.Sprintf("date >= $%d", argIndex))
-		args = append(args, dateFromStr)
-		argIndex++
-	}
-
-	// Фильтр по дате до (меньше или равно)
-	if filter.DateTo != nil {
-		dateToStr := filter.DateTo.Format("2006-01-02")
-		conditions = append(conditions, fmt.Sprintf("date <= $%d", argIndex))
-		args = append(args, dateToStr)
-		argIndex++
-	}
-
-	// Фильтр по точному совпадению локации
-	if filter.Location != nil {
-		conditions = append(conditions, fmt.Sprintf("location = $%d", argIndex))
-		args = append(args, *filter.Location)
-		argIndex++
-	}
+	conditions, args := buildFilterConditions(filter)
+	argIndex := len(args) + 1
 
-	// Фильтр по точному совпадению источника
-	if filter.Source != nil {
-		conditions = append(conditions, fmt.Sprintf("source = $%d", argIndex))
-		args = append(args, *filter.Source)
-		argIndex++
+	// Keyset-пагинация: строго после последнего события предыдущей страницы.
+	// Только в выборке, общее количество считается без курсора
+	if filter.After != nil {
+		condition, cursorArgs := buildCursorCondition(filter.GetSort(), filter.After, argIndex)
+		conditions = append(conditions, condition)
+		args = append(args, cursorArgs...)
+		argIndex += len(cursorArgs)
 	}
 
 	// == Собираем запрос == \\
@@ -80,8 +33,8 @@ func (s *PostgresStore) buildFilteredQuery(filter *EventFilter) (string, []any)
 		baseQuery += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	// Сортировка по дате создания (новые сверху)
-	baseQuery += " ORDER BY created_at DESC"
+	// Сортировка, id делает порядок однозначным для курсора
+	baseQuery += " ORDER BY " + buildOrderBy(filter.GetSort())
 
 	// Пагинация: LIMIT
 	if filter.Limit != nil {
@@ -100,72 +53,262 @@ func (s *PostgresStore) buildFilteredQuery(filter *EventFilter) (string, []any)
 	return baseQuery, args
 }
 
+// buildOrderBy строит список ORDER BY. Пустые значения nullable колонок идут в конце,
+// последним ключом идет id в направлении последнего ключа сортировки.
+func buildOrderBy(sorts []EventSort) string {
+	parts := make([]string, 0, len(sorts)+1)
+	for _, sort := range sorts {
+		column := eventSortColumns[sort.Field]
+		part := column.expr + sortDirection(sort.Descending)
+		if column.nullable {
+			part += " NULLS LAST"
+		}
+		parts = append(parts, part)
+	}
+	parts = append(parts, "events.id"+sortDirection(sorts[len(sorts)-1].Descending))
+	return strings.Join(parts, ", ")
+}
+
+// buildCursorCondition строит условие "строка после курсора" для сортировки sorts:
+// (k1 после v1) OR (k1 = v1 AND k2 после v2) OR ... OR (все ключи равны AND id после ID).
+// Плейсхолдеры нумеруются с argIndex.
+func buildCursorCondition(sorts []EventSort, cursor *EventCursor, argIndex int) (string, []any) {
+	if row, args, ok := buildRowCursorCondition(sorts, cursor, argIndex); ok {
+		return row, args
+	}
+
+	var args []any
+	var equal []string
+	var branches []string
+
+	for i, sort := range sorts {
+		column := eventSortColumns[sort.Field]
+		value := cursor.Values[i]
+
+		// При NULLS LAST после пустого значения идут только такие же пустые
+		if value == nil {
+			equal = append(equal, column.expr+" IS NULL")
+			continue
+		}
+
+		placeholder := fmt.Sprintf("$%d", argIndex)
+		args = append(args, value)
+		argIndex++
+
+		after := column.expr + sortOperator(sort.Descending) + placeholder
+		if column.nullable {
+			after = "(" + after + " OR " + column.expr + " IS NULL)"
+		}
+		branches = append(branches, joinConditions(append(equal, after)))
+		equal = append(equal, column.expr+" = "+placeholder)
+	}
+
+	idAfter := fmt.Sprintf("events.id%s$%d", sortOperator(sorts[len(sorts)-1].Descending), argIndex)
+	args = append(args, cursor.ID)
+	branches = append(branches, joinConditions(append(equal, idAfter)))
+
+	return "(" + strings.Join(branches, " OR ") + ")", args
+}
+
+// buildRowCursorCondition строит условие сравнением строк (k1, ..., id) < (v1, ..., ID),
+// которое читается из составного индекса. Подходит, только если все ключи непустые
+// и отсортированы в одном направлении, как сортировка по умолчанию.
+func buildRowCursorCondition(sorts []EventSort, cursor *EventCursor, argIndex int) (string, []any, bool) {
+	descending := sorts[0].Descending
+	columns := make([]string, 0, len(sorts)+1)
+	placeholders := make([]string, 0, len(sorts)+1)
+	args := make([]any, 0, len(sorts)+1)
+
+	for i, sort := range sorts {
+		column := eventSortColumns[sort.Field]
+		if column.nullable || sort.Descending != descending {
+			return "", nil, false
+		}
+		columns = append(columns, column.expr)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", argIndex+i))
+		args = append(args, cursor.Values[i])
+	}
+	columns = append(columns, "events.id")
+	placeholders = append(placeholders, fmt.Sprintf("$%d", argIndex+len(sorts)))
+	args = append(args, cursor.ID)
+
+	condition := "(" + strings.Join(columns, ", ") + ")" + sortOperator(descending) +
+		"(" + strings.Join(placeholders, ", ") + ")"
+	return condition, args, true
+}
+
+// joinConditions объединяет условия через AND
+func joinConditions(conditions []string) string {
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")"
+}
+
+// sortDirection направление ORDER BY
+func sortDirection(descending bool) string {
+	if descending {
+		return " DESC"
+	}
+	return " ASC"
+}
+
+// sortOperator оператор сравнения "идет после" для направления сортировки
+func sortOperator(descending bool) string {
+	if descending {
+		return " < "
+	}
+	return " > "
+}
+
 // buildCountQuery строит запрос для подсчета общего количества записей с учетом фильтров.
 // Используется для реализации пагинации с информацией об общем количестве.
 func (s *PostgresStore) buildCountQuery(filter *EventFilter) (string, []any) {
-	baseQuery := `SELECT COUNT(*) FROM events`
+	baseQuery := `SELECT COUNT(*)` + eventsFromClause
+
+	// Применяем те же условия фильтрации, что и в основном запросе (без ORDER BY, LIMIT, OFFSET)
+	conditions, args := buildFilterConditions(filter)
+
+	// Добавляем WHERE условия
+	if len(conditions) > 0 {
+		baseQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return baseQuery, args
+}
 
+// buildFilterConditions строит WHERE условия фильтра.
+// Общая часть для buildFilteredQuery и buildCountQuery, плейсхолдеры нумеруются с $1.
+// Колонки events квалифицируются явно: запросы соединяют events с venues и organizers.
+func buildFilterConditions(filter *EventFilter) ([]string, []any) {
 	var conditions []string
 	var args []any
 	argIndex := 1
 
-	// Применяем те же условия фильтрации, что и в основном запросе
-	// (копируем логику из buildFilteredQuery, но без ORDER BY, LIMIT, OFFSET)
+	// Мягко удаленные события не попадают ни в одну выборку
+	conditions = append(conditions, activeEventsCondition)
 
+	// Фильтр по категориям: родительская категория включает всех потомков
 	if len(filter.CategoryIDs) > 0 {
-		placeholders := make([]string, len(filter.CategoryIDs))
-		for i, categoryID := range filter.CategoryIDs {
-			placeholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, categoryID)
-			argIndex++
-		}
-		conditions = append(conditions, fmt.Sprintf("category_id IN (%s)", strings.Join(placeholders, ",")))
+		conditions = append(conditions, fmt.Sprintf(
+			`events.category_id IN (WITH RECURSIVE sub AS (
+				SELECT id FROM categories WHERE id = ANY($%d)
+				UNION
+				SELECT c.id FROM categories c JOIN sub ON c.parent_id = sub.id
+			) SELECT id FROM sub)`,
+			argIndex,
+		))
+		args = append(args, filter.CategoryIDs)
+		argIndex++
 	}
 
+	// Фильтр по цене: диапазон [price_from, price_to] события пересекается с запрошенным.
+	// Событие "от 500 до 3000" попадает и в min_price=2000, и в max_price=1000.
 	if filter.MinPrice != nil {
-		conditions = append(conditions, fmt.Sprintf("price >= $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("events.price_to >= $%d", argIndex))
 		args = append(args, *filter.MinPrice)
 		argIndex++
 	}
 
 	if filter.MaxPrice != nil {
-		conditions = append(conditions, fmt.Sprintf("price <= $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("events.price_from <= $%d", argIndex))
 		args = append(args, *filter.MaxPrice)
 		argIndex++
 	}
 
+	// Бесплатные события: все билеты бесплатны. Событие с бесплатным
+	// детским билетом находится фильтром max_price=0
+	if filter.IsFree != nil {
+		if *filter.IsFree {
+			conditions = append(conditions, "events.price_to = 0")
+		} else {
+			conditions = append(conditions, "events.price_to > 0")
+		}
+	}
+
+	// Фильтр по дате от: событие еще идет или начнется после начала диапазона.
+	// Событие без ends_at считается точечным (заканчивается в момент начала).
+	// Серия подходит, если ее последнее вхождение не закончилось (или она бесконечна),
+	// конкретные вхождения разворачиваются уже после выборки.
 	if filter.DateFrom != nil {
-		dateFromStr := filter.DateFrom.Format("2006-01-02")
-		conditions = append(conditions, fmt.Sprintf("date >= $%d", argIndex))
-		args = append(args, dateFromStr)
+		conditions = append(conditions, fmt.Sprintf(
+			"(COALESCE(events.ends_at, events.starts_at) >= $%d OR (events.recurrence_rule IS NOT NULL AND (events.recurrence_until IS NULL OR events.recurrence_until >= $%d)))",
+			argIndex, argIndex,
+		))
+		args = append(args, *filter.DateFrom)
 		argIndex++
 	}
 
+	// Фильтр по дате до: событие (или первое вхождение серии) начинается не позже конца дня DateTo
 	if filter.DateTo != nil {
-		dateToStr := filter.DateTo.Format("2006-01-02")
-		conditions = append(conditions, fmt.Sprintf("date <= $%d", argIndex))
-		args = append(args, dateToStr)
+		conditions = append(conditions, fmt.Sprintf("events.starts_at < $%d", argIndex))
+		args = append(args, filter.DateTo.AddDate(0, 0, 1))
 		argIndex++
 	}
 
+	// Фильтр по точному совпадению локации
 	if filter.Location != nil {
-		conditions = append(conditions, fmt.Sprintf("location = $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("events.location = $%d", argIndex))
 		args = append(args, *filter.Location)
 		argIndex++
 	}
 
+	// Фильтр по точному совпадению источника
 	if filter.Source != nil {
-		conditions = append(conditions, fmt.Sprintf("source = $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("events.source = $%d", argIndex))
 		args = append(args, *filter.Source)
 		argIndex++
 	}
 
-	// Добавляем WHERE условия
-	if len(conditions) > 0 {
-		baseQuery += " WHERE " + strings.Join(conditions, " AND ")
+	// Фильтр "любой из тегов"
+	if len(filter.TagsAny) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM event_tags et JOIN tags t ON t.id = et.tag_id WHERE et.event_id = events.id AND t.slug = ANY($%d))",
+			argIndex,
+		))
+		args = append(args, filter.TagsAny)
+		argIndex++
 	}
 
-	return baseQuery, args
+	// Фильтр "все теги": количество совпавших тегов равно количеству запрошенных
+	if len(filter.TagsAll) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"(SELECT COUNT(*) FROM event_tags et JOIN tags t ON t.id = et.tag_id WHERE et.event_id = events.id AND t.slug = ANY($%d)) = $%d",
+			argIndex, argIndex+1,
+		))
+		args = append(args, filter.TagsAll, len(filter.TagsAll))
+		argIndex += 2
+	}
+
+	// Фильтр по статусу публикации
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, fmt.Sprintf("events.status = ANY($%d)", argIndex))
+		args = append(args, filter.Statuses)
+		argIndex++
+	}
+
+	// Фильтр по организаторам
+	if len(filter.OrganizerIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("events.organizer_id = ANY($%d)", argIndex))
+		args = append(args, filter.OrganizerIDs)
+		argIndex++
+	}
+
+	// Фильтр по площадкам
+	if len(filter.VenueIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("events.venue_id = ANY($%d)", argIndex))
+		args = append(args, filter.VenueIDs)
+		argIndex++
+	}
+
+	// Фильтр по городам площадок
+	if len(filter.Cities) > 0 {
+		conditions = append(conditions, fmt.Sprintf("venues.city = ANY($%d)", argIndex))
+		args = append(args, filter.Cities)
+		argIndex++
+	}
+
+	return conditions, args
 }
 
 // validateFilter проверяет корректность параметров фильтра.
@@ -185,6 +328,26 @@ func validateFilter(filter *EventFilter) error {
 		return fmt.Errorf("offset cannot be negative, got: %d", *filter.Offset)
 	}
 
+	if filter.After != nil && filter.Offset != nil && *filter.Offset > 0 {
+		return fmt.Errorf("offset cannot be combined with a cursor")
+	}
+
+	// Проверяем сортировку: релевантность и расстояние есть только в OpenSearch
+	seen := make(map[string]bool, len(filter.Sort))
+	for _, sort := range filter.Sort {
+		if _, ok := eventSortColumns[sort.Field]; !ok {
+			return fmt.Errorf("sort field %q is not supported", sort.Field)
+		}
+		if seen[sort.Field] {
+			return fmt.Errorf("duplicate sort field %q", sort.Field)
+		}
+		seen[sort.Field] = true
+	}
+
+	if filter.After != nil && len(filter.After.Values) != len(filter.GetSort()) {
+		return fmt.Errorf("cursor does not match sort")
+	}
+
 	// Проверяем диапазон цен
 	if filter.MinPrice != nil && *filter.MinPrice < 0 {
 		return fmt.Errorf("min_price cannot be negative, got: %f", *filter.MinPrice)
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Роли изображений события
+const (
+	ImageRoleCover   = "cover"   // Обложка: одна на событие, ее URL хранится в events.image и попадает в поиск
+	ImageRoleGallery = "gallery" // Фотографии галереи
+	ImageRolePoster  = "poster"  // Афиши
+)
+
+// Ограничения полей изображения (соответствуют колонкам event_images)
+const (
+	MaxImageURLLength     = 2048
+	MaxImageAltTextLength = 500
+)
+
+// ErrImagesMismatch возвращается, если новый порядок не содержит ровно все изображения события
+var ErrImagesMismatch = errors.New("image ids do not match event images")
+
+const (
+	// eventImagesColumn подзапрос изображений события для списка колонок SELECT
+	eventImagesColumn = `COALESCE((SELECT json_agg(json_build_object(
+						'id', ei.id, 'url', ei.url, 'width', ei.width, 'height', ei.height,
+						'alt_text', ei.alt_text, 'role', ei.role
+					) ORDER BY ei.position, ei.id) FROM event_images ei WHERE ei.event_id = events.id), '[]') AS images`
+
+	// Новое изображение добавляется в конец списка
+	addEventImageQuery = `INSERT INTO event_images (event_id, url, width, height, alt_text, role, position)
+						SELECT $1, $2, $3, $4, $5, $6, COALESCE(MAX(position), 0) + 1
+						FROM event_images WHERE event_id = $1
+						RETURNING id`
+
+	// Обложка заменяется на месте; размеры и подпись прежнего файла сбрасываются только при смене URL
+	setEventCoverQuery = `INSERT INTO event_images (event_id, url, role, position)
+						SELECT $1, $2, 'cover', COALESCE(MAX(position), 0) + 1
+						FROM event_images WHERE event_id = $1
+						ON CONFLICT (event_id) WHERE role = 'cover' DO UPDATE
+						SET url = EXCLUDED.url, width = NULL, height = NULL, alt_text = ''
+						WHERE event_images.url <> EXCLUDED.url`
+
+	deleteEventCoverQuery = `DELETE FROM event_images WHERE event_id = $1 AND role = 'cover'`
+	demoteEventCoverQuery = `UPDATE event_images SET role = 'gallery' WHERE event_id = $1 AND role = 'cover'`
+	removeEventImageQuery = `DELETE FROM event_images WHERE event_id = $1 AND id = $2`
+	eventImageIdsQuery    = `SELECT id FROM event_images WHERE event_id = $1 ORDER BY id`
+
+	reorderEventImagesQuery = `UPDATE event_images ei SET position = t.position
+						FROM unnest($2::bigint[]) WITH ORDINALITY AS t(id, position)
+						WHERE ei.event_id = $1 AND ei.id = t.id`
+
+	// Изменение изображений меняет ответ события, поэтому версия растет.
+	// events.image повторяет URL обложки, чтобы у поля image был один источник
+	touchEventQuery = `UPDATE events SET image = COALESCE((SELECT ei.url FROM event_images ei
+						WHERE ei.event_id = events.id AND ei.role = 'cover'), ''),
+						version = version + 1, updated_at = NOW()
+						WHERE id = $1 AND deleted_at IS NULL`
+)
+
+// AddEventImage добавляет изображение в конец списка изображений события.
+// Новая обложка переводит прежнюю в галерею. Возвращает событие после изменения.
+func (s *PostgresStore) AddEventImage(parentCtx context.Context, eventID int64, image *EventImage) (*Event, error) {
+	return s.changeEventImages(parentCtx, eventID, func(ctx context.Context, tx *PostgresStore) error {
+		if image.Role == ImageRoleCover {
+			if _, err := tx.db.Exec(ctx, demoteEventCoverQuery, eventID); err != nil {
+				return fmt.Errorf("failed to demote cover of event %d: %w", eventID, err)
+			}
+		}
+
+		err := tx.db.QueryRow(
+			ctx,
+			addEventImageQuery,
+			eventID,
+			image.URL,
+			image.Width,
+			image.Height,
+			image.AltText,
+			image.Role,
+		).Scan(&image.Id)
+		if err != nil {
+			return fmt.Errorf("failed to add image to event %d: %w", eventID, err)
+		}
+
+		return nil
+	})
+}
+
+// RemoveEventImage удаляет изображение события.
+func (s *PostgresStore) RemoveEventImage(parentCtx context.Context, eventID, imageID int64) (*Event, error) {
+	return s.changeEventImages(parentCtx, eventID, func(ctx context.Context, tx *PostgresStore) error {
+		cmdTag, err := tx.db.Exec(ctx, removeEventImageQuery, eventID, imageID)
+		if err != nil {
+			return fmt.Errorf("failed to remove image %d of event %d: %w", imageID, eventID, err)
+		}
+		if cmdTag.RowsAffected() == 0 {
+			return fmt.Errorf("image %d of event %d not found: %w", imageID, eventID, pgx.ErrNoRows)
+		}
+		return nil
+	})
+}
+
+// ReorderEventImages задает порядок изображений события. imageIDs должны
+// содержать ровно все изображения события, иначе возвращается ErrImagesMismatch.
+func (s *PostgresStore) ReorderEventImages(parentCtx context.Context, eventID int64, imageIDs []int64) (*Event, error) {
+	return s.changeEventImages(parentCtx, eventID, func(ctx context.Context, tx *PostgresStore) error {
+		rows, err := tx.db.Query(ctx, eventImageIdsQuery, eventID)
+		if err != nil {
+			return fmt.Errorf("failed to query images of event %d: %w", eventID, err)
+		}
+		current, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+		if err != nil {
+			return fmt.Errorf("failed to collect images of event %d: %w", eventID, err)
+		}
+
+		requested := slices.Clone(imageIDs)
+		slices.Sort(requested)
+		if !slices.Equal(current, requested) {
+			return fmt.Errorf("event %d: %w", eventID, ErrImagesMismatch)
+		}
+
+		if _, err := tx.db.Exec(ctx, reorderEventImagesQuery, eventID, imageIDs); err != nil {
+			return fmt.Errorf("failed to reorder images of event %d: %w", eventID, err)
+		}
+		return nil
+	})
+}
+
+// setEventCover делает url обложкой события, пустой url удаляет обложку.
+// Вызывается внутри транзакции, которая записывает тот же url в events.image.
+func (s *PostgresStore) setEventCover(ctx context.Context, eventID int64, url string) error {
+	if url == "" {
+		if _, err := s.db.Exec(ctx, deleteEventCoverQuery, eventID); err != nil {
+			return fmt.Errorf("failed to remove cover of event %d: %w", eventID, err)
+		}
+		return nil
+	}
+
+	if _, err := s.db.Exec(ctx, setEventCoverQuery, eventID, url); err != nil {
+		return fmt.Errorf("failed to set cover of event %d: %w", eventID, err)
+	}
+	return nil
+}
+
+// changeEventImages выполняет изменение изображений неудаленного события в транзакции:
+// блокирует событие, увеличивает его версию и записывает ревизию.
+func (s *PostgresStore) changeEventImages(parentCtx context.Context, eventID int64, change func(ctx context.Context, tx *PostgresStore) error) (*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var updated *Event
+	err := s.inTx(ctx, func(tx *PostgresStore) error {
+		previous, err := tx.getEventForUpdate(ctx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := change(ctx, tx); err != nil {
+			return err
+		}
+
+		if _, err := tx.db.Exec(ctx, touchEventQuery, eventID); err != nil {
+			return fmt.Errorf("failed to touch event %d: %w", eventID, err)
+		}
+
+		updated, err = tx.recordEventRevision(ctx, eventID, RevisionUpdate, previous)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// IsValidImageRole проверяет роль изображения.
+func IsValidImageRole(role string) bool {
+	switch role {
+	case ImageRoleCover, ImageRoleGallery, ImageRolePoster:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// Старые ID, уже перенаправленные на объединяемое событие, переводятся на новое каноническое
+	insertEventRedirectQuery = `INSERT INTO event_redirects (event_id, canonical_id) VALUES ($1, $2)
+						ON CONFLICT (event_id) DO UPDATE SET canonical_id = EXCLUDED.canonical_id, merged_at = NOW()`
+	moveEventRedirectsQuery = `UPDATE event_redirects SET canonical_id = $1 WHERE canonical_id = ANY($2)`
+	getEventRedirectQuery   = `SELECT canonical_id FROM event_redirects WHERE event_id = $1`
+
+	getEventsStartingBetweenQuery = getEventsQuery + ` AND events.starts_at >= $1 AND events.starts_at < $2
+						ORDER BY events.starts_at, events.id LIMIT $3`
+)
+
+// MergeEvents объединяет дубликаты duplicateIDs в каноническое событие canonicalID:
+// дубликаты мягко удаляются с ревизией RevisionMerge, а их ID перенаправляются на каноническое.
+// Все изменения записываются в одной транзакции. Возвращает каноническое событие и удаленные дубликаты.
+func (s *PostgresStore) MergeEvents(parentCtx context.Context, canonicalID int64, duplicateIDs []int64) (*Event, []*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	if slices.Contains(duplicateIDs, canonicalID) {
+		return nil, nil, fmt.Errorf("event %d cannot be merged into itself", canonicalID)
+	}
+
+	var canonical *Event
+	var merged []*Event
+	err := s.inTx(ctx, func(tx *PostgresStore) error {
+		// Блокируем строки в порядке ID, чтобы параллельные слияния не взаимоблокировались
+		ids := append([]int64{canonicalID}, duplicateIDs...)
+		slices.Sort(ids)
+		ids = slices.Compact(ids)
+
+		locked := make(map[int64]*Event, len(ids))
+		for _, id := range ids {
+			event, err := tx.getEventForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			locked[id] = event
+		}
+		canonical = locked[canonicalID]
+
+		var mergedIDs []int64
+		for _, id := range ids {
+			if id == canonicalID {
+				continue
+			}
+
+			if _, err := tx.db.Exec(ctx, deleteEventQuery, id); err != nil {
+				return fmt.Errorf("failed to delete merged event %d: %w", id, err)
+			}
+			if _, err := tx.db.Exec(ctx, insertEventRedirectQuery, id, canonicalID); err != nil {
+				return fmt.Errorf("failed to redirect event %d to %d: %w", id, canonicalID, err)
+			}
+
+			event, err := tx.recordEventRevision(ctx, id, RevisionMerge, locked[id])
+			if err != nil {
+				return err
+			}
+			merged = append(merged, event)
+			mergedIDs = append(mergedIDs, id)
+		}
+
+		if _, err := tx.db.Exec(ctx, moveEventRedirectsQuery, canonicalID, mergedIDs); err != nil {
+			return fmt.Errorf("failed to move redirects to event %d: %w", canonicalID, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return canonical, merged, nil
+}
+
+// ResolveEventRedirect возвращает ID канонического события, в которое было объединено событие id.
+// Если событие не объединялось, возвращается ошибка с pgx.ErrNoRows.
+func (s *PostgresStore) ResolveEventRedirect(parentCtx context.Context, id int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var canonicalID int64
+	if err := s.db.QueryRow(ctx, getEventRedirectQuery, id).Scan(&canonicalID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("event %d has no redirect: %w", id, err)
+		}
+		return 0, fmt.Errorf("failed to get redirect of event %d: %w", id, err)
+	}
+
+	return canonicalID, nil
+}
+
+// GetEventsStartingBetween извлекает не больше limit событий, начинающихся в [from, to), по времени начала.
+func (s *PostgresStore) GetEventsStartingBetween(parentCtx context.Context, from, to time.Time, limit int) ([]*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, getEventsStartingBetweenQuery, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events starting between %s and %s: %w", from, to, err)
+	}
+	defer rows.Close()
+
+	events := []*Event{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event during GetEventsStartingBetween: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event rows: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_events_ends_at;
+DROP INDEX IF EXISTS idx_events_starts_at;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_ends_after_starts;
+
+ALTER TABLE events
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS ends_at,
+    DROP COLUMN IF EXISTS starts_at;
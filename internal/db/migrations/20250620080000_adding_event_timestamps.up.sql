@@ -0,0 +1,31 @@
+-- Реальные моменты начала/окончания события с учетом часового пояса.
+-- Старые строковые колонки date/time пока остаются для обратной совместимости.
+ALTER TABLE events
+    ADD COLUMN starts_at TIMESTAMPTZ,
+    ADD COLUMN ends_at TIMESTAMPTZ,
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow';
+
+-- Переносим данные из строковых колонок.
+-- Учитываем только значения в формате YYYY-MM-DD и HH:MM[:SS], остальное оставляем NULL.
+-- Формат не гарантирует корректность (2025-02-30, 25:00), поэтому приведение
+-- выполняется функцией, возвращающей NULL вместо ошибки.
+CREATE FUNCTION pg_temp.try_parse_timestamp(value TEXT) RETURNS TIMESTAMP AS $$
+BEGIN
+    RETURN value::timestamp;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+UPDATE events
+SET starts_at = pg_temp.try_parse_timestamp(
+        date || ' ' || COALESCE(NULLIF(substring(time from '^\d{1,2}:\d{2}(?::\d{2})?'), ''), '00:00')
+    ) AT TIME ZONE timezone
+WHERE date ~ '^\d{4}-(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])$';
+
+ALTER TABLE events
+    ADD CONSTRAINT events_ends_after_starts CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at >= starts_at);
+
+-- Для фильтрации по диапазону дат
+CREATE INDEX idx_events_starts_at ON events(starts_at);
+CREATE INDEX idx_events_ends_at ON events(ends_at);
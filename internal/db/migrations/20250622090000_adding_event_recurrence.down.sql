@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS event_occurrence_overrides;
+
+DROP INDEX IF EXISTS idx_events_recurrence_until;
+
+ALTER TABLE events
+    DROP COLUMN IF EXISTS recurrence_until,
+    DROP COLUMN IF EXISTS recurrence_exdates,
+    DROP COLUMN IF EXISTS recurrence_rule;
@@ -0,0 +1,26 @@
+-- Повторяющиеся события: правило RFC 5545 (RRULE) и исключенные даты (EXDATE)
+ALTER TABLE events
+    ADD COLUMN recurrence_rule TEXT,
+    ADD COLUMN recurrence_exdates TIMESTAMPTZ[] NOT NULL DEFAULT '{}',
+    -- Окончание последнего вхождения серии, NULL для бесконечных серий.
+    -- Вычисляется приложением при сохранении и используется для фильтрации по датам.
+    ADD COLUMN recurrence_until TIMESTAMPTZ;
+
+CREATE INDEX idx_events_recurrence_until ON events(recurrence_until) WHERE recurrence_rule IS NOT NULL;
+
+-- Изменения отдельных вхождений серии. Сама серия при этом не меняется.
+CREATE TABLE IF NOT EXISTS event_occurrence_overrides (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    occurrence_start TIMESTAMPTZ NOT NULL, -- исходное начало вхождения по правилу
+    name VARCHAR(255),
+    description TEXT,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    location VARCHAR(255),
+    price REAL,
+    is_cancelled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (event_id, occurrence_start)
+);
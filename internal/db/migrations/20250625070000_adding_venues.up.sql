@@ -0,0 +1,44 @@
+-- Площадки проведения событий
+CREATE TABLE IF NOT EXISTS venues (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    -- Имя в нижнем регистре со схлопнутыми пробелами, по нему ищутся дубликаты
+    normalized_name VARCHAR(255) NOT NULL,
+    address VARCHAR(255) NOT NULL DEFAULT '',
+    city VARCHAR(100) NOT NULL DEFAULT '',
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (normalized_name, city),
+    CONSTRAINT venues_coordinates_check CHECK (
+        (latitude IS NULL AND longitude IS NULL)
+        OR (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+    )
+);
+
+ALTER TABLE events
+    ADD COLUMN venue_id INTEGER REFERENCES venues(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_events_venue_id ON events(venue_id);
+
+-- Переносим строки location в площадки.
+-- Написания, отличающиеся регистром и пробелами, считаются одной площадкой,
+-- в качестве имени берется самое частое из них.
+WITH spellings AS (
+    SELECT regexp_replace(btrim(location), '\s+', ' ', 'g') AS name, COUNT(*) AS uses
+    FROM events
+    WHERE location IS NOT NULL AND btrim(location) <> ''
+    GROUP BY 1
+)
+INSERT INTO venues (name, normalized_name)
+SELECT DISTINCT ON (lower(name)) name, lower(name)
+FROM spellings
+ORDER BY lower(name), uses DESC, name
+ON CONFLICT (normalized_name, city) DO NOTHING;
+
+UPDATE events e
+SET venue_id = v.id
+FROM venues v
+WHERE v.city = ''
+  AND v.normalized_name = lower(regexp_replace(btrim(e.location), '\s+', ' ', 'g'));
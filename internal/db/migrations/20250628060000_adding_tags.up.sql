@@ -0,0 +1,17 @@
+-- Теги: дополняют единственную категорию события
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    -- Имя в нижнем регистре со схлопнутыми пробелами, по нему теги сравниваются и фильтруются
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS event_tags (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (event_id, tag_id)
+);
+
+-- Для фильтрации событий по тегу
+CREATE INDEX idx_event_tags_tag_id ON event_tags(tag_id);
@@ -0,0 +1,6 @@
+-- Иерархия категорий. Категорию с подкатегориями удалить нельзя.
+ALTER TABLE categories
+    ADD COLUMN parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT categories_parent_not_self CHECK (parent_id <> id);
+
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
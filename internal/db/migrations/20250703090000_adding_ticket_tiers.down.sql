@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_events_price_range;
+
+ALTER TABLE events
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS price_to,
+    DROP COLUMN IF EXISTS price_from;
+
+DROP TABLE IF EXISTS event_ticket_tiers;
@@ -0,0 +1,28 @@
+-- Билетные категории события: одно событие может стоить "от 500 до 3000" или быть бесплатным для детей
+CREATE TABLE IF NOT EXISTS event_ticket_tiers (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    min_price REAL NOT NULL DEFAULT 0,
+    -- NULL означает фиксированную цену min_price
+    max_price REAL,
+    currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    availability VARCHAR(20) NOT NULL DEFAULT 'available',
+    purchase_url VARCHAR(2048) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT event_ticket_tiers_price_check CHECK (min_price >= 0 AND (max_price IS NULL OR max_price >= min_price)),
+    CONSTRAINT event_ticket_tiers_availability_check CHECK (availability IN ('available', 'limited', 'sold_out', 'unavailable'))
+);
+
+CREATE INDEX idx_event_ticket_tiers_event_id ON event_ticket_tiers(event_id);
+
+-- Диапазон цен события, вычисляется по билетным категориям (или по price, если их нет).
+-- Хранится в events, чтобы фильтр по цене не требовал подзапроса.
+ALTER TABLE events
+    ADD COLUMN price_from REAL,
+    ADD COLUMN price_to REAL,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+UPDATE events SET price_from = price, price_to = price WHERE price IS NOT NULL;
+
+CREATE INDEX idx_events_price_range ON events(price_from, price_to);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_events_scheduled_publish_at;
+DROP INDEX IF EXISTS idx_events_status;
+
+ALTER TABLE events
+    DROP CONSTRAINT IF EXISTS events_scheduled_has_publish_at,
+    DROP CONSTRAINT IF EXISTS events_status_check,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS publish_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,20 @@
+-- Жизненный цикл публикации события
+ALTER TABLE events
+    -- Существующие события уже видны пользователям, поэтому считаются опубликованными
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published',
+    -- Момент (запланированной) публикации
+    ADD COLUMN publish_at TIMESTAMPTZ,
+    -- Причина отмены или переноса
+    ADD COLUMN status_reason TEXT NOT NULL DEFAULT '',
+    ADD CONSTRAINT events_status_check
+        CHECK (status IN ('draft', 'scheduled', 'published', 'cancelled', 'postponed', 'sold_out')),
+    ADD CONSTRAINT events_scheduled_has_publish_at
+        CHECK (status <> 'scheduled' OR publish_at IS NOT NULL);
+
+-- Новые события создаются черновиками
+ALTER TABLE events ALTER COLUMN status SET DEFAULT 'draft';
+
+CREATE INDEX idx_events_status ON events(status);
+
+-- Для планировщика публикаций
+CREATE INDEX idx_events_scheduled_publish_at ON events(publish_at) WHERE status = 'scheduled';
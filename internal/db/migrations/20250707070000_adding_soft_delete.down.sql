@@ -0,0 +1,32 @@
+-- Удаленные события при откате удаляются окончательно
+DELETE FROM events WHERE deleted_at IS NOT NULL;
+
+-- Удаленные категории, на которые ссылаются оставшиеся события или категории (вместе
+-- с их предками), восстанавливаются: иначе удаление нарушило бы внешние ключи.
+-- Имя, совпадающее с именем другой остающейся категории, дополняется id
+WITH RECURSIVE kept AS (
+    SELECT category_id AS id FROM events
+    UNION
+    SELECT id FROM categories WHERE deleted_at IS NULL
+    UNION
+    SELECT c.parent_id FROM categories c JOIN kept ON kept.id = c.id WHERE c.parent_id IS NOT NULL
+)
+UPDATE categories c
+SET deleted_at = NULL,
+    name = CASE WHEN EXISTS (
+        SELECT 1 FROM categories other
+        WHERE other.name = c.name AND other.id <> c.id
+          AND (other.deleted_at IS NULL OR other.id IN (SELECT id FROM kept))
+    ) THEN left(c.name, 90) || ' #' || c.id ELSE c.name END
+WHERE c.deleted_at IS NOT NULL AND c.id IN (SELECT id FROM kept);
+
+DELETE FROM categories WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_categories_deleted_at;
+DROP INDEX IF EXISTS idx_events_deleted_at;
+DROP INDEX IF EXISTS categories_name_active_key;
+
+ALTER TABLE categories ADD CONSTRAINT categories_name_key UNIQUE (name);
+
+ALTER TABLE categories DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE events DROP COLUMN IF EXISTS deleted_at;
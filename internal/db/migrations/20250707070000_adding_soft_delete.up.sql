@@ -0,0 +1,11 @@
+-- Мягкое удаление: строки остаются в таблице до очистки по сроку хранения
+ALTER TABLE events ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Имя должно быть уникальным только среди неудаленных категорий
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_name_key;
+CREATE UNIQUE INDEX categories_name_active_key ON categories(name) WHERE deleted_at IS NULL;
+
+-- Для списка удаленных событий и задачи очистки
+CREATE INDEX idx_events_deleted_at ON events(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_categories_deleted_at ON categories(deleted_at) WHERE deleted_at IS NOT NULL;
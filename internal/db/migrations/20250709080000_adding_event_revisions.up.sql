@@ -0,0 +1,14 @@
+-- История изменений события: полный снимок после каждой записи, измененные поля и автор.
+-- Пишется в той же транзакции, что и само изменение
+CREATE TABLE IF NOT EXISTS event_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT event_revisions_event_revision_key UNIQUE (event_id, revision),
+    CONSTRAINT event_revisions_action_check CHECK (action IN ('create', 'update', 'delete', 'restore', 'status', 'revert'))
+);
@@ -0,0 +1,3 @@
+-- Версия записи для оптимистичной блокировки: увеличивается при каждом изменении строки
+ALTER TABLE events ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
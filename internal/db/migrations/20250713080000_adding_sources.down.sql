@@ -0,0 +1,4 @@
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_source_external_id_key;
+ALTER TABLE events DROP COLUMN IF EXISTS external_id;
+ALTER TABLE events DROP COLUMN IF EXISTS source_id;
+DROP TABLE IF EXISTS sources;
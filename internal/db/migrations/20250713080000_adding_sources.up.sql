@@ -0,0 +1,19 @@
+-- Реестр внешних источников событий (парсеры, партнерские API)
+CREATE TABLE IF NOT EXISTS sources (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(64) NOT NULL UNIQUE,
+    display_name VARCHAR(255) NOT NULL,
+    -- Насколько данным источника можно доверять: 0 - не доверяем, 100 - полностью
+    trust_level SMALLINT NOT NULL DEFAULT 50,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    CONSTRAINT sources_trust_level_check CHECK (trust_level BETWEEN 0 AND 100)
+);
+
+-- Идентификатор события во внешнем источнике. Повторный импорт того же
+-- external_id обновляет событие вместо создания дубликата
+ALTER TABLE events
+    ADD COLUMN source_id INTEGER REFERENCES sources(id),
+    ADD COLUMN external_id VARCHAR(255),
+    ADD CONSTRAINT events_source_external_id_key UNIQUE (source_id, external_id);
@@ -0,0 +1,6 @@
+DELETE FROM event_revisions WHERE action = 'merge';
+ALTER TABLE event_revisions DROP CONSTRAINT event_revisions_action_check;
+ALTER TABLE event_revisions ADD CONSTRAINT event_revisions_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'status', 'revert'));
+
+DROP TABLE IF EXISTS event_redirects;
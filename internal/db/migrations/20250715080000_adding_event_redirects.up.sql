@@ -0,0 +1,13 @@
+-- ID событий, объединенных в каноническое событие при слиянии дубликатов.
+-- Ссылки на старый ID не удаляются вместе с ним, чтобы переадресация пережила очистку корзины
+CREATE TABLE IF NOT EXISTS event_redirects (
+    event_id INTEGER PRIMARY KEY,
+    canonical_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_event_redirects_canonical_id ON event_redirects(canonical_id);
+
+ALTER TABLE event_revisions DROP CONSTRAINT event_revisions_action_check;
+ALTER TABLE event_revisions ADD CONSTRAINT event_revisions_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'restore', 'status', 'revert', 'merge'));
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS event_images;
+
+ALTER TABLE events ALTER COLUMN image TYPE VARCHAR(255) USING left(image, 255);
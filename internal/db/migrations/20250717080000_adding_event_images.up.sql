@@ -0,0 +1,27 @@
+-- Подписанные ссылки CDN длиннее 255 символов
+ALTER TABLE events ALTER COLUMN image TYPE VARCHAR(2048);
+
+-- Изображения события: обложка, галерея и афиши
+CREATE TABLE IF NOT EXISTS event_images (
+    id SERIAL PRIMARY KEY,
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    url VARCHAR(2048) NOT NULL,
+    -- Размеры в пикселях, NULL если неизвестны
+    width INTEGER,
+    height INTEGER,
+    alt_text VARCHAR(500) NOT NULL DEFAULT '',
+    role VARCHAR(20) NOT NULL DEFAULT 'gallery',
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT event_images_role_check CHECK (role IN ('cover', 'gallery', 'poster')),
+    CONSTRAINT event_images_size_check CHECK ((width IS NULL OR width > 0) AND (height IS NULL OR height > 0))
+);
+
+CREATE INDEX idx_event_images_event_id ON event_images(event_id, position);
+
+-- У события не больше одной обложки
+CREATE UNIQUE INDEX event_images_cover_key ON event_images(event_id) WHERE role = 'cover';
+
+-- Единственное изображение существующих событий становится обложкой
+INSERT INTO event_images (event_id, url, role)
+SELECT id, image, 'cover' FROM events WHERE image IS NOT NULL AND image <> '';
@@ -0,0 +1,10 @@
+-- Переводы названия и описания события. Содержимое на языке по умолчанию (ru) хранится в events
+CREATE TABLE IF NOT EXISTS event_translations (
+    event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    -- Тег языка: "en" или "en-GB"
+    locale VARCHAR(10) NOT NULL,
+    -- Пустое значение означает, что поле берется из следующего языка цепочки
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (event_id, locale)
+);
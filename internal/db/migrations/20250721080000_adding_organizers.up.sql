@@ -0,0 +1,17 @@
+-- Организаторы событий: партнеры, которые проводят события
+CREATE TABLE IF NOT EXISTS organizers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    website VARCHAR(2048) NOT NULL DEFAULT '',
+    -- Организатор подтвержден модерацией
+    is_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+ALTER TABLE events
+    ADD COLUMN organizer_id INTEGER REFERENCES organizers(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_events_organizer_id ON events(organizer_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_event_changes_created_at;
+
+DROP TABLE IF EXISTS event_changes;
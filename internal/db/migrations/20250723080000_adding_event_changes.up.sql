@@ -0,0 +1,16 @@
+-- Журнал изменений событий для подписки WatchEvents: одна запись на каждую ревизию
+-- с полным состоянием события после изменения. Пишется в той же транзакции, что и изменение,
+-- под advisory-блокировкой, поэтому порядок seq совпадает с порядком фиксации транзакций
+CREATE TABLE IF NOT EXISTS event_changes (
+    seq BIGSERIAL PRIMARY KEY,
+    -- Без внешнего ключа: запись об удалении переживает очистку корзины
+    event_id INTEGER NOT NULL,
+    change_type VARCHAR(10) NOT NULL,
+    category_id INTEGER NOT NULL,
+    source VARCHAR(255) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT event_changes_type_check CHECK (change_type IN ('created', 'updated', 'deleted'))
+);
+
+CREATE INDEX idx_event_changes_created_at ON event_changes(created_at);
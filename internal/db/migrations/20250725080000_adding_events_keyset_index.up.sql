@@ -0,0 +1,3 @@
+-- Индекс для keyset-пагинации списка событий: ORDER BY created_at DESC, id DESC
+-- и условие (created_at, id) < (...) читаются из индекса без сортировки и OFFSET
+CREATE INDEX idx_events_created_at_id ON events(created_at DESC, id DESC) WHERE deleted_at IS NULL;
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// Повторное изменение того же вхождения заменяет предыдущее
+	upsertOccurrenceOverrideQuery = `INSERT INTO event_occurrence_overrides
+						(event_id, occurrence_start, name, description, starts_at, ends_at, location, price, is_cancelled)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+						ON CONFLICT (event_id, occurrence_start) DO UPDATE
+						SET name = EXCLUDED.name, description = EXCLUDED.description,
+						    starts_at = EXCLUDED.starts_at, ends_at = EXCLUDED.ends_at,
+						    location = EXCLUDED.location, price = EXCLUDED.price,
+						    is_cancelled = EXCLUDED.is_cancelled, updated_at = NOW()
+						RETURNING id, created_at, updated_at`
+
+	getOccurrenceOverridesQuery = `SELECT id, event_id, occurrence_start, name, description, starts_at, ends_at, location, price,
+						is_cancelled, created_at, updated_at
+						FROM event_occurrence_overrides
+						WHERE event_id = ANY($1)
+						ORDER BY event_id, occurrence_start`
+)
+
+// UpsertOccurrenceOverride сохраняет изменение одного вхождения серии.
+func (s *PostgresStore) UpsertOccurrenceOverride(parentCtx context.Context, override *OccurrenceOverride) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	err := s.db.QueryRow(
+		ctx,
+		upsertOccurrenceOverrideQuery,
+		override.EventID,
+		override.OccurrenceStart,
+		override.Name,
+		override.Description,
+		override.StartsAt,
+		override.EndsAt,
+		override.Location,
+		override.Price,
+		override.IsCancelled,
+	).Scan(&override.Id, &override.CreatedAt, &override.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save override for event %d: %w", override.EventID, err)
+	}
+
+	return nil
+}
+
+// GetOccurrenceOverrides возвращает изменения вхождений для набора серий, сгруппированные по ID события.
+func (s *PostgresStore) GetOccurrenceOverrides(parentCtx context.Context, eventIDs []int64) (map[int64][]*OccurrenceOverride, error) {
+	result := make(map[int64][]*OccurrenceOverride)
+	if len(eventIDs) == 0 {
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, getOccurrenceOverridesQuery, eventIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query occurrence overrides: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		override := new(OccurrenceOverride)
+		err := rows.Scan(
+			&override.Id,
+			&override.EventID,
+			&override.OccurrenceStart,
+			&override.Name,
+			&override.Description,
+			&override.StartsAt,
+			&override.EndsAt,
+			&override.Location,
+			&override.Price,
+			&override.IsCancelled,
+			&override.CreatedAt,
+			&override.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan occurrence override: %w", err)
+		}
+		result[override.EventID] = append(result[override.EventID], override)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating occurrence override rows: %w", err)
+	}
+
+	return result, nil
+}
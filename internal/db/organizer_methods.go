@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	organizerColumns = `id, name, email, phone, website, is_verified, created_at, updated_at`
+
+	createOrganizerQuery = `INSERT INTO organizers (name, email, phone, website, is_verified)
+						VALUES ($1, $2, $3, $4, $5)
+						RETURNING id, created_at, updated_at`
+
+	updateOrganizerQuery = `UPDATE organizers
+						SET name = $1, email = $2, phone = $3, website = $4, is_verified = $5, updated_at = NOW()
+						WHERE id = $6
+						RETURNING updated_at`
+
+	getOrganizerByIdQuery       = `SELECT ` + organizerColumns + ` FROM organizers WHERE id = $1`
+	listOrganizersQuery         = `SELECT ` + organizerColumns + ` FROM organizers ORDER BY name, id`
+	listVerifiedOrganizersQuery = `SELECT ` + organizerColumns + ` FROM organizers WHERE is_verified ORDER BY name, id`
+	deleteOrganizerQuery        = `DELETE FROM organizers WHERE id = $1`
+	getEventsByOrganizerQuery   = getEventsQuery + ` AND events.organizer_id = $1`
+)
+
+// CreateOrganizer создает нового организатора.
+func (s *PostgresStore) CreateOrganizer(parentCtx context.Context, organizer *Organizer) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	err := s.db.QueryRow(
+		ctx,
+		createOrganizerQuery,
+		organizer.Name,
+		organizer.Email,
+		organizer.Phone,
+		organizer.Website,
+		organizer.IsVerified,
+	).Scan(&organizer.Id, &organizer.CreatedAt, &organizer.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create organizer: %w", err)
+	}
+
+	return nil
+}
+
+// GetOrganizerByID получает организатора по ID.
+func (s *PostgresStore) GetOrganizerByID(parentCtx context.Context, id int64) (*Organizer, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	organizer, err := scanOrganizer(s.db.QueryRow(ctx, getOrganizerByIdQuery, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("organizer %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to get organizer by id %d: %w", id, err)
+	}
+
+	return organizer, nil
+}
+
+// ListOrganizers возвращает организаторов, отсортированных по имени.
+// verifiedOnly оставляет только подтвержденных организаторов.
+func (s *PostgresStore) ListOrganizers(parentCtx context.Context, verifiedOnly bool) ([]*Organizer, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	query := listOrganizersQuery
+	if verifiedOnly {
+		query = listVerifiedOrganizersQuery
+	}
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query organizers: %w", err)
+	}
+	defer rows.Close()
+
+	organizers := []*Organizer{}
+	for rows.Next() {
+		organizer, err := scanOrganizer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organizer: %w", err)
+		}
+		organizers = append(organizers, organizer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating organizer rows: %w", err)
+	}
+
+	return organizers, nil
+}
+
+// UpdateOrganizer обновляет существующего организатора.
+func (s *PostgresStore) UpdateOrganizer(parentCtx context.Context, organizer *Organizer) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var updatedAt time.Time
+	err := s.db.QueryRow(
+		ctx,
+		updateOrganizerQuery,
+		organizer.Name,
+		organizer.Email,
+		organizer.Phone,
+		organizer.Website,
+		organizer.IsVerified,
+		organizer.Id,
+	).Scan(&updatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("organizer with ID %d not found for update: %w", organizer.Id, err)
+		}
+		return fmt.Errorf("failed to update organizer %d: %w", organizer.Id, err)
+	}
+
+	organizer.UpdatedAt = &updatedAt
+
+	return nil
+}
+
+// DeleteOrganizer удаляет организатора. У его событий organizer_id сбрасывается в NULL.
+func (s *PostgresStore) DeleteOrganizer(parentCtx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	cmdTag, err := s.db.Exec(ctx, deleteOrganizerQuery, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete organizer %d: %w", id, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("organizer with ID %d not found for deletion: %w", id, pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+// GetEventsByOrganizer извлекает события организатора.
+func (s *PostgresStore) GetEventsByOrganizer(parentCtx context.Context, organizerID int64) ([]*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, getEventsByOrganizerQuery, organizerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events by organizer %d: %w", organizerID, err)
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event row for organizer %d: %w", organizerID, err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event rows for organizer %d: %w", organizerID, err)
+	}
+
+	return events, nil
+}
+
+// scanOrganizer сканирует одну строку organizers в структуру Organizer.
+func scanOrganizer(scanner pgxScanner) (*Organizer, error) {
+	organizer := new(Organizer)
+
+	err := scanner.Scan(
+		&organizer.Id,
+		&organizer.Name,
+		&organizer.Email,
+		&organizer.Phone,
+		&organizer.Website,
+		&organizer.IsVerified,
+		&organizer.CreatedAt,
+		&organizer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return organizer, nil
+}
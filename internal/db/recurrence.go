@@ -0,0 +1,200 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rx3lixir/event-service/internal/recurrence"
+)
+
+// MaxExpandedOccurrences ограничивает количество вхождений одной серии в ответе,
+// когда верхняя граница диапазона не задана.
+const MaxExpandedOccurrences = 500
+
+// IsRecurring сообщает, является ли событие серией повторений.
+func (e *Event) IsRecurring() bool {
+	return e.RecurrenceRule != ""
+}
+
+// RecurrenceSet разбирает правило повторения события.
+// Для разового события возвращает nil.
+func (e *Event) RecurrenceSet() (*recurrence.Set, error) {
+	if !e.IsRecurring() {
+		return nil, nil
+	}
+
+	if e.StartsAt == nil {
+		return nil, fmt.Errorf("recurring event requires starts_at")
+	}
+
+	loc := e.TimeLocation()
+	rule, err := recurrence.Parse(e.RecurrenceRule, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+
+	return recurrence.NewSet(rule, *e.StartsAt, loc, e.RecurrenceExDates), nil
+}
+
+// prepareRecurrence проверяет правило и вычисляет RecurrenceUntil перед сохранением.
+func (e *Event) prepareRecurrence() error {
+	if e.RecurrenceExDates == nil {
+		e.RecurrenceExDates = []time.Time{}
+	}
+
+	if !e.IsRecurring() {
+		e.RecurrenceUntil = nil
+		return nil
+	}
+
+	set, err := e.RecurrenceSet()
+	if err != nil {
+		return err
+	}
+
+	e.RecurrenceUntil = nil
+	if last, ok := set.Last(); ok {
+		until := last.Add(e.duration())
+		e.RecurrenceUntil = &until
+	}
+
+	return nil
+}
+
+// duration возвращает длительность одного вхождения (0, если ends_at не задан).
+func (e *Event) duration() time.Duration {
+	if e.StartsAt == nil || e.EndsAt == nil {
+		return 0
+	}
+	return e.EndsAt.Sub(*e.StartsAt)
+}
+
+// Occurrence строит вхождение серии, начинающееся в start, с учетом изменения override.
+func (e *Event) Occurrence(start time.Time, override *OccurrenceOverride) *Event {
+	occurrence := *e
+	occurrenceStart := start
+	occurrence.OccurrenceStart = &occurrenceStart
+
+	startsAt := start
+	occurrence.StartsAt = &startsAt
+	if e.EndsAt != nil {
+		endsAt := start.Add(e.duration())
+		occurrence.EndsAt = &endsAt
+	}
+
+	if override != nil {
+		occurrence.IsOverride = true
+		if override.Name != nil {
+			occurrence.Name = *override.Name
+		}
+		if override.Description != nil {
+			occurrence.Description = *override.Description
+		}
+		if override.StartsAt != nil {
+			occurrence.StartsAt = override.StartsAt
+		}
+		if override.EndsAt != nil {
+			occurrence.EndsAt = override.EndsAt
+		}
+		if override.Location != nil {
+			occurrence.Location = *override.Location
+		}
+		if override.Price != nil {
+			// Единая цена вхождения заменяет диапазон цен серии
+			price := *override.Price
+			occurrence.Price = price
+			occurrence.PriceFrom = &price
+			occurrence.PriceTo = &price
+		}
+	}
+
+	occurrence.normalizeSchedule()
+
+	return &occurrence
+}
+
+// ExpandOccurrences разворачивает серию в вхождения, пересекающиеся с [from, to).
+// Нулевой from означает начало серии, нулевой to - не более MaxExpandedOccurrences вхождений.
+// Отмененные вхождения пропускаются, перенесенные в диапазон из-за его пределов - добавляются.
+// Разовое событие возвращается как есть.
+func ExpandOccurrences(event *Event, overrides []*OccurrenceOverride, from, to time.Time) ([]*Event, error) {
+	set, err := event.RecurrenceSet()
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		return []*Event{event}, nil
+	}
+
+	byStart := make(map[int64]*OccurrenceOverride, len(overrides))
+	for _, o := range overrides {
+		byStart[o.OccurrenceStart.Unix()] = o
+	}
+
+	// Вхождение могло начаться до from, но еще идти
+	searchFrom := from
+	if !from.IsZero() {
+		searchFrom = from.Add(-event.duration())
+	}
+
+	limit := 0
+	if to.IsZero() {
+		limit = MaxExpandedOccurrences
+	}
+
+	var occurrences []*Event
+	seen := make(map[int64]bool)
+
+	for _, start := range set.Between(searchFrom, to, limit) {
+		override := byStart[start.Unix()]
+		seen[start.Unix()] = true
+
+		if override != nil && override.IsCancelled {
+			continue
+		}
+
+		occurrence := event.Occurrence(start, override)
+		if occurrenceOverlaps(occurrence, from, to) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	// Вхождения, перенесенные в диапазон из-за его пределов
+	for _, o := range overrides {
+		if seen[o.OccurrenceStart.Unix()] || o.IsCancelled || o.StartsAt == nil {
+			continue
+		}
+		if !set.Includes(o.OccurrenceStart.In(event.TimeLocation())) {
+			continue
+		}
+
+		occurrence := event.Occurrence(o.OccurrenceStart, o)
+		if occurrenceOverlaps(occurrence, from, to) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartsAt.Before(*occurrences[j].StartsAt)
+	})
+
+	return occurrences, nil
+}
+
+// occurrenceOverlaps проверяет пересечение вхождения с [from, to)
+func occurrenceOverlaps(e *Event, from, to time.Time) bool {
+	start := *e.StartsAt
+	end := start
+	if e.EndsAt != nil {
+		end = *e.EndsAt
+	}
+
+	if !from.IsZero() && end.Before(from) {
+		return false
+	}
+	if !to.IsZero() && !start.Before(to) {
+		return false
+	}
+	return true
+}
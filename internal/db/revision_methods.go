@@ -0,0 +1,405 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Действия, после которых записывается ревизия события
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+	RevisionStatus  = "status"
+	RevisionRevert  = "revert"
+	RevisionMerge   = "merge"
+)
+
+// SystemActor автор изменений, сделанных фоновыми задачами
+const SystemActor = "system"
+
+const (
+	insertEventRevisionQuery = `INSERT INTO event_revisions (event_id, revision, action, actor, changed_fields, snapshot)
+						SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5
+						FROM event_revisions WHERE event_id = $1`
+
+	eventRevisionColumns = `id, event_id, revision, action, actor, changed_fields, snapshot, created_at`
+
+	listEventRevisionsQuery = `SELECT ` + eventRevisionColumns + ` FROM event_revisions
+						WHERE event_id = $1 ORDER BY revision DESC LIMIT $2 OFFSET $3`
+	countEventRevisionsQuery = `SELECT COUNT(*) FROM event_revisions WHERE event_id = $1`
+	getEventRevisionQuery    = `SELECT ` + eventRevisionColumns + ` FROM event_revisions WHERE event_id = $1 AND revision = $2`
+
+	// getAnyEventByIdQuery читает событие независимо от мягкого удаления и блокирует строку до конца транзакции
+	getAnyEventByIdQuery = getEventsQueryBaseFields + ` WHERE events.id = $1 FOR UPDATE OF events`
+	// getEventForUpdateQuery читает неудаленное событие и блокирует строку до конца транзакции
+	getEventForUpdateQuery = getEventByIdQuery + ` FOR UPDATE OF events`
+)
+
+// EventSnapshot сохраняемое состояние события в ревизии.
+// Производные поля (площадка, расстояние, таймстемпы) не входят в снимок.
+type EventSnapshot struct {
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	CategoryID        int64              `json:"category_id"`
+	Date              string             `json:"date"`
+	Time              string             `json:"time"`
+	Location          string             `json:"location"`
+	Price             float32            `json:"price"`
+	Image             string             `json:"image"`
+	Source            string             `json:"source"`
+	StartsAt          *time.Time         `json:"starts_at"`
+	EndsAt            *time.Time         `json:"ends_at"`
+	Timezone          string             `json:"timezone"`
+	RecurrenceRule    string             `json:"recurrence_rule"`
+	RecurrenceExDates []time.Time        `json:"recurrence_exdates"`
+	RecurrenceUntil   *time.Time         `json:"recurrence_until"`
+	Tags              []string           `json:"tags"`
+	VenueID           *int64             `json:"venue_id"`
+	OrganizerID       *int64             `json:"organizer_id"`
+	TicketTiers       []TicketTier       `json:"ticket_tiers"`
+	Translations      []EventTranslation `json:"translations"`
+	PriceFrom         *float32           `json:"price_from"`
+	PriceTo           *float32           `json:"price_to"`
+	Currency          string             `json:"currency"`
+	Status            string             `json:"status"`
+	PublishAt         *time.Time         `json:"publish_at"`
+	StatusReason      string             `json:"status_reason"`
+	DeletedAt         *time.Time         `json:"deleted_at"`
+}
+
+// EventRevision ревизия события: снимок после изменения, измененные поля и автор
+type EventRevision struct {
+	Id            int64
+	EventID       int64
+	Revision      int
+	Action        string // Одно из Revision*
+	Actor         string // Пусто, если автор неизвестен
+	ChangedFields []string
+	Snapshot      EventSnapshot
+	CreatedAt     time.Time
+}
+
+// FieldChange изменение одного поля между двумя снимками. Значения в JSON
+type FieldChange struct {
+	Field    string
+	OldValue json.RawMessage
+	NewValue json.RawMessage
+}
+
+type actorContextKey struct{}
+
+// WithActor сохраняет в контексте автора изменений для ревизий.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext возвращает автора изменений из контекста или пустую строку.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorContextKey{}).(string)
+	return actor
+}
+
+// NewEventSnapshot создает снимок события. Идентификаторы билетных категорий
+// не сохраняются: они меняются при каждой перезаписи категорий.
+func NewEventSnapshot(e *Event) EventSnapshot {
+	tiers := make([]TicketTier, len(e.TicketTiers))
+	for i, tier := range e.TicketTiers {
+		tier.Id = 0
+		tiers[i] = tier
+	}
+
+	return EventSnapshot{
+		Name:              e.Name,
+		Description:       e.Description,
+		CategoryID:        e.CategoryID,
+		Date:              e.Date,
+		Time:              e.Time,
+		Location:          e.Location,
+		Price:             e.Price,
+		Image:             e.Image,
+		Source:            e.Source,
+		StartsAt:          e.StartsAt,
+		EndsAt:            e.EndsAt,
+		Timezone:          e.Timezone,
+		RecurrenceRule:    e.RecurrenceRule,
+		RecurrenceExDates: e.RecurrenceExDates,
+		RecurrenceUntil:   e.RecurrenceUntil,
+		Tags:              e.Tags,
+		VenueID:           e.VenueID,
+		OrganizerID:       e.OrganizerID,
+		TicketTiers:       tiers,
+		Translations:      e.Translations,
+		PriceFrom:         e.PriceFrom,
+		PriceTo:           e.PriceTo,
+		Currency:          e.Currency,
+		Status:            e.Status,
+		PublishAt:         e.PublishAt,
+		StatusReason:      e.StatusReason,
+		DeletedAt:         e.DeletedAt,
+	}
+}
+
+// Event восстанавливает событие из снимка.
+func (s EventSnapshot) Event(id int64) *Event {
+	event := &Event{Id: id}
+	s.ApplyContent(event)
+	event.Status = s.Status
+	event.PublishAt = s.PublishAt
+	event.StatusReason = s.StatusReason
+	event.DeletedAt = s.DeletedAt
+	return event
+}
+
+// ApplyContent переносит в событие содержимое снимка. Статус и удаление
+// не переносятся: они меняются только через жизненный цикл и корзину.
+func (s EventSnapshot) ApplyContent(e *Event) {
+	e.Name = s.Name
+	e.Description = s.Description
+	e.CategoryID = s.CategoryID
+	e.Date = s.Date
+	e.Time = s.Time
+	e.Location = s.Location
+	e.Price = s.Price
+	e.Image = s.Image
+	e.Source = s.Source
+	e.StartsAt = s.StartsAt
+	e.EndsAt = s.EndsAt
+	e.Timezone = s.Timezone
+	e.RecurrenceRule = s.RecurrenceRule
+	e.RecurrenceExDates = s.RecurrenceExDates
+	e.RecurrenceUntil = s.RecurrenceUntil
+	e.Tags = s.Tags
+	e.VenueID = s.VenueID
+	e.OrganizerID = s.OrganizerID
+	e.TicketTiers = s.TicketTiers
+	e.Translations = s.Translations
+	e.PriceFrom = s.PriceFrom
+	e.PriceTo = s.PriceTo
+	e.Currency = s.Currency
+}
+
+// DiffEventSnapshots возвращает изменения полей между снимками в порядке имени поля.
+// Нулевой from означает сравнение с пустым событием.
+func DiffEventSnapshots(from, to *EventSnapshot) ([]FieldChange, error) {
+	oldFields, err := snapshotFields(from)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := snapshotFields(to)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(newFields))
+	for name := range newFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changes := []FieldChange{}
+	for _, name := range names {
+		oldValue, newValue := oldFields[name], newFields[name]
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, OldValue: oldValue, NewValue: newValue})
+	}
+
+	return changes, nil
+}
+
+// snapshotFields раскладывает снимок на JSON значения полей.
+// Для nil возвращаются значения пустого события, пустые списки приравниваются к null.
+func snapshotFields(snapshot *EventSnapshot) (map[string]json.RawMessage, error) {
+	if snapshot == nil {
+		snapshot = &EventSnapshot{}
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event snapshot: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode event snapshot: %w", err)
+	}
+
+	for name, value := range fields {
+		if string(value) == "[]" {
+			fields[name] = json.RawMessage("null")
+		}
+	}
+
+	return fields, nil
+}
+
+// changedFieldNames возвращает имена полей, различающихся в снимках событий.
+func changedFieldNames(previous, current *Event) ([]string, error) {
+	if previous == nil {
+		return []string{}, nil
+	}
+
+	from, to := NewEventSnapshot(previous), NewEventSnapshot(current)
+	changes, err := DiffEventSnapshots(&from, &to)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(changes))
+	for i, change := range changes {
+		names[i] = change.Field
+	}
+	return names, nil
+}
+
+// recordEventRevision читает текущее состояние события и записывает его ревизию
+// и запись журнала изменений. previous - состояние до изменения, nil для созданного события.
+// Должен вызываться внутри транзакции изменения. Возвращает текущее состояние.
+func (s *PostgresStore) recordEventRevision(ctx context.Context, eventID int64, action string, previous *Event) (*Event, error) {
+	current, err := scanEvent(s.db.QueryRow(ctx, getAnyEventByIdQuery, eventID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event %d for revision: %w", eventID, err)
+	}
+
+	changedFields, err := changedFieldNames(previous, current)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.Exec(
+		ctx,
+		insertEventRevisionQuery,
+		eventID,
+		action,
+		ActorFromContext(ctx),
+		changedFields,
+		NewEventSnapshot(current),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record revision of event %d: %w", eventID, err)
+	}
+
+	if err := s.recordEventChange(ctx, action, current); err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// getEventForUpdate читает неудаленное событие с блокировкой строки. Вызывается внутри транзакции.
+func (s *PostgresStore) getEventForUpdate(ctx context.Context, id int64) (*Event, error) {
+	event, err := scanEvent(s.db.QueryRow(ctx, getEventForUpdateQuery, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("event with ID %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to lock event %d: %w", id, err)
+	}
+	return event, nil
+}
+
+// ListEventRevisions возвращает страницу ревизий события, начиная с последней, и их общее количество.
+func (s *PostgresStore) ListEventRevisions(parentCtx context.Context, eventID int64, limit, offset int) ([]*EventRevision, int64, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, listEventRevisionsQuery, eventID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query revisions of event %d: %w", eventID, err)
+	}
+	defer rows.Close()
+
+	revisions := []*EventRevision{}
+	for rows.Next() {
+		revision, err := scanEventRevision(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan event revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating event revision rows: %w", err)
+	}
+
+	var total int64
+	if err := s.db.QueryRow(ctx, countEventRevisionsQuery, eventID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count revisions of event %d: %w", eventID, err)
+	}
+
+	return revisions, total, nil
+}
+
+// GetEventRevision извлекает ревизию события по номеру.
+func (s *PostgresStore) GetEventRevision(parentCtx context.Context, eventID int64, revision int) (*EventRevision, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	result, err := scanEventRevision(s.db.QueryRow(ctx, getEventRevisionQuery, eventID, revision))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("revision %d of event %d not found: %w", revision, eventID, err)
+		}
+		return nil, fmt.Errorf("failed to get revision %d of event %d: %w", revision, eventID, err)
+	}
+
+	return result, nil
+}
+
+// RevertEventToRevision возвращает содержимое события к состоянию ревизии
+// и записывает это как новую ревизию. Статус и удаление не откатываются.
+func (s *PostgresStore) RevertEventToRevision(parentCtx context.Context, eventID int64, revision int) (*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var reverted *Event
+	err := s.inTx(ctx, func(tx *PostgresStore) error {
+		target, err := tx.GetEventRevision(ctx, eventID, revision)
+		if err != nil {
+			return err
+		}
+
+		event, err := tx.getEventForUpdate(ctx, eventID)
+		if err != nil {
+			return err
+		}
+
+		target.Snapshot.ApplyContent(event)
+
+		reverted, err = tx.updateEventWithRevision(ctx, event, RevisionRevert, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reverted, nil
+}
+
+// scanEventRevision сканирует строку event_revisions (eventRevisionColumns).
+func scanEventRevision(scanner pgxScanner) (*EventRevision, error) {
+	revision := new(EventRevision)
+	err := scanner.Scan(
+		&revision.Id,
+		&revision.EventID,
+		&revision.Revision,
+		&revision.Action,
+		&revision.Actor,
+		&revision.ChangedFields,
+		&revision.Snapshot,
+		&revision.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
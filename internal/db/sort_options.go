@@ -0,0 +1,207 @@
+package db
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Поля сортировки списка событий
+const (
+	SortFieldStartsAt  = "starts_at"  // Начало события
+	SortFieldPrice     = "price"      // Минимальная цена (price_from)
+	SortFieldName      = "name"       // Название
+	SortFieldCreatedAt = "created_at" // Дата создания
+	SortFieldRelevance = "relevance"  // Релевантность поиску, только OpenSearch
+	SortFieldDistance  = "distance"   // Расстояние от точки поиска, только OpenSearch
+)
+
+// EventSort ключ сортировки списка событий. Порядок ключей задает приоритет,
+// при равенстве всех ключей события упорядочиваются по id.
+type EventSort struct {
+	Field      string
+	Descending bool
+}
+
+// sortDefaultDescending направление полей по умолчанию: новые, релевантные - сверху,
+// остальное по возрастанию
+var sortDefaultDescending = map[string]bool{
+	SortFieldStartsAt:  false,
+	SortFieldPrice:     false,
+	SortFieldName:      false,
+	SortFieldCreatedAt: true,
+	SortFieldRelevance: true,
+	SortFieldDistance:  false,
+}
+
+// sortColumn колонка сортировки PostgreSQL. Пустые значения nullable колонок
+// всегда идут в конце, независимо от направления.
+type sortColumn struct {
+	expr     string
+	nullable bool
+}
+
+// eventSortColumns поля сортировки, доступные в PostgreSQL
+var eventSortColumns = map[string]sortColumn{
+	SortFieldStartsAt:  {expr: "events.starts_at", nullable: true},
+	SortFieldPrice:     {expr: "events.price_from", nullable: true},
+	SortFieldName:      {expr: "events.name"},
+	SortFieldCreatedAt: {expr: "events.created_at"},
+}
+
+// DefaultEventSort порядок списка без явной сортировки: новые сверху.
+func DefaultEventSort() []EventSort {
+	return []EventSort{{Field: SortFieldCreatedAt, Descending: true}}
+}
+
+// ParseEventSort разбирает поле и направление ("asc", "desc" или пусто - направление поля
+// по умолчанию).
+func ParseEventSort(field, direction string) (EventSort, error) {
+	field = strings.ToLower(strings.TrimSpace(field))
+	descending, known := sortDefaultDescending[field]
+	if !known {
+		return EventSort{}, fmt.Errorf("unknown sort field %q", field)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(direction)) {
+	case "":
+	case "asc":
+		descending = false
+	case "desc":
+		descending = true
+	default:
+		return EventSort{}, fmt.Errorf("invalid sort direction %q for %s, expected asc or desc", direction, field)
+	}
+
+	return EventSort{Field: field, Descending: descending}, nil
+}
+
+// SortSignature описывает сортировку строкой вида "price:asc,name:desc".
+// Курсор страницы действует только с той сортировкой, с которой выдан.
+func SortSignature(sorts []EventSort) string {
+	parts := make([]string, len(sorts))
+	for i, sort := range sorts {
+		direction := "asc"
+		if sort.Descending {
+			direction = "desc"
+		}
+		parts[i] = sort.Field + ":" + direction
+	}
+	return strings.Join(parts, ",")
+}
+
+// sortValue значение ключа сортировки события для курсора; nil для пустого значения.
+func sortValue(event *Event, field string) any {
+	switch field {
+	case SortFieldStartsAt:
+		if event.StartsAt == nil {
+			return nil
+		}
+		return *event.StartsAt
+	case SortFieldPrice:
+		if event.PriceFrom == nil {
+			return nil
+		}
+		return *event.PriceFrom
+	case SortFieldName:
+		return event.Name
+	default:
+		return event.CreatedAt
+	}
+}
+
+// CompareEvents сравнивает события в порядке сортировки sorts, как ORDER BY списка:
+// пустые значения в конце, при равенстве всех ключей - по id в направлении последнего ключа.
+// Поддерживаются поля, доступные в PostgreSQL.
+func CompareEvents(a, b *Event, sorts []EventSort) int {
+	descending := false
+	for _, sort := range sorts {
+		descending = sort.Descending
+
+		left, right := sortValue(a, sort.Field), sortValue(b, sort.Field)
+		if left == nil || right == nil {
+			switch {
+			case left == nil && right == nil:
+				continue
+			case left == nil:
+				return 1
+			default:
+				return -1
+			}
+		}
+
+		var result int
+		switch value := left.(type) {
+		case time.Time:
+			result = value.Compare(right.(time.Time))
+		case float32:
+			result = cmp.Compare(value, right.(float32))
+		case string:
+			result = strings.Compare(value, right.(string))
+		}
+		if sort.Descending {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+
+	result := cmp.Compare(a.Id, b.Id)
+	if descending {
+		result = -result
+	}
+	return result
+}
+
+// NewEventCursor собирает курсор из значений ключей сортировки, прочитанных из токена
+// (после JSON время - строка RFC 3339, цена - float64), и проверяет их типы.
+func NewEventCursor(sorts []EventSort, values []any, id int64) (EventCursor, error) {
+	if id <= 0 || len(values) != len(sorts) {
+		return EventCursor{}, fmt.Errorf("cursor does not match sort")
+	}
+
+	parsed := make([]any, len(values))
+	for i, sort := range sorts {
+		column, ok := eventSortColumns[sort.Field]
+		if !ok {
+			return EventCursor{}, fmt.Errorf("sort field %q is not supported", sort.Field)
+		}
+
+		value := values[i]
+		if value == nil {
+			if !column.nullable {
+				return EventCursor{}, fmt.Errorf("cursor value for %s cannot be empty", sort.Field)
+			}
+			continue
+		}
+
+		switch sort.Field {
+		case SortFieldStartsAt, SortFieldCreatedAt:
+			raw, ok := value.(string)
+			if !ok {
+				return EventCursor{}, fmt.Errorf("invalid cursor value for %s", sort.Field)
+			}
+			t, err := time.Parse(time.RFC3339Nano, raw)
+			if err != nil {
+				return EventCursor{}, fmt.Errorf("invalid cursor value for %s: %w", sort.Field, err)
+			}
+			parsed[i] = t
+		case SortFieldPrice:
+			price, ok := value.(float64)
+			if !ok {
+				return EventCursor{}, fmt.Errorf("invalid cursor value for %s", sort.Field)
+			}
+			parsed[i] = float32(price)
+		case SortFieldName:
+			name, ok := value.(string)
+			if !ok {
+				return EventCursor{}, fmt.Errorf("invalid cursor value for %s", sort.Field)
+			}
+			parsed[i] = name
+		}
+	}
+
+	return EventCursor{Values: parsed, ID: id}, nil
+}
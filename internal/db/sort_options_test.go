@@ -0,0 +1,155 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEventSort(t *testing.T) {
+	tests := []struct {
+		field, direction string
+		want             EventSort
+		wantErr          bool
+	}{
+		{field: "price", want: EventSort{Field: SortFieldPrice}},
+		{field: "created_at", want: EventSort{Field: SortFieldCreatedAt, Descending: true}},
+		{field: " Name ", direction: "DESC", want: EventSort{Field: SortFieldName, Descending: true}},
+		{field: "created_at", direction: "asc", want: EventSort{Field: SortFieldCreatedAt}},
+		{field: "popularity", wantErr: true},
+		{field: "price", direction: "up", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEventSort(tt.field, tt.direction)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseEventSort(%q, %q) error = %v, wantErr %v", tt.field, tt.direction, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseEventSort(%q, %q) = %+v, want %+v", tt.field, tt.direction, got, tt.want)
+		}
+	}
+}
+
+func TestSortSignature(t *testing.T) {
+	priceAsc := EventSort{Field: SortFieldPrice}
+	priceDesc := EventSort{Field: SortFieldPrice, Descending: true}
+	nameAsc := EventSort{Field: SortFieldName}
+
+	if got, want := SortSignature([]EventSort{priceAsc, nameAsc}), "price:asc,name:asc"; got != want {
+		t.Errorf("SortSignature() = %q, want %q", got, want)
+	}
+
+	// Курсор одной сортировки не должен приниматься для другой
+	tests := []struct {
+		name string
+		a, b []EventSort
+	}{
+		{"direction", []EventSort{priceAsc}, []EventSort{priceDesc}},
+		{"key order", []EventSort{priceAsc, nameAsc}, []EventSort{nameAsc, priceAsc}},
+		{"extra key", []EventSort{priceAsc}, []EventSort{priceAsc, nameAsc}},
+	}
+	for _, tt := range tests {
+		if SortSignature(tt.a) == SortSignature(tt.b) {
+			t.Errorf("%s: signatures of %+v and %+v match", tt.name, tt.a, tt.b)
+		}
+	}
+}
+
+func TestBuildRowCursorCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		sorts    []EventSort
+		cursor   EventCursor
+		argIndex int
+		want     string
+		wantArgs []any
+		wantOK   bool
+	}{
+		{
+			name:     "default order reads the composite index",
+			sorts:    []EventSort{{Field: SortFieldCreatedAt, Descending: true}},
+			cursor:   EventCursor{Values: []any{"t"}, ID: 9},
+			argIndex: 3,
+			want:     "(events.created_at, events.id) < ($3, $4)",
+			wantArgs: []any{"t", int64(9)},
+			wantOK:   true,
+		},
+		{
+			name:     "several keys in one direction",
+			sorts:    []EventSort{{Field: SortFieldName}, {Field: SortFieldCreatedAt}},
+			cursor:   EventCursor{Values: []any{"a", "t"}, ID: 5},
+			argIndex: 1,
+			want:     "(events.name, events.created_at, events.id) > ($1, $2, $3)",
+			wantArgs: []any{"a", "t", int64(5)},
+			wantOK:   true,
+		},
+		{
+			name:   "mixed directions",
+			sorts:  []EventSort{{Field: SortFieldName}, {Field: SortFieldCreatedAt, Descending: true}},
+			cursor: EventCursor{Values: []any{"a", "t"}, ID: 5},
+		},
+		{
+			name:   "nullable key",
+			sorts:  []EventSort{{Field: SortFieldStartsAt}},
+			cursor: EventCursor{Values: []any{"t"}, ID: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, ok := buildRowCursorCondition(tt.sorts, &tt.cursor, tt.argIndex)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("condition = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBuildCursorConditionFallsBackToBranches(t *testing.T) {
+	sorts := []EventSort{{Field: SortFieldStartsAt}, {Field: SortFieldName, Descending: true}}
+
+	tests := []struct {
+		name     string
+		cursor   EventCursor
+		want     string
+		wantArgs []any
+	}{
+		{
+			name:   "non-empty nullable key",
+			cursor: EventCursor{Values: []any{"t", "a"}, ID: 7},
+			want: "((events.starts_at > $1 OR events.starts_at IS NULL)" +
+				" OR (events.starts_at = $1 AND events.name < $2)" +
+				" OR (events.starts_at = $1 AND events.name = $2 AND events.id < $3))",
+			wantArgs: []any{"t", "a", int64(7)},
+		},
+		{
+			name:   "empty nullable key is followed only by empty ones",
+			cursor: EventCursor{Values: []any{nil, "a"}, ID: 7},
+			want: "((events.starts_at IS NULL AND events.name < $1)" +
+				" OR (events.starts_at IS NULL AND events.name = $1 AND events.id < $2))",
+			wantArgs: []any{"a", int64(7)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := buildCursorCondition(sorts, &tt.cursor, 1)
+			if got != tt.want {
+				t.Errorf("condition:\n got %s\nwant %s", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Результаты UpsertEvent
+const (
+	UpsertCreated   = "created"   // Событие создано
+	UpsertUpdated   = "updated"   // Существующее событие изменено
+	UpsertUnchanged = "unchanged" // Данные источника совпали с сохраненными, запись не менялась
+	UpsertMerged    = "merged"    // Событие объединено с другим, данные источника не применяются
+)
+
+// DefaultSourceTrustLevel уровень доверия нового источника, если он не указан
+const DefaultSourceTrustLevel = 50
+
+var (
+	// ErrExternalEventDeleted возвращается при импорте события, которое было удалено.
+	// Повторный импорт не восстанавливает удаленные события
+	ErrExternalEventDeleted = errors.New("external event was deleted")
+
+	// errUpsertUnchanged откатывает транзакцию UpsertEvent, если событие не изменилось
+	errUpsertUnchanged = errors.New("upserted event is unchanged")
+)
+
+const (
+	sourceColumns = `id, slug, display_name, trust_level, is_active, created_at, updated_at`
+
+	createSourceQuery = `INSERT INTO sources (slug, display_name, trust_level, is_active)
+						VALUES ($1, $2, $3, $4)
+						RETURNING id, created_at, updated_at`
+
+	updateSourceQuery = `UPDATE sources
+						SET display_name = $1, trust_level = $2, is_active = $3, updated_at = NOW()
+						WHERE id = $4
+						RETURNING updated_at`
+
+	getSourceByIdQuery     = `SELECT ` + sourceColumns + ` FROM sources WHERE id = $1`
+	getSourceBySlugQuery   = `SELECT ` + sourceColumns + ` FROM sources WHERE slug = $1`
+	listSourcesQuery       = `SELECT ` + sourceColumns + ` FROM sources ORDER BY slug`
+	listActiveSourcesQuery = `SELECT ` + sourceColumns + ` FROM sources WHERE is_active ORDER BY slug`
+
+	// Конкурентная вставка того же external_id ждет первую транзакцию и ничего не вставляет
+	insertExternalEventQuery = insertEventQuery + ` ON CONFLICT ON CONSTRAINT events_source_external_id_key DO NOTHING
+						RETURNING id, created_at, updated_at`
+
+	// Читает импортированное событие независимо от мягкого удаления и блокирует строку до конца транзакции
+	getExternalEventForUpdateQuery = getEventsQueryBaseFields + ` WHERE events.source_id = $1 AND events.external_id = $2 FOR UPDATE OF events`
+)
+
+// CreateSource создает новый источник. Slug должен быть уникальным.
+func (s *PostgresStore) CreateSource(parentCtx context.Context, source *Source) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	err := s.db.QueryRow(
+		ctx,
+		createSourceQuery,
+		source.Slug,
+		source.DisplayName,
+		source.TrustLevel,
+		source.IsActive,
+	).Scan(&source.Id, &source.CreatedAt, &source.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create source: %w", err)
+	}
+
+	return nil
+}
+
+// GetSourceByID получает источник по ID.
+func (s *PostgresStore) GetSourceByID(parentCtx context.Context, id int64) (*Source, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	source, err := scanSource(s.db.QueryRow(ctx, getSourceByIdQuery, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("source %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to get source by id %d: %w", id, err)
+	}
+
+	return source, nil
+}
+
+// GetSourceBySlug получает источник по slug.
+func (s *PostgresStore) GetSourceBySlug(parentCtx context.Context, slug string) (*Source, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	source, err := scanSource(s.db.QueryRow(ctx, getSourceBySlugQuery, NormalizeSourceSlug(slug)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("source %q not found: %w", slug, err)
+		}
+		return nil, fmt.Errorf("failed to get source by slug %q: %w", slug, err)
+	}
+
+	return source, nil
+}
+
+// ListSources возвращает источники, отсортированные по slug.
+// activeOnly исключает отключенные источники.
+func (s *PostgresStore) ListSources(parentCtx context.Context, activeOnly bool) ([]*Source, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	query := listSourcesQuery
+	if activeOnly {
+		query = listActiveSourcesQuery
+	}
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sources: %w", err)
+	}
+	defer rows.Close()
+
+	sources := []*Source{}
+	for rows.Next() {
+		source, err := scanSource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan source: %w", err)
+		}
+		sources = append(sources, source)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating source rows: %w", err)
+	}
+
+	return sources, nil
+}
+
+// UpdateSource обновляет имя, уровень доверия и активность источника. Slug не меняется.
+func (s *PostgresStore) UpdateSource(parentCtx context.Context, source *Source) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var updatedAt time.Time
+	err := s.db.QueryRow(
+		ctx,
+		updateSourceQuery,
+		source.DisplayName,
+		source.TrustLevel,
+		source.IsActive,
+		source.Id,
+	).Scan(&updatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("source with ID %d not found for update: %w", source.Id, err)
+		}
+		return fmt.Errorf("failed to update source %d: %w", source.Id, err)
+	}
+
+	source.UpdatedAt = &updatedAt
+
+	return nil
+}
+
+// UpsertEvent создает событие источника event.SourceID с идентификатором event.ExternalID
+// или обновляет уже импортированное. Возвращает сохраненное состояние и одно из Upsert*.
+// При обновлении статус и публикация сохраняются, версия не сверяется: источник - последний автор.
+// Для события, объединенного с другим (MergeEvents), возвращается каноническое событие и UpsertMerged.
+// Если после записи событие не отличается от сохраненного, транзакция откатывается
+// и возвращается UpsertUnchanged без новой версии и ревизии.
+func (s *PostgresStore) UpsertEvent(parentCtx context.Context, event *Event) (*Event, string, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	if event.SourceID == nil || event.ExternalID == "" {
+		return nil, "", errors.New("failed to upsert event: source and external id are required")
+	}
+
+	if err := event.prepareRecurrence(); err != nil {
+		return nil, "", fmt.Errorf("failed to upsert event: %w", err)
+	}
+
+	var result *Event
+	var outcome string
+	err := s.inTx(ctx, func(tx *PostgresStore) error {
+		err := tx.insertEvent(ctx, insertExternalEventQuery, event)
+		if err == nil {
+			outcome = UpsertCreated
+			result, err = tx.createEventDetails(ctx, event)
+			return err
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to insert external event %q: %w", event.ExternalID, err)
+		}
+
+		// Событие уже импортировано: обновляем его
+		previous, err := scanEvent(tx.db.QueryRow(ctx, getExternalEventForUpdateQuery, *event.SourceID, event.ExternalID))
+		if err != nil {
+			return fmt.Errorf("failed to lock external event %q: %w", event.ExternalID, err)
+		}
+		if previous.DeletedAt != nil {
+			// Объединенный дубликат: возвращаем каноническое событие без изменений
+			canonicalID, err := tx.ResolveEventRedirect(ctx, previous.Id)
+			if err == nil {
+				result, err = tx.GetEventByID(ctx, canonicalID)
+				outcome = UpsertMerged
+				return err
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+			return fmt.Errorf("event %d (external id %q): %w", previous.Id, event.ExternalID, ErrExternalEventDeleted)
+		}
+
+		event.Id = previous.Id
+		event.Version = previous.Version
+		event.Status = previous.Status
+		event.PublishAt = previous.PublishAt
+		event.StatusReason = previous.StatusReason
+
+		current, err := tx.updateEventWithRevision(ctx, event, RevisionUpdate, nil)
+		if err != nil {
+			return err
+		}
+
+		changed, err := changedFieldNames(previous, current)
+		if err != nil {
+			return err
+		}
+		if len(changed) == 0 {
+			result, outcome = previous, UpsertUnchanged
+			return errUpsertUnchanged
+		}
+
+		result, outcome = current, UpsertUpdated
+		return nil
+	})
+	if err != nil && !errors.Is(err, errUpsertUnchanged) {
+		return nil, "", err
+	}
+
+	return result, outcome, nil
+}
+
+// scanSource сканирует одну строку sources (sourceColumns) в структуру Source.
+func scanSource(scanner pgxScanner) (*Source, error) {
+	source := new(Source)
+
+	err := scanner.Scan(
+		&source.Id,
+		&source.Slug,
+		&source.DisplayName,
+		&source.TrustLevel,
+		&source.IsActive,
+		&source.CreatedAt,
+		&source.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
@@ -0,0 +1,229 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Статусы жизненного цикла события
+const (
+	StatusDraft     = "draft"     // Черновик, виден только администраторам
+	StatusScheduled = "scheduled" // Будет опубликовано в момент PublishAt
+	StatusPublished = "published" // Опубликовано
+	StatusCancelled = "cancelled" // Отменено, конечный статус
+	StatusPostponed = "postponed" // Перенесено, новая дата может быть еще неизвестна
+	StatusSoldOut   = "sold_out"  // Опубликовано, но билеты во всех категориях распроданы
+)
+
+// allowedStatusTransitions допустимые переходы между статусами.
+// scheduled -> scheduled означает перенос момента публикации.
+var allowedStatusTransitions = map[string][]string{
+	StatusDraft:     {StatusScheduled, StatusPublished, StatusCancelled},
+	StatusScheduled: {StatusDraft, StatusScheduled, StatusPublished, StatusCancelled},
+	StatusPublished: {StatusCancelled, StatusPostponed, StatusSoldOut},
+	StatusSoldOut:   {StatusPublished, StatusCancelled, StatusPostponed},
+	StatusPostponed: {StatusPublished, StatusCancelled},
+	StatusCancelled: {},
+}
+
+var (
+	// ErrInvalidStatusTransition возвращается при недопустимой смене статуса
+	ErrInvalidStatusTransition = errors.New("invalid event status transition")
+	// ErrEventStatusConflict возвращается, если статус события изменился параллельно
+	ErrEventStatusConflict = errors.New("event status was changed concurrently")
+)
+
+const (
+	changeEventStatusQuery = `UPDATE events
+							SET status = $1, status_reason = $2, publish_at = $3, version = version + 1, updated_at = NOW()
+							WHERE id = $4 AND status = $5 AND deleted_at IS NULL
+							RETURNING updated_at, version`
+
+	// Уже заблокированные другой транзакцией события пропускаются до следующего запуска
+	dueEventIdsQuery = `SELECT id FROM events
+							WHERE status = 'scheduled' AND publish_at <= $1 AND deleted_at IS NULL
+							ORDER BY id
+							FOR UPDATE SKIP LOCKED`
+
+	publishEventsQuery = `UPDATE events
+							SET status = 'published', version = version + 1, updated_at = NOW()
+							WHERE id = ANY($1)`
+)
+
+// PublicStatuses статусы, в которых событие видно в публичных списках и поиске:
+// опубликованные события, в том числе распроданные, перенесенные и отмененные после публикации.
+func PublicStatuses() []string {
+	return []string{StatusPublished, StatusSoldOut, StatusPostponed, StatusCancelled}
+}
+
+// IsPublicStatus проверяет, что событие в этом статусе видно публичным клиентам.
+func IsPublicStatus(status string) bool {
+	return slices.Contains(PublicStatuses(), status)
+}
+
+// IsValidStatus проверяет, что статус известен.
+func IsValidStatus(status string) bool {
+	_, ok := allowedStatusTransitions[status]
+	return ok
+}
+
+// CanTransition сообщает, допустим ли переход между статусами.
+func CanTransition(from, to string) bool {
+	for _, allowed := range allowedStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// InitialEventStatus вычисляет статус нового события.
+// Будущий publishAt планирует публикацию, прошедший или publish=true публикует сразу,
+// иначе событие создается черновиком.
+func InitialEventStatus(publish bool, publishAt *time.Time, now time.Time) string {
+	switch {
+	case publishAt != nil && publishAt.After(now):
+		return StatusScheduled
+	case publish || publishAt != nil:
+		return StatusPublished
+	default:
+		return StatusDraft
+	}
+}
+
+// TransitionTo переводит событие в новый статус, проверяя допустимость перехода.
+// При публикации без PublishAt момент публикации устанавливается в now.
+func (e *Event) TransitionTo(status, reason string, publishAt *time.Time, now time.Time) error {
+	if !CanTransition(e.Status, status) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, e.Status, status)
+	}
+
+	e.Status = status
+	e.StatusReason = reason
+	if publishAt != nil {
+		e.PublishAt = publishAt
+	}
+	if status == StatusPublished && e.PublishAt == nil {
+		e.PublishAt = &now
+	}
+
+	return nil
+}
+
+// TicketsSoldOut сообщает, распроданы ли билеты во всех категориях события.
+func (e *Event) TicketsSoldOut() bool {
+	if len(e.TicketTiers) == 0 {
+		return false
+	}
+	for _, tier := range e.TicketTiers {
+		if tier.Availability != TicketSoldOut {
+			return false
+		}
+	}
+	return true
+}
+
+// SoldOutTransition возвращает статус, в который нужно перевести опубликованное событие
+// после изменения доступности билетов, или пустую строку, если статус не меняется.
+func (e *Event) SoldOutTransition() string {
+	switch {
+	case e.Status == StatusPublished && e.TicketsSoldOut():
+		return StatusSoldOut
+	case e.Status == StatusSoldOut && !e.TicketsSoldOut():
+		return StatusPublished
+	default:
+		return ""
+	}
+}
+
+// ChangeEventStatus сохраняет статус, причину и момент публикации события.
+// Запись происходит, только если в БД событие все еще в статусе from,
+// иначе возвращается ErrEventStatusConflict. Изменение и его ревизия записываются в одной транзакции.
+func (s *PostgresStore) ChangeEventStatus(parentCtx context.Context, event *Event, from string) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	return s.inTx(ctx, func(tx *PostgresStore) error {
+		previous, err := tx.getEventForUpdate(ctx, event.Id)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		var updatedAt time.Time
+		err = tx.db.QueryRow(
+			ctx,
+			changeEventStatusQuery,
+			event.Status,
+			event.StatusReason,
+			event.PublishAt,
+			event.Id,
+			from,
+		).Scan(&updatedAt, &event.Version)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("event %d is no longer %s: %w", event.Id, from, ErrEventStatusConflict)
+			}
+			return fmt.Errorf("failed to change status of event %d: %w", event.Id, err)
+		}
+
+		event.UpdatedAt = &updatedAt
+
+		_, err = tx.recordEventRevision(ctx, event.Id, RevisionStatus, previous)
+		return err
+	})
+}
+
+// PublishDueEvents публикует запланированные события, момент публикации которых наступил,
+// и возвращает их для переиндексации. Ревизии записываются от имени SystemActor.
+func (s *PostgresStore) PublishDueEvents(parentCtx context.Context, now time.Time) ([]*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	ctx = WithActor(ctx, SystemActor)
+
+	var published []*Event
+	err := s.inTx(ctx, func(tx *PostgresStore) error {
+		rows, err := tx.db.Query(ctx, dueEventIdsQuery, now)
+		if err != nil {
+			return fmt.Errorf("failed to select due events: %w", err)
+		}
+
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+		if err != nil {
+			return fmt.Errorf("failed to collect due event ids: %w", err)
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		previous, err := tx.GetEventsByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.db.Exec(ctx, publishEventsQuery, ids); err != nil {
+			return fmt.Errorf("failed to publish due events: %w", err)
+		}
+
+		for _, event := range previous {
+			current, err := tx.recordEventRevision(ctx, event.Id, RevisionStatus, event)
+			if err != nil {
+				return err
+			}
+			published = append(published, current)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return published, nil
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{StatusDraft, StatusScheduled, true},
+		{StatusDraft, StatusPublished, true},
+		{StatusDraft, StatusCancelled, true},
+		{StatusDraft, StatusSoldOut, false},
+		{StatusDraft, StatusPostponed, false},
+		{StatusScheduled, StatusScheduled, true}, // перенос момента публикации
+		{StatusScheduled, StatusDraft, true},
+		{StatusScheduled, StatusPostponed, false},
+		{StatusPublished, StatusSoldOut, true},
+		{StatusPublished, StatusPostponed, true},
+		{StatusPublished, StatusDraft, false},
+		{StatusPublished, StatusScheduled, false},
+		{StatusPublished, StatusPublished, false},
+		{StatusSoldOut, StatusPublished, true},
+		{StatusPostponed, StatusPublished, true},
+		{StatusPostponed, StatusSoldOut, false},
+		{StatusCancelled, StatusPublished, false},
+		{StatusCancelled, StatusDraft, false},
+		{"unknown", StatusPublished, false},
+		{StatusDraft, "unknown", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestStatusSets(t *testing.T) {
+	for _, status := range []string{StatusDraft, StatusScheduled, StatusPublished, StatusCancelled, StatusPostponed, StatusSoldOut} {
+		if !IsValidStatus(status) {
+			t.Errorf("IsValidStatus(%q) = false", status)
+		}
+	}
+	if IsValidStatus("archived") {
+		t.Error(`IsValidStatus("archived") = true`)
+	}
+
+	tests := []struct {
+		status string
+		public bool
+	}{
+		{StatusDraft, false},
+		{StatusScheduled, false},
+		{StatusPublished, true},
+		{StatusSoldOut, true},
+		{StatusPostponed, true},
+		{StatusCancelled, true},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicStatus(tt.status); got != tt.public {
+			t.Errorf("IsPublicStatus(%q) = %v, want %v", tt.status, got, tt.public)
+		}
+	}
+}
+
+func TestInitialEventStatus(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		publish   bool
+		publishAt *time.Time
+		want      string
+	}{
+		{"draft by default", false, nil, StatusDraft},
+		{"publish now", true, nil, StatusPublished},
+		{"future publish_at schedules", false, &future, StatusScheduled},
+		{"future publish_at wins over publish", true, &future, StatusScheduled},
+		{"past publish_at publishes", false, &past, StatusPublished},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InitialEventStatus(tt.publish, tt.publishAt, now); got != tt.want {
+				t.Errorf("InitialEventStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventTransitionTo(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	event := &Event{Status: StatusDraft}
+	if err := event.TransitionTo(StatusPublished, "", nil, now); err != nil {
+		t.Fatalf("draft -> published: %v", err)
+	}
+	if event.PublishAt == nil || !event.PublishAt.Equal(now) {
+		t.Errorf("PublishAt = %v, want %v", event.PublishAt, now)
+	}
+
+	if err := event.TransitionTo(StatusPostponed, "venue closed", nil, now); err != nil {
+		t.Fatalf("published -> postponed: %v", err)
+	}
+	if event.StatusReason != "venue closed" {
+		t.Errorf("StatusReason = %q, want %q", event.StatusReason, "venue closed")
+	}
+
+	err := event.TransitionTo(StatusDraft, "", nil, now)
+	if !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("postponed -> draft error = %v, want ErrInvalidStatusTransition", err)
+	}
+	if event.Status != StatusPostponed {
+		t.Errorf("Status after rejected transition = %q, want %q", event.Status, StatusPostponed)
+	}
+}
+
+func TestSoldOutTransition(t *testing.T) {
+	soldOut := []TicketTier{{Availability: TicketSoldOut}, {Availability: TicketSoldOut}}
+	partial := []TicketTier{{Availability: TicketSoldOut}, {Availability: TicketLimited}}
+
+	tests := []struct {
+		name   string
+		status string
+		tiers  []TicketTier
+		want   string
+	}{
+		{"published sells out", StatusPublished, soldOut, StatusSoldOut},
+		{"published with tickets left", StatusPublished, partial, ""},
+		{"published without tiers", StatusPublished, nil, ""},
+		{"sold out returns to sale", StatusSoldOut, partial, StatusPublished},
+		{"sold out stays", StatusSoldOut, soldOut, ""},
+		{"draft is not touched", StatusDraft, soldOut, ""},
+		{"postponed is not touched", StatusPostponed, soldOut, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &Event{Status: tt.status, TicketTiers: tt.tiers}
+			if got := event.SoldOutTransition(); got != tt.want {
+				t.Errorf("SoldOutTransition() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
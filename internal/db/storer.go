@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -14,11 +15,27 @@ type DBTX interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// VersionConflictError возвращается при изменении записи, версия которой
+// не совпадает с переданной: запись успели изменить с момента чтения
+type VersionConflictError struct {
+	Entity  string
+	ID      int64
+	Current int64 // Текущая версия записи
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s %d version mismatch, current version is %d", e.Entity, e.ID, e.Current)
 }
 
 // PostgresStore реализует EventStore с использованием PostgreSQL.
 type PostgresStore struct {
 	db DBTX
+
+	// Изменения событий, записываемые в журнал перед фиксацией внешней транзакции; nil вне транзакции
+	changes *[]pendingEventChange
 }
 
 // NewPostgresStore создает новый экземпляр PostgresStore.
@@ -28,20 +45,131 @@ func NewPosgresStore(pool DBTX) *PostgresStore {
 	}
 }
 
+// inTx выполняет fn в транзакции: хранилище, переданное в fn, работает через нее.
+// Если s уже работает внутри транзакции, создается точка сохранения.
+// Журнал изменений событий пишется непосредственно перед фиксацией внешней транзакции.
+func (s *PostgresStore) inTx(ctx context.Context, fn func(tx *PostgresStore) error) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // После Commit ничего не делает
+
+	txStore := *s
+	txStore.db = tx
+
+	outer := s.changes == nil
+	if outer {
+		txStore.changes = &[]pendingEventChange{}
+	}
+	recorded := len(*txStore.changes)
+
+	if err := fn(&txStore); err != nil {
+		// Изменения откаченной точки сохранения в журнал не попадают
+		*txStore.changes = (*txStore.changes)[:recorded]
+		return err
+	}
+
+	if outer {
+		if err := txStore.writeEventChanges(ctx, *txStore.changes); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // EventStore определяет методы для работы с хранилищем событий.
 type EventStore interface {
 	// Базовые CRUD операции для событий
 	CreateEvent(ctx context.Context, event *Event) (*Event, error)
 	UpdateEvent(ctx context.Context, event *Event) (*Event, error)
+	UpdateEventFields(ctx context.Context, event *Event, fields []string) (*Event, error)
 	GetEvents(ctx context.Context) ([]*Event, error)
 	GetEventByID(ctx context.Context, id int64) (*Event, error)
 	DeleteEvent(ctx context.Context, id int64) (*Event, error)
 	GetEventsByCategory(ctx context.Context, categoryID int64) ([]*Event, error)
+	GetEventsByIDs(ctx context.Context, ids []int64) ([]*Event, error)
+
+	// Пакетные операции: результаты и ошибки по элементам
+	BatchCreateEvents(ctx context.Context, events []*Event, atomic bool) ([]*Event, []error)
+	BatchUpdateEvents(ctx context.Context, events []*Event, fields [][]string, atomic bool) ([]*Event, []error)
+	BatchDeleteEvents(ctx context.Context, ids []int64, atomic bool) ([]*Event, []error)
+
+	// Мягкое удаление, восстановление и очистка
+	GetDeletedEventByID(ctx context.Context, id int64) (*Event, error)
+	GetDeletedEvents(ctx context.Context, limit, offset int) ([]*Event, int64, error)
+	RestoreEvent(ctx context.Context, id int64) (*Event, error)
+	PurgeDeletedEvents(ctx context.Context, before time.Time) (int64, error)
+
+	// История изменений
+	ListEventRevisions(ctx context.Context, eventID int64, limit, offset int) ([]*EventRevision, int64, error)
+	GetEventRevision(ctx context.Context, eventID int64, revision int) (*EventRevision, error)
+	RevertEventToRevision(ctx context.Context, eventID int64, revision int) (*Event, error)
+
+	// Журнал изменений для подписки на изменения
+	GetEventChanges(ctx context.Context, filter *EventChangeFilter) ([]*EventChange, error)
+	GetLatestEventChangeSeq(ctx context.Context) (int64, error)
+	GetOldestEventChangeSeq(ctx context.Context) (int64, error)
+	GetOldestEventChangeTime(ctx context.Context) (*time.Time, error)
+	PurgeEventChanges(ctx context.Context, before time.Time) (int64, error)
+
+	// Жизненный цикл публикации
+	ChangeEventStatus(ctx context.Context, event *Event, from string) error
+	PublishDueEvents(ctx context.Context, now time.Time) ([]*Event, error)
 
 	// Методы событий с поддержкой фильтрации
 	GetEventsWithFilter(ctx context.Context, filter *EventFilter) ([]*Event, error)
 	CountEventsWithFilter(ctx context.Context, filter *EventFilter) (int64, error)
 	GetEventsWithFilterAndCount(ctx context.Context, filter *EventFilter) ([]*Event, int64, error)
+	StreamEventsWithFilter(ctx context.Context, filter *EventFilter, fn func(events []*Event) error) error
+	GetEventFacets(ctx context.Context, filter *EventFilter, req *FacetRequest) (*EventFacets, error)
+
+	// Теги событий
+	SetEventTags(ctx context.Context, eventID int64, tags []string) error
+	SetEventTicketTiers(ctx context.Context, eventID int64, tiers []TicketTier) error
+
+	// Изображения событий
+	AddEventImage(ctx context.Context, eventID int64, image *EventImage) (*Event, error)
+	RemoveEventImage(ctx context.Context, eventID, imageID int64) (*Event, error)
+	ReorderEventImages(ctx context.Context, eventID int64, imageIDs []int64) (*Event, error)
+
+	// Изменения отдельных вхождений повторяющихся событий
+	UpsertOccurrenceOverride(ctx context.Context, override *OccurrenceOverride) error
+	GetOccurrenceOverrides(ctx context.Context, eventIDs []int64) (map[int64][]*OccurrenceOverride, error)
+
+	// Внешние источники и идемпотентный импорт событий
+	CreateSource(ctx context.Context, source *Source) error
+	GetSourceByID(ctx context.Context, id int64) (*Source, error)
+	GetSourceBySlug(ctx context.Context, slug string) (*Source, error)
+	ListSources(ctx context.Context, activeOnly bool) ([]*Source, error)
+	UpdateSource(ctx context.Context, source *Source) error
+	UpsertEvent(ctx context.Context, event *Event) (*Event, string, error)
+
+	// Дубликаты событий из разных источников
+	MergeEvents(ctx context.Context, canonicalID int64, duplicateIDs []int64) (*Event, []*Event, error)
+	ResolveEventRedirect(ctx context.Context, id int64) (int64, error)
+	GetEventsStartingBetween(ctx context.Context, from, to time.Time, limit int) ([]*Event, error)
+
+	// Базовые CRUD операции для площадок
+	CreateVenue(ctx context.Context, venue *Venue) error
+	GetVenueByID(ctx context.Context, id int64) (*Venue, error)
+	ListVenues(ctx context.Context, city string) ([]*Venue, error)
+	UpdateVenue(ctx context.Context, venue *Venue) error
+	DeleteVenue(ctx context.Context, id int64) error
+	GetEventsByVenue(ctx context.Context, venueID int64) ([]*Event, error)
+
+	// Базовые CRUD операции для организаторов
+	CreateOrganizer(ctx context.Context, organizer *Organizer) error
+	GetOrganizerByID(ctx context.Context, id int64) (*Organizer, error)
+	ListOrganizers(ctx context.Context, verifiedOnly bool) ([]*Organizer, error)
+	UpdateOrganizer(ctx context.Context, organizer *Organizer) error
+	DeleteOrganizer(ctx context.Context, id int64) error
+	GetEventsByOrganizer(ctx context.Context, organizerID int64) ([]*Event, error)
 
 	// Базовые CRUD операции для категорий
 	CreateCategory(ctx context.Context, category *Category) error
@@ -49,6 +177,8 @@ type EventStore interface {
 	GetCategoryByID(parentCtx context.Context, id int) (*Category, error)
 	UpdateCategory(parentCtx context.Context, category *Category) error
 	DeleteCategory(parentCtx context.Context, id int) error
+	GetCategoryDescendantIDs(ctx context.Context, ids []int64) ([]int64, error)
+	PurgeDeletedCategories(ctx context.Context, before time.Time) (int64, error)
 }
 
 // CreatePostgresPool создает и проверяет пул соединений к PostgreSQL.
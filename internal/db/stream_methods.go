@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// streamFetchSize количество строк, читаемых из курсора за один FETCH
+	streamFetchSize = 500
+	// streamFetchTimeout ограничивает один FETCH: первый из них сортирует всю выборку
+	streamFetchTimeout = 30 * time.Second
+
+	declareEventsCursorQuery = `DECLARE events_stream NO SCROLL CURSOR FOR `
+)
+
+// fetchEventsCursorQuery читает из курсора порцию размером streamFetchSize
+var fetchEventsCursorQuery = fmt.Sprintf("FETCH FORWARD %d FROM events_stream", streamFetchSize)
+
+// StreamEventsWithFilter читает события фильтра через курсор PostgreSQL порциями
+// и передает каждую порцию в fn. Следующая порция читается только после возврата из fn,
+// поэтому медленный получатель не накапливает строки в памяти.
+// Пагинация фильтра не учитывается. Ошибка fn или отмена ctx прекращают чтение.
+func (s *PostgresStore) StreamEventsWithFilter(ctx context.Context, filter *EventFilter, fn func(events []*Event) error) error {
+	if err := validateFilter(filter); err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+
+	unpaged := *filter
+	unpaged.Limit, unpaged.Offset = nil, nil
+	query, args := s.buildFilteredQuery(&unpaged)
+
+	// Курсор живет до конца транзакции
+	return s.inTx(ctx, func(tx *PostgresStore) error {
+		if _, err := tx.db.Exec(ctx, declareEventsCursorQuery+query, args...); err != nil {
+			return fmt.Errorf("failed to declare events cursor: %w", err)
+		}
+
+		for {
+			events, err := tx.fetchEvents(ctx)
+			if err != nil {
+				return err
+			}
+			if len(events) == 0 {
+				return nil
+			}
+
+			if err := fn(events); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// fetchEvents читает следующую порцию событий из курсора events_stream.
+func (s *PostgresStore) fetchEvents(parentCtx context.Context) ([]*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, streamFetchTimeout)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, fetchEventsCursorQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events from cursor: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*Event, 0, streamFetchSize)
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan streamed event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating streamed event rows: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxTagLength максимальная длина имени тега (соответствует tags.name)
+const MaxTagLength = 100
+
+const (
+	// Один запрос создает недостающие теги и приводит связи события к переданному набору.
+	// DO UPDATE вместо DO NOTHING нужен, чтобы RETURNING вернул id и уже существующих тегов.
+	setEventTagsQuery = `WITH input AS (
+							SELECT DISTINCT ON (slug) name, slug
+							FROM unnest($2::text[], $3::text[]) AS i(name, slug)
+						),
+						wanted AS (
+							INSERT INTO tags (name, slug)
+							SELECT name, slug FROM input
+							ON CONFLICT (slug) DO UPDATE SET name = tags.name
+							RETURNING id
+						),
+						removed AS (
+							DELETE FROM event_tags
+							WHERE event_id = $1 AND tag_id NOT IN (SELECT id FROM wanted)
+						)
+						INSERT INTO event_tags (event_id, tag_id)
+						SELECT $1, id FROM wanted
+						ON CONFLICT DO NOTHING`
+
+	// eventTagsColumn подзапрос тегов события для списка колонок SELECT
+	eventTagsColumn = `ARRAY(SELECT t.name FROM event_tags et JOIN tags t ON t.id = et.tag_id
+					WHERE et.event_id = events.id ORDER BY t.name) AS tags`
+)
+
+// SetEventTags заменяет теги события переданным набором, создавая недостающие теги.
+// Пустой набор удаляет все теги события.
+func (s *PostgresStore) SetEventTags(parentCtx context.Context, eventID int64, tags []string) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	tags = NormalizeTags(tags)
+	slugs := make([]string, len(tags))
+	for i, tag := range tags {
+		slugs[i] = TagSlug(tag)
+	}
+
+	if _, err := s.db.Exec(ctx, setEventTagsQuery, eventID, tags, slugs); err != nil {
+		return fmt.Errorf("failed to set tags for event %d: %w", eventID, err)
+	}
+
+	return nil
+}
+
+// NormalizeTag убирает лишние пробелы в имени тега, регистр сохраняется.
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(tag), " ")
+}
+
+// TagSlug возвращает ключ, по которому теги сравниваются и фильтруются.
+func TagSlug(tag string) string {
+	return strings.ToLower(NormalizeTag(tag))
+}
+
+// NormalizeTags нормализует теги, отбрасывая пустые и повторы (без учета регистра).
+// Порядок первых вхождений сохраняется.
+func NormalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		slug := TagSlug(tag)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		result = append(result, tag)
+	}
+
+	return result
+}
+
+// TagSlugs возвращает уникальные ключи тегов.
+func TagSlugs(tags []string) []string {
+	normalized := NormalizeTags(tags)
+	slugs := make([]string, len(normalized))
+	for i, tag := range normalized {
+		slugs[i] = TagSlug(tag)
+	}
+	return slugs
+}
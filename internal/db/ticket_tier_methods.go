@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultCurrency валюта цен по умолчанию (ISO 4217)
+const DefaultCurrency = "RUB"
+
+// Доступность билетов в категории
+const (
+	TicketAvailable   = "available"   // Билеты в продаже
+	TicketLimited     = "limited"     // Осталось мало билетов
+	TicketSoldOut     = "sold_out"    // Билеты распроданы
+	TicketUnavailable = "unavailable" // Продажа еще не началась или закрыта
+)
+
+// ErrPriceDerived возвращается при явном изменении цены события с билетными категориями:
+// цена такого события вычисляется из категорий и иначе была бы молча перезаписана
+var ErrPriceDerived = errors.New("price is derived from ticket_tiers")
+
+// MaxTicketTierNameLength максимальная длина названия билетной категории (соответствует event_ticket_tiers.name)
+const MaxTicketTierNameLength = 100
+
+const (
+	// Один запрос заменяет билетные категории события переданным набором.
+	// Порядок категорий сохраняется через WITH ORDINALITY.
+	setEventTicketTiersQuery = `WITH removed AS (
+							DELETE FROM event_ticket_tiers WHERE event_id = $1
+						)
+						INSERT INTO event_ticket_tiers (event_id, name, min_price, max_price, currency, availability, purchase_url, position)
+						SELECT $1, t.name, t.min_price, t.max_price, t.currency, t.availability, t.purchase_url, t.position
+						FROM unnest($2::text[], $3::real[], $4::real[], $5::text[], $6::text[], $7::text[])
+						     WITH ORDINALITY AS t(name, min_price, max_price, currency, availability, purchase_url, position)`
+
+	// eventTicketTiersColumn подзапрос билетных категорий события для списка колонок SELECT
+	eventTicketTiersColumn = `COALESCE((SELECT json_agg(json_build_object(
+						'id', tt.id, 'name', tt.name, 'min_price', tt.min_price, 'max_price', tt.max_price,
+						'currency', tt.currency, 'availability', tt.availability, 'purchase_url', tt.purchase_url
+					) ORDER BY tt.position) FROM event_ticket_tiers tt WHERE tt.event_id = events.id), '[]') AS ticket_tiers`
+)
+
+// SetEventTicketTiers заменяет билетные категории события переданным набором.
+// Пустой набор удаляет все категории события.
+func (s *PostgresStore) SetEventTicketTiers(parentCtx context.Context, eventID int64, tiers []TicketTier) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	names := make([]string, len(tiers))
+	minPrices := make([]float32, len(tiers))
+	maxPrices := make([]*float32, len(tiers))
+	currencies := make([]string, len(tiers))
+	availability := make([]string, len(tiers))
+	urls := make([]string, len(tiers))
+
+	for i, tier := range tiers {
+		names[i] = tier.Name
+		minPrices[i] = tier.MinPrice
+		maxPrices[i] = tier.MaxPrice
+		currencies[i] = tier.Currency
+		availability[i] = tier.Availability
+		urls[i] = tier.PurchaseURL
+	}
+
+	_, err := s.db.Exec(ctx, setEventTicketTiersQuery, eventID, names, minPrices, maxPrices, currencies, availability, urls)
+	if err != nil {
+		return fmt.Errorf("failed to set ticket tiers for event %d: %w", eventID, err)
+	}
+
+	return nil
+}
+
+// NormalizeTicketTiers убирает лишние пробелы и заполняет значения по умолчанию:
+// валюту DefaultCurrency и доступность TicketAvailable.
+// Верхняя граница, равная нижней, превращается в фиксированную цену.
+func NormalizeTicketTiers(tiers []TicketTier) []TicketTier {
+	result := make([]TicketTier, 0, len(tiers))
+
+	for _, tier := range tiers {
+		tier.Name = strings.Join(strings.Fields(tier.Name), " ")
+		tier.Currency = strings.ToUpper(strings.TrimSpace(tier.Currency))
+		if tier.Currency == "" {
+			tier.Currency = DefaultCurrency
+		}
+		tier.Availability = strings.ToLower(strings.TrimSpace(tier.Availability))
+		if tier.Availability == "" {
+			tier.Availability = TicketAvailable
+		}
+		tier.PurchaseURL = strings.TrimSpace(tier.PurchaseURL)
+		if tier.MaxPrice != nil && *tier.MaxPrice == tier.MinPrice {
+			tier.MaxPrice = nil
+		}
+		result = append(result, tier)
+	}
+
+	return result
+}
+
+// IsValidTicketAvailability проверяет значение доступности билетов.
+func IsValidTicketAvailability(availability string) bool {
+	switch availability {
+	case TicketAvailable, TicketLimited, TicketSoldOut, TicketUnavailable:
+		return true
+	}
+	return false
+}
+
+// UpperPrice возвращает верхнюю границу цены категории.
+func (t TicketTier) UpperPrice() float32 {
+	if t.MaxPrice != nil {
+		return *t.MaxPrice
+	}
+	return t.MinPrice
+}
+
+// normalizePricing вычисляет диапазон цен и валюту события по билетным категориям.
+// Без категорий диапазон вырождается в Price. С категориями Price приравнивается
+// к нижней границе, чтобы старые клиенты видели цену "от".
+func (e *Event) normalizePricing() {
+	if len(e.TicketTiers) == 0 {
+		price := e.Price
+		e.PriceFrom = &price
+		e.PriceTo = &price
+		if e.Currency == "" {
+			e.Currency = DefaultCurrency
+		}
+		return
+	}
+
+	from := e.TicketTiers[0].MinPrice
+	to := e.TicketTiers[0].UpperPrice()
+	for _, tier := range e.TicketTiers[1:] {
+		from = min(from, tier.MinPrice)
+		to = max(to, tier.UpperPrice())
+	}
+
+	e.PriceFrom = &from
+	e.PriceTo = &to
+	e.Currency = e.TicketTiers[0].Currency
+	e.Price = from
+}
+
+// IsFree сообщает, бесплатно ли событие целиком.
+func (e *Event) IsFree() bool {
+	return e.PriceTo != nil && *e.PriceTo == 0
+}
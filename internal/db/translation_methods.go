@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultLocale язык содержимого, которое хранится в самом событии
+const DefaultLocale = "ru"
+
+// MaxTranslationNameLength максимальная длина переведенного названия (соответствует event_translations.name)
+const MaxTranslationNameLength = 255
+
+const (
+	// Один запрос заменяет переводы события переданным набором
+	setEventTranslationsQuery = `WITH input AS (
+							SELECT * FROM unnest($2::text[], $3::text[], $4::text[]) AS i(locale, name, description)
+						),
+						removed AS (
+							DELETE FROM event_translations
+							WHERE event_id = $1 AND locale NOT IN (SELECT locale FROM input)
+						)
+						INSERT INTO event_translations (event_id, locale, name, description)
+						SELECT $1, locale, name, description FROM input
+						ON CONFLICT (event_id, locale) DO UPDATE
+						SET name = EXCLUDED.name, description = EXCLUDED.description`
+
+	// eventTranslationsColumn подзапрос переводов события для списка колонок SELECT
+	eventTranslationsColumn = `COALESCE((SELECT json_agg(json_build_object(
+						'locale', tr.locale, 'name', tr.name, 'description', tr.description
+					) ORDER BY tr.locale) FROM event_translations tr WHERE tr.event_id = events.id), '[]') AS translations`
+)
+
+// SetEventTranslations заменяет переводы события переданным набором.
+// Пустой набор удаляет все переводы события.
+func (s *PostgresStore) SetEventTranslations(parentCtx context.Context, eventID int64, translations []EventTranslation) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	locales := make([]string, len(translations))
+	names := make([]string, len(translations))
+	descriptions := make([]string, len(translations))
+
+	for i, translation := range translations {
+		locales[i] = translation.Locale
+		names[i] = translation.Name
+		descriptions[i] = translation.Description
+	}
+
+	_, err := s.db.Exec(ctx, setEventTranslationsQuery, eventID, locales, names, descriptions)
+	if err != nil {
+		return fmt.Errorf("failed to set translations for event %d: %w", eventID, err)
+	}
+
+	return nil
+}
+
+// NormalizeTranslations приводит теги языков к виду "en" или "en-GB", убирает лишние
+// пробелы и сортирует переводы по языку. При повторе языка остается последний перевод.
+func NormalizeTranslations(translations []EventTranslation) []EventTranslation {
+	byLocale := make(map[string]EventTranslation, len(translations))
+	for _, translation := range translations {
+		translation.Locale = NormalizeLocale(translation.Locale)
+		translation.Name = strings.Join(strings.Fields(translation.Name), " ")
+		translation.Description = strings.TrimSpace(translation.Description)
+		byLocale[translation.Locale] = translation
+	}
+
+	result := make([]EventTranslation, 0, len(byLocale))
+	for _, translation := range byLocale {
+		result = append(result, translation)
+	}
+	slices.SortFunc(result, func(a, b EventTranslation) int { return strings.Compare(a.Locale, b.Locale) })
+
+	return result
+}
+
+// NormalizeLocale приводит тег языка к виду "en" или "en-GB". Принимает и "en_gb".
+func NormalizeLocale(locale string) string {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	lang, region, found := strings.Cut(locale, "-")
+	if !found {
+		return strings.ToLower(lang)
+	}
+	return strings.ToLower(lang) + "-" + strings.ToUpper(region)
+}
+
+// IsValidLocale проверяет нормализованный тег языка: код языка ISO 639 из двух-трех букв
+// и необязательный регион из двух букв или трех цифр.
+func IsValidLocale(locale string) bool {
+	lang, region, found := strings.Cut(locale, "-")
+	if len(lang) < 2 || len(lang) > 3 || !isASCIIRange(lang, 'a', 'z') {
+		return false
+	}
+	if !found {
+		return true
+	}
+	return (len(region) == 2 && isASCIIRange(region, 'A', 'Z')) ||
+		(len(region) == 3 && isASCIIRange(region, '0', '9'))
+}
+
+// LocaleLanguage возвращает код языка тега без региона.
+func LocaleLanguage(locale string) string {
+	lang, _, _ := strings.Cut(locale, "-")
+	return lang
+}
+
+// ResolveLocale выбирает среди доступных тегов наиболее подходящий запрошенному:
+// сам тег, затем язык без региона, затем тот же язык с другим регионом.
+// Пустой результат означает, что подходит только содержимое на DefaultLocale.
+func ResolveLocale(available []string, requested string) string {
+	requested = NormalizeLocale(requested)
+	if requested == "" {
+		return ""
+	}
+
+	lang := LocaleLanguage(requested)
+	if slices.Contains(available, requested) {
+		return requested
+	}
+	if slices.Contains(available, lang) {
+		return lang
+	}
+
+	var regional []string
+	for _, locale := range available {
+		if LocaleLanguage(locale) == lang {
+			regional = append(regional, locale)
+		}
+	}
+	if len(regional) == 0 {
+		return ""
+	}
+	return slices.Min(regional)
+}
+
+// isASCIIRange проверяет, что все символы строки лежат в диапазоне [from, to]
+func isASCIIRange(s string, from, to byte) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < from || s[i] > to {
+			return false
+		}
+	}
+	return true
+}
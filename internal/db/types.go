@@ -1,6 +1,18 @@
 package db
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// DefaultTimezone часовой пояс, в котором интерпретируются события без явно указанной зоны
+const DefaultTimezone = "Europe/Moscow"
+
+// Форматы устаревших строковых полей date/time
+const (
+	LegacyDateLayout = "2006-01-02"
+	LegacyTimeLayout = "15:04"
+)
 
 // Event представляет событие в системе
 type Event struct {
@@ -8,14 +20,68 @@ type Event struct {
 	Name        string
 	Description string
 	CategoryID  int64
-	Date        string
-	Time        string
+	Date        string // Устарело: используйте StartsAt
+	Time        string // Устарело: используйте StartsAt
 	Location    string
 	Price       float32
 	Image       string
 	Source      string
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	Timezone    string // IANA имя часового пояса, например Europe/Moscow
 	CreatedAt   time.Time
 	UpdatedAt   *time.Time
+
+	// Повторение (RFC 5545)
+	RecurrenceRule    string      // RRULE без префикса, пусто для разовых событий
+	RecurrenceExDates []time.Time // EXDATE: исключенные начала вхождений
+	RecurrenceUntil   *time.Time  // Окончание последнего вхождения, nil для бесконечной серии
+
+	// Теги в порядке имени, дополняют единственную категорию
+	Tags []string
+
+	// Площадка проведения. Venue заполняется из venues при чтении из БД
+	VenueID *int64
+	Venue   *Venue
+
+	// Организатор. Organizer заполняется из organizers при чтении из БД (только ID, имя и подтверждение)
+	OrganizerID *int64
+	Organizer   *Organizer
+
+	// Билетные категории и вычисленный по ним диапазон цен.
+	// Без категорий диапазон совпадает с Price.
+	TicketTiers []TicketTier
+	PriceFrom   *float32
+	PriceTo     *float32
+	Currency    string // ISO 4217, например RUB
+
+	// Жизненный цикл публикации. Статус меняется только через TransitionTo
+	Status       string
+	PublishAt    *time.Time // Момент (запланированной) публикации
+	StatusReason string     // Причина отмены или переноса
+
+	// Момент мягкого удаления, nil для неудаленных событий
+	DeletedAt *time.Time
+
+	// Версия строки для оптимистичной блокировки, растет при каждом изменении
+	Version int64
+
+	// Внешний источник и идентификатор события в нем, заполняются при импорте через UpsertEvent
+	SourceID   *int64
+	ExternalID string
+
+	// Изображения в порядке показа. Image содержит URL обложки, если она есть
+	Images []EventImage
+
+	// Переводы названия и описания по языкам, Name и Description на DefaultLocale
+	Translations []EventTranslation
+
+	// Заполняются только для развернутых вхождений серии (не хранятся в events)
+	OccurrenceStart *time.Time // Исходное начало вхождения по правилу
+	IsOverride      bool       // Вхождение изменено через OccurrenceOverride
+
+	// Заполняется только в результатах гео-поиска
+	DistanceKm *float64
 }
 
 // CreateEventParams содержит параметры для создания нового события
@@ -29,6 +95,22 @@ type CreateEventParams struct {
 	Price       float32
 	Image       string
 	Source      string
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	Timezone    string
+
+	RecurrenceRule    string
+	RecurrenceExDates []time.Time
+
+	VenueID      *int64
+	OrganizerID  *int64
+	Tags         []string
+	TicketTiers  []TicketTier
+	Translations []EventTranslation
+
+	// Публикация: без них событие создается черновиком
+	Publish   bool
+	PublishAt *time.Time
 }
 
 // UpdateEventParams содержит параметры для обновления существующего события
@@ -42,25 +124,155 @@ type UpdateEventParams struct {
 	Price       float32
 	Image       string
 	Source      string
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	Timezone    string
+
+	RecurrenceRule    string
+	RecurrenceExDates []time.Time
+
+	VenueID      *int64
+	OrganizerID  *int64
+	Tags         []string
+	TicketTiers  []TicketTier
+	Translations []EventTranslation
+
+	// Маска обновления (EventField*): применяются только перечисленные поля.
+	// Пустая маска означает полное обновление
+	Fields []string
+}
+
+// OccurrenceOverride изменение одного вхождения повторяющегося события.
+// Пустые (nil) поля наследуются от серии.
+type OccurrenceOverride struct {
+	Id              int64
+	EventID         int64
+	OccurrenceStart time.Time // Исходное начало вхождения по правилу
+	Name            *string
+	Description     *string
+	StartsAt        *time.Time
+	EndsAt          *time.Time
+	Location        *string
+	Price           *float32
+	IsCancelled     bool
+	CreatedAt       time.Time
+	UpdatedAt       *time.Time
+}
+
+// TicketTier билетная категория события, например "Партер" или "Детский"
+type TicketTier struct {
+	Id           int64    `json:"id"`
+	Name         string   `json:"name"`
+	MinPrice     float32  `json:"min_price"`
+	MaxPrice     *float32 `json:"max_price,omitempty"` // nil для фиксированной цены MinPrice
+	Currency     string   `json:"currency"`
+	Availability string   `json:"availability"`
+	PurchaseURL  string   `json:"purchase_url,omitempty"`
+}
+
+// EventTranslation перевод названия и описания события на язык Locale.
+// Пустое поле перевода берется из следующего языка цепочки
+type EventTranslation struct {
+	Locale      string `json:"locale"` // Тег языка, например "en" или "en-GB"
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// EventImage изображение события
+type EventImage struct {
+	Id      int64  `json:"id"`
+	URL     string `json:"url"`
+	Width   *int   `json:"width,omitempty"` // Размеры в пикселях, nil если неизвестны
+	Height  *int   `json:"height,omitempty"`
+	AltText string `json:"alt_text"`
+	Role    string `json:"role"` // Одна из ImageRole*
 }
 
 // Category представляет категорию событий
 type Category struct {
 	Id        int
 	Name      string
+	ParentID  *int   // nil для корневой категории
+	Version   int64  // Версия строки для оптимистичной блокировки
+	Path      string // Имена от корня через " / ", вычисляется при чтении
+	Depth     int    // 0 для корневой категории
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// Заполняется только при построении дерева (BuildCategoryTree)
+	Children []*Category
 }
 
 // CreateCategoryReq представляет запрос на создание новой категории
 type CreateCategoryReq struct {
-	Name string
+	Name     string
+	ParentID *int
+}
+
+// Venue представляет площадку проведения событий
+type Venue struct {
+	Id        int64
+	Name      string
+	Address   string
+	City      string
+	Latitude  *float64 // Координаты задаются парой или отсутствуют
+	Longitude *float64
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+}
+
+// Organizer организатор событий, например театр или промоутер
+type Organizer struct {
+	Id         int64
+	Name       string
+	Email      string
+	Phone      string
+	Website    string
+	IsVerified bool // Подтвержден модерацией
+	CreatedAt  time.Time
+	UpdatedAt  *time.Time
+}
+
+// CreateOrganizerReq представляет запрос на создание нового организатора
+type CreateOrganizerReq struct {
+	Name       string
+	Email      string
+	Phone      string
+	Website    string
+	IsVerified bool
+}
+
+// Source внешний источник событий, например парсер афиши или API партнера
+type Source struct {
+	Id          int64
+	Slug        string // Неизменяемый идентификатор, записывается в Event.Source
+	DisplayName string
+	TrustLevel  int // 0 - не доверяем, 100 - полностью
+	IsActive    bool
+	CreatedAt   time.Time
+	UpdatedAt   *time.Time
+}
+
+// CreateSourceReq представляет запрос на создание нового источника
+type CreateSourceReq struct {
+	Slug        string
+	DisplayName string
+	TrustLevel  int
+}
+
+// CreateVenueReq представляет запрос на создание новой площадки
+type CreateVenueReq struct {
+	Name      string
+	Address   string
+	City      string
+	Latitude  *float64
+	Longitude *float64
 }
 
 // NewEventFromCreateRequest создает новый экземпляр Event на основе параметров создания.
 // CreatedAt устанавливается текущим временем, UpdatedAt остается nil.
 func NewEventFromCreateRequest(params CreateEventParams) *Event {
-	return &Event{
+	event := &Event{
 		Name:        params.Name,
 		Description: params.Description,
 		CategoryID:  params.CategoryID,
@@ -70,33 +282,282 @@ func NewEventFromCreateRequest(params CreateEventParams) *Event {
 		Price:       params.Price,
 		Image:       params.Image,
 		Source:      params.Source,
+		StartsAt:    params.StartsAt,
+		EndsAt:      params.EndsAt,
+		Timezone:    params.Timezone,
+
+		RecurrenceRule:    params.RecurrenceRule,
+		RecurrenceExDates: params.RecurrenceExDates,
+		VenueID:           params.VenueID,
+		OrganizerID:       params.OrganizerID,
+		Tags:              NormalizeTags(params.Tags),
+		TicketTiers:       NormalizeTicketTiers(params.TicketTiers),
+		Translations:      NormalizeTranslations(params.Translations),
+		Status:            InitialEventStatus(params.Publish, params.PublishAt, time.Now()),
+		PublishAt:         params.PublishAt,
 		// CreatedAt будет установлено БД или в методе CreateEvent
 		// UpdatedAt остается nil или будет установлено БД/методом CreateEvent
 	}
+	event.normalizeSchedule()
+	event.normalizePricing()
+
+	if event.Status == StatusPublished && event.PublishAt == nil {
+		now := time.Now()
+		event.PublishAt = &now
+	}
+
+	return event
 }
 
 // ApplyUpdate применяет изменения из UpdateEventParams к существующему событию.
+// С маской params.Fields меняются только перечисленные поля, остальные сохраняют текущие значения.
+// Маска с price для события, у которого после изменения остаются билетные категории,
+// отклоняется с ErrPriceDerived, и событие не меняется.
 // Этот метод полезен, если ты хочешь обновить объект Event в памяти перед отправкой в БД,
 // или если UpdateEventParams содержит опциональные поля (с указателями).
-func (e *Event) ApplyUpdate(params UpdateEventParams) {
-	e.Name = params.Name
-	e.Description = params.Description
-	e.CategoryID = params.CategoryID
-	e.Date = params.Date
-	e.Time = params.Time
-	e.Location = params.Location
-	e.Price = params.Price
-	e.Image = params.Image
-	e.Source = params.Source
+func (e *Event) ApplyUpdate(params UpdateEventParams) error {
+	has := func(field string) bool { return InMask(params.Fields, field) }
+
+	if len(params.Fields) > 0 && has(EventFieldPrice) {
+		tiers := e.TicketTiers
+		if has(EventFieldTicketTiers) {
+			tiers = params.TicketTiers
+		}
+		if len(tiers) > 0 {
+			return ErrPriceDerived
+		}
+	}
+
+	if has(EventFieldName) {
+		e.Name = params.Name
+	}
+	if has(EventFieldDescription) {
+		e.Description = params.Description
+	}
+	if has(EventFieldCategoryID) {
+		e.CategoryID = params.CategoryID
+	}
+	if has(EventFieldDate) {
+		e.Date = params.Date
+	}
+	if has(EventFieldTime) {
+		e.Time = params.Time
+	}
+	if has(EventFieldLocation) {
+		e.Location = params.Location
+	}
+	if has(EventFieldPrice) {
+		e.Price = params.Price
+	}
+	if has(EventFieldImage) {
+		e.Image = params.Image
+	}
+	if has(EventFieldSource) {
+		e.Source = params.Source
+	}
+	// Устаревшие date/time без starts_at задают начало: текущее starts_at пересчитывается из них
+	switch {
+	case has(EventFieldStartsAt):
+		e.StartsAt = params.StartsAt
+	case has(EventFieldDate) || has(EventFieldTime):
+		e.StartsAt = nil
+	}
+	if has(EventFieldEndsAt) {
+		e.EndsAt = params.EndsAt
+	}
+	if has(EventFieldTimezone) {
+		e.Timezone = params.Timezone
+	}
+	if has(EventFieldRecurrenceRule) {
+		e.RecurrenceRule = params.RecurrenceRule
+	}
+	if has(EventFieldRecurrenceExDates) {
+		e.RecurrenceExDates = params.RecurrenceExDates
+	}
+	if has(EventFieldVenueID) {
+		if e.VenueID == nil || params.VenueID == nil || *e.VenueID != *params.VenueID {
+			e.Venue = nil // Площадка сменилась, загруженные данные устарели
+		}
+		e.VenueID = params.VenueID
+	}
+	if has(EventFieldOrganizerID) {
+		if e.OrganizerID == nil || params.OrganizerID == nil || *e.OrganizerID != *params.OrganizerID {
+			e.Organizer = nil // Организатор сменился, загруженные данные устарели
+		}
+		e.OrganizerID = params.OrganizerID
+	}
+	if has(EventFieldTags) {
+		e.Tags = NormalizeTags(params.Tags)
+	}
+	if has(EventFieldTicketTiers) {
+		e.TicketTiers = NormalizeTicketTiers(params.TicketTiers)
+	}
+	if has(EventFieldTranslations) {
+		e.Translations = NormalizeTranslations(params.Translations)
+	}
+	e.normalizeSchedule()
+	e.normalizePricing()
 	// ID и CreatedAt не должны меняться здесь.
 	// UpdatedAt будет обновлен базой данных или методом хранилища.
+	return nil
+}
+
+// Reschedule переносит событие на новое начало. Без endsAt длительность сохраняется.
+func (e *Event) Reschedule(startsAt time.Time, endsAt *time.Time) {
+	if endsAt == nil && e.StartsAt != nil && e.EndsAt != nil {
+		end := startsAt.Add(e.EndsAt.Sub(*e.StartsAt))
+		endsAt = &end
+	}
+
+	e.StartsAt = &startsAt
+	e.EndsAt = endsAt
+	e.normalizeSchedule()
+}
+
+// TimeLocation возвращает часовой пояс события.
+// Если зона не указана или неизвестна, используется DefaultTimezone.
+func (e *Event) TimeLocation() *time.Location {
+	return LoadLocation(e.Timezone)
+}
+
+// normalizeSchedule заполняет часовой пояс по умолчанию и синхронизирует
+// устаревшие поля Date/Time со StartsAt.
+// Если StartsAt не задан, пытается вычислить его из Date/Time (старые клиенты).
+func (e *Event) normalizeSchedule() {
+	if e.Timezone == "" {
+		e.Timezone = DefaultTimezone
+	}
+
+	loc := e.TimeLocation()
+
+	if e.StartsAt == nil {
+		e.StartsAt = ParseLegacyDateTime(e.Date, e.Time, loc)
+		return
+	}
+
+	local := e.StartsAt.In(loc)
+	e.Date = local.Format(LegacyDateLayout)
+	e.Time = local.Format(LegacyTimeLayout)
+}
+
+// ParseLegacyDateTime собирает момент времени из строковых date (YYYY-MM-DD) и time (HH:MM).
+// Возвращает nil, если дата не распознана. Нераспознанное время трактуется как полночь.
+func ParseLegacyDateTime(date, clock string, loc *time.Location) *time.Time {
+	day, err := time.ParseInLocation(LegacyDateLayout, date, loc)
+	if err != nil {
+		return nil
+	}
+
+	if parsed, err := time.Parse(LegacyTimeLayout, clock); err == nil {
+		day = time.Date(day.Year(), day.Month(), day.Day(), parsed.Hour(), parsed.Minute(), 0, 0, loc)
+	}
+
+	return &day
+}
+
+// LoadLocation загружает часовой пояс по IANA имени.
+// Пустое или неизвестное имя заменяется на DefaultTimezone.
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		name = DefaultTimezone
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc, err = time.LoadLocation(DefaultTimezone)
+		if err != nil {
+			return time.UTC
+		}
+	}
+
+	return loc
 }
 
 // NewCategory создает новую категорию из запроса
 func NewCategory(req *CreateCategoryReq) *Category {
 	return &Category{
 		Name:      req.Name,
+		ParentID:  req.ParentID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 }
+
+// NewVenue создает новую площадку из запроса
+func NewVenue(req *CreateVenueReq) *Venue {
+	return &Venue{
+		Name:      strings.TrimSpace(req.Name),
+		Address:   strings.TrimSpace(req.Address),
+		City:      strings.TrimSpace(req.City),
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NewOrganizer создает нового организатора из запроса
+func NewOrganizer(req *CreateOrganizerReq) *Organizer {
+	return &Organizer{
+		Name:       strings.Join(strings.Fields(req.Name), " "),
+		Email:      strings.TrimSpace(req.Email),
+		Phone:      strings.TrimSpace(req.Phone),
+		Website:    strings.TrimSpace(req.Website),
+		IsVerified: req.IsVerified,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// NewSource создает новый активный источник из запроса
+func NewSource(req *CreateSourceReq) *Source {
+	return &Source{
+		Slug:        NormalizeSourceSlug(req.Slug),
+		DisplayName: strings.TrimSpace(req.DisplayName),
+		TrustLevel:  req.TrustLevel,
+		IsActive:    true,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// NormalizeSourceSlug приводит slug источника к нижнему регистру без пробелов по краям.
+func NormalizeSourceSlug(slug string) string {
+	return strings.ToLower(strings.TrimSpace(slug))
+}
+
+// HasCoordinates сообщает, заданы ли координаты площадки.
+func (v *Venue) HasCoordinates() bool {
+	return v.Latitude != nil && v.Longitude != nil
+}
+
+// NormalizedName возвращает имя площадки для поиска дубликатов.
+func (v *Venue) NormalizedName() string {
+	return NormalizeVenueName(v.Name)
+}
+
+// NormalizeVenueName приводит имя к нижнему регистру и схлопывает пробелы.
+// Должна совпадать с нормализацией в миграции adding_venues.
+func NormalizeVenueName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// BuildCategoryTree собирает плоский список категорий в дерево и возвращает корни.
+// Категории, родитель которых отсутствует в списке, считаются корнями.
+func BuildCategoryTree(categories []*Category) []*Category {
+	byID := make(map[int]*Category, len(categories))
+	for _, category := range categories {
+		category.Children = nil
+		byID[category.Id] = category
+	}
+
+	roots := make([]*Category, 0)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			if parent, ok := byID[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+
+	return roots
+}
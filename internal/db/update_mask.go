@@ -0,0 +1,143 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Поля события, которые можно перечислить в маске частичного обновления
+const (
+	EventFieldName              = "name"
+	EventFieldDescription       = "description"
+	EventFieldCategoryID        = "category_id"
+	EventFieldDate              = "date"
+	EventFieldTime              = "time"
+	EventFieldLocation          = "location"
+	EventFieldPrice             = "price"
+	EventFieldImage             = "image"
+	EventFieldSource            = "source"
+	EventFieldStartsAt          = "starts_at"
+	EventFieldEndsAt            = "ends_at"
+	EventFieldTimezone          = "timezone"
+	EventFieldRecurrenceRule    = "recurrence_rule"
+	EventFieldRecurrenceExDates = "recurrence_exdates"
+	EventFieldVenueID           = "venue_id"
+	EventFieldOrganizerID       = "organizer_id"
+	EventFieldTags              = "tags"
+	EventFieldTicketTiers       = "ticket_tiers"
+	EventFieldTranslations      = "translations"
+)
+
+// Поля категории, которые можно перечислить в маске частичного обновления
+const (
+	CategoryFieldName     = "name"
+	CategoryFieldParentID = "parent_id"
+)
+
+var (
+	// Расписание и устаревшие date/time вычисляются друг из друга, а от них зависит окончание серии
+	scheduleColumns   = []string{"date", "time", "starts_at", "ends_at", "timezone", "recurrence_until"}
+	recurrenceColumns = []string{"recurrence_rule", "recurrence_exdates", "recurrence_until"}
+	// Цена и диапазон цен вычисляются по билетным категориям
+	pricingColumns = []string{"price", "price_from", "price_to", "currency"}
+)
+
+// eventFieldColumns колонки events, которые записываются при изменении поля.
+// Теги и переводы хранятся в отдельных таблицах, поэтому колонок у них нет.
+var eventFieldColumns = map[string][]string{
+	EventFieldName:              {"name"},
+	EventFieldDescription:       {"description"},
+	EventFieldCategoryID:        {"category_id"},
+	EventFieldDate:              scheduleColumns,
+	EventFieldTime:              scheduleColumns,
+	EventFieldLocation:          {"location"},
+	EventFieldPrice:             pricingColumns,
+	EventFieldImage:             {"image"},
+	EventFieldSource:            {"source"},
+	EventFieldStartsAt:          scheduleColumns,
+	EventFieldEndsAt:            scheduleColumns,
+	EventFieldTimezone:          scheduleColumns,
+	EventFieldRecurrenceRule:    recurrenceColumns,
+	EventFieldRecurrenceExDates: recurrenceColumns,
+	EventFieldVenueID:           {"venue_id"},
+	EventFieldOrganizerID:       {"organizer_id"},
+	EventFieldTags:              {},
+	EventFieldTicketTiers:       pricingColumns,
+	EventFieldTranslations:      {},
+}
+
+// eventUpdateColumn колонка UPDATE events: SQL выражение с плейсхолдером %d и значение из события
+type eventUpdateColumn struct {
+	name  string
+	expr  string
+	value func(e *Event) any
+}
+
+// eventUpdateColumns все изменяемые через UpdateEvent колонки в порядке записи
+var eventUpdateColumns = []eventUpdateColumn{
+	{"name", "name = $%d", func(e *Event) any { return e.Name }},
+	{"description", "description = $%d", func(e *Event) any { return e.Description }},
+	{"category_id", "category_id = $%d", func(e *Event) any { return e.CategoryID }},
+	{"date", "date = $%d", func(e *Event) any { return e.Date }},
+	{"time", "time = $%d", func(e *Event) any { return e.Time }},
+	{"location", "location = $%d", func(e *Event) any { return e.Location }},
+	{"price", "price = $%d", func(e *Event) any { return e.Price }},
+	{"image", "image = $%d", func(e *Event) any { return e.Image }},
+	{"source", "source = $%d", func(e *Event) any { return e.Source }},
+	{"starts_at", "starts_at = $%d", func(e *Event) any { return e.StartsAt }},
+	{"ends_at", "ends_at = $%d", func(e *Event) any { return e.EndsAt }},
+	{"timezone", "timezone = $%d", func(e *Event) any { return e.Timezone }},
+	{"recurrence_rule", "recurrence_rule = NULLIF($%d, '')", func(e *Event) any { return e.RecurrenceRule }},
+	{"recurrence_exdates", "recurrence_exdates = $%d", func(e *Event) any { return e.RecurrenceExDates }},
+	{"recurrence_until", "recurrence_until = $%d", func(e *Event) any { return e.RecurrenceUntil }},
+	{"venue_id", "venue_id = $%d", func(e *Event) any { return e.VenueID }},
+	{"organizer_id", "organizer_id = $%d", func(e *Event) any { return e.OrganizerID }},
+	{"price_from", "price_from = $%d", func(e *Event) any { return e.PriceFrom }},
+	{"price_to", "price_to = $%d", func(e *Event) any { return e.PriceTo }},
+	{"currency", "currency = $%d", func(e *Event) any { return e.Currency }},
+}
+
+// IsUpdatableEventField сообщает, можно ли указать поле в маске обновления события.
+func IsUpdatableEventField(field string) bool {
+	_, ok := eventFieldColumns[field]
+	return ok
+}
+
+// IsUpdatableCategoryField сообщает, можно ли указать поле в маске обновления категории.
+func IsUpdatableCategoryField(field string) bool {
+	return field == CategoryFieldName || field == CategoryFieldParentID
+}
+
+// InMask сообщает, затрагивает ли обновление поле. Пустая маска означает все поля.
+func InMask(fields []string, field string) bool {
+	return len(fields) == 0 || slices.Contains(fields, field)
+}
+
+// buildUpdateEventQuery строит UPDATE events только для колонок полей из маски.
+// Пустая маска обновляет все колонки. Версия и updated_at меняются всегда.
+func buildUpdateEventQuery(event *Event, fields []string) (string, []any) {
+	columns := make(map[string]bool)
+	for _, field := range fields {
+		for _, column := range eventFieldColumns[field] {
+			columns[column] = true
+		}
+	}
+
+	var sets []string
+	var args []any
+	for _, column := range eventUpdateColumns {
+		if len(fields) > 0 && !columns[column.name] {
+			continue
+		}
+		args = append(args, column.value(event))
+		sets = append(sets, fmt.Sprintf(column.expr, len(args)))
+	}
+	sets = append(sets, "version = version + 1", "updated_at = NOW()")
+
+	args = append(args, event.Id)
+	query := fmt.Sprintf(`UPDATE events SET %s WHERE id = $%d AND deleted_at IS NULL RETURNING updated_at`,
+		strings.Join(sets, ", "), len(args))
+
+	return query, args
+}
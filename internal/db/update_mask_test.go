@@ -0,0 +1,182 @@
+package db
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBuildUpdateEventQuery(t *testing.T) {
+	event := &Event{Id: 7, Name: "Concert", RecurrenceRule: "FREQ=WEEKLY"}
+
+	tests := []struct {
+		name     string
+		fields   []string
+		wantSets string
+		wantArgs int
+	}{
+		{
+			name:     "single column",
+			fields:   []string{EventFieldName},
+			wantSets: "name = $1",
+			wantArgs: 2,
+		},
+		{
+			name:     "schedule field writes legacy date/time and series end",
+			fields:   []string{EventFieldStartsAt},
+			wantSets: "date = $1, time = $2, starts_at = $3, ends_at = $4, timezone = $5, recurrence_until = $6",
+			wantArgs: 7,
+		},
+		{
+			name:     "recurrence rule is nulled when empty",
+			fields:   []string{EventFieldRecurrenceRule},
+			wantSets: "recurrence_rule = NULLIF($1, ''), recurrence_exdates = $2, recurrence_until = $3",
+			wantArgs: 4,
+		},
+		{
+			name:     "ticket tiers recompute prices",
+			fields:   []string{EventFieldTicketTiers},
+			wantSets: "price = $1, price_from = $2, price_to = $3, currency = $4",
+			wantArgs: 5,
+		},
+		{
+			name:     "overlapping fields write each column once in table order",
+			fields:   []string{EventFieldRecurrenceExDates, EventFieldTimezone, EventFieldName},
+			wantSets: "name = $1, date = $2, time = $3, starts_at = $4, ends_at = $5, timezone = $6, recurrence_rule = NULLIF($7, ''), recurrence_exdates = $8, recurrence_until = $9",
+			wantArgs: 10,
+		},
+		{
+			name:     "fields stored outside events only bump the version",
+			fields:   []string{EventFieldTags, EventFieldTranslations},
+			wantSets: "",
+			wantArgs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := buildUpdateEventQuery(event, tt.fields)
+
+			sets := "version = version + 1, updated_at = NOW()"
+			if tt.wantSets != "" {
+				sets = tt.wantSets + ", " + sets
+			}
+			want := "UPDATE events SET " + sets + " WHERE id = $" + strconv.Itoa(tt.wantArgs) + " AND deleted_at IS NULL RETURNING updated_at"
+			if query != want {
+				t.Errorf("query:\n got %s\nwant %s", query, want)
+			}
+			if len(args) != tt.wantArgs {
+				t.Fatalf("got %d args, want %d", len(args), tt.wantArgs)
+			}
+			if id, ok := args[len(args)-1].(int64); !ok || id != event.Id {
+				t.Errorf("last arg = %v, want event id %d", args[len(args)-1], event.Id)
+			}
+		})
+	}
+}
+
+func TestBuildUpdateEventQueryEmptyMaskWritesAllColumns(t *testing.T) {
+	query, args := buildUpdateEventQuery(&Event{Id: 1}, nil)
+
+	if len(args) != len(eventUpdateColumns)+1 {
+		t.Fatalf("got %d args, want %d", len(args), len(eventUpdateColumns)+1)
+	}
+	for _, column := range eventUpdateColumns {
+		if !strings.Contains(query, column.name+" = ") {
+			t.Errorf("query does not set %s: %s", column.name, query)
+		}
+	}
+}
+
+func TestEventFieldColumnsAreUpdatable(t *testing.T) {
+	known := make(map[string]bool, len(eventUpdateColumns))
+	for _, column := range eventUpdateColumns {
+		known[column.name] = true
+	}
+
+	reachable := make(map[string]bool, len(eventUpdateColumns))
+	for field, columns := range eventFieldColumns {
+		if !IsUpdatableEventField(field) {
+			t.Errorf("IsUpdatableEventField(%q) = false", field)
+		}
+		for _, column := range columns {
+			if !known[column] {
+				t.Errorf("field %q maps to unknown column %q", field, column)
+			}
+			reachable[column] = true
+		}
+	}
+
+	// Колонка, не попадающая ни в одно поле маски, не обновится частичным обновлением
+	for _, column := range eventUpdateColumns {
+		if !reachable[column.name] {
+			t.Errorf("column %q is not reachable from any mask field", column.name)
+		}
+	}
+
+	if IsUpdatableEventField("version") {
+		t.Error(`IsUpdatableEventField("version") = true`)
+	}
+}
+
+func TestApplyUpdatePriceWithTicketTiers(t *testing.T) {
+	tiers := []TicketTier{{Name: "Standard", MinPrice: 1500}}
+
+	tests := []struct {
+		name    string
+		tiers   []TicketTier
+		params  UpdateEventParams
+		wantErr bool
+		want    float32
+	}{
+		{
+			name:   "price of an event without tiers",
+			params: UpdateEventParams{Fields: []string{EventFieldPrice}, Price: 700},
+			want:   700,
+		},
+		{
+			name:    "price of an event with tiers",
+			tiers:   tiers,
+			params:  UpdateEventParams{Fields: []string{EventFieldPrice}, Price: 700},
+			wantErr: true,
+			want:    1500,
+		},
+		{
+			name:    "price together with new tiers",
+			params:  UpdateEventParams{Fields: []string{EventFieldPrice, EventFieldTicketTiers}, Price: 700, TicketTiers: tiers},
+			wantErr: true,
+			want:    300,
+		},
+		{
+			name:   "price together with removed tiers",
+			tiers:  tiers,
+			params: UpdateEventParams{Fields: []string{EventFieldPrice, EventFieldTicketTiers}, Price: 700},
+			want:   700,
+		},
+		{
+			name:   "full update recomputes the price from tiers",
+			tiers:  tiers,
+			params: UpdateEventParams{Price: 700, TicketTiers: tiers},
+			want:   1500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price := float32(300)
+			if len(tt.tiers) > 0 {
+				price = tt.tiers[0].MinPrice
+			}
+			event := &Event{Price: price, TicketTiers: tt.tiers}
+
+			err := event.ApplyUpdate(tt.params)
+			if errors.Is(err, ErrPriceDerived) != tt.wantErr {
+				t.Fatalf("ApplyUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if event.Price != tt.want {
+				t.Errorf("Price = %v, want %v", event.Price, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	venueColumns = `id, name, address, city, latitude, longitude, created_at, updated_at`
+
+	createVenueQuery = `INSERT INTO venues (name, normalized_name, address, city, latitude, longitude)
+						VALUES ($1, $2, $3, $4, $5, $6)
+						RETURNING id, created_at, updated_at`
+
+	updateVenueQuery = `UPDATE venues
+						SET name = $1, normalized_name = $2, address = $3, city = $4,
+						    latitude = $5, longitude = $6, updated_at = NOW()
+						WHERE id = $7
+						RETURNING updated_at`
+
+	getVenueByIdQuery     = `SELECT ` + venueColumns + ` FROM venues WHERE id = $1`
+	listVenuesQuery       = `SELECT ` + venueColumns + ` FROM venues ORDER BY name`
+	listVenuesByCityQuery = `SELECT ` + venueColumns + ` FROM venues WHERE city = $1 ORDER BY name`
+	deleteVenueQuery      = `DELETE FROM venues WHERE id = $1`
+	getEventsByVenueQuery = getEventsQuery + ` AND events.venue_id = $1`
+)
+
+// CreateVenue создает новую площадку.
+// Площадка с тем же нормализованным именем в том же городе нарушает уникальность.
+func (s *PostgresStore) CreateVenue(parentCtx context.Context, venue *Venue) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	err := s.db.QueryRow(
+		ctx,
+		createVenueQuery,
+		venue.Name,
+		venue.NormalizedName(),
+		venue.Address,
+		venue.City,
+		venue.Latitude,
+		venue.Longitude,
+	).Scan(&venue.Id, &venue.CreatedAt, &venue.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create venue: %w", err)
+	}
+
+	return nil
+}
+
+// GetVenueByID получает площадку по ID.
+func (s *PostgresStore) GetVenueByID(parentCtx context.Context, id int64) (*Venue, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	venue, err := scanVenue(s.db.QueryRow(ctx, getVenueByIdQuery, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("venue %d not found: %w", id, err)
+		}
+		return nil, fmt.Errorf("failed to get venue by id %d: %w", id, err)
+	}
+
+	return venue, nil
+}
+
+// ListVenues возвращает площадки, отсортированные по имени.
+// Непустой city ограничивает выборку одним городом.
+func (s *PostgresStore) ListVenues(parentCtx context.Context, city string) ([]*Venue, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var rows pgx.Rows
+	var err error
+	if city != "" {
+		rows, err = s.db.Query(ctx, listVenuesByCityQuery, city)
+	} else {
+		rows, err = s.db.Query(ctx, listVenuesQuery)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query venues: %w", err)
+	}
+	defer rows.Close()
+
+	venues := []*Venue{}
+	for rows.Next() {
+		venue, err := scanVenue(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan venue: %w", err)
+		}
+		venues = append(venues, venue)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating venue rows: %w", err)
+	}
+
+	return venues, nil
+}
+
+// UpdateVenue обновляет существующую площадку.
+func (s *PostgresStore) UpdateVenue(parentCtx context.Context, venue *Venue) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	var updatedAt time.Time
+	err := s.db.QueryRow(
+		ctx,
+		updateVenueQuery,
+		venue.Name,
+		venue.NormalizedName(),
+		venue.Address,
+		venue.City,
+		venue.Latitude,
+		venue.Longitude,
+		venue.Id,
+	).Scan(&updatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("venue with ID %d not found for update: %w", venue.Id, err)
+		}
+		return fmt.Errorf("failed to update venue %d: %w", venue.Id, err)
+	}
+
+	venue.UpdatedAt = &updatedAt
+
+	return nil
+}
+
+// DeleteVenue удаляет площадку. У событий площадки venue_id сбрасывается в NULL.
+func (s *PostgresStore) DeleteVenue(parentCtx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	cmdTag, err := s.db.Exec(ctx, deleteVenueQuery, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete venue %d: %w", id, err)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("venue with ID %d not found for deletion: %w", id, pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+// GetEventsByVenue извлекает события, проходящие на площадке.
+func (s *PostgresStore) GetEventsByVenue(parentCtx context.Context, venueID int64) ([]*Event, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, getEventsByVenueQuery, venueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events by venue %d: %w", venueID, err)
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan event row for venue %d: %w", venueID, err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event rows for venue %d: %w", venueID, err)
+	}
+
+	return events, nil
+}
+
+// scanVenue сканирует одну строку venues в структуру Venue.
+func scanVenue(scanner pgxScanner) (*Venue, error) {
+	venue := new(Venue)
+
+	err := scanner.Scan(
+		&venue.Id,
+		&venue.Name,
+		&venue.Address,
+		&venue.City,
+		&venue.Latitude,
+		&venue.Longitude,
+		&venue.CreatedAt,
+		&venue.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return venue, nil
+}
@@ -0,0 +1,184 @@
+package dedup
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rx3lixir/event-service/internal/db"
+)
+
+// DefaultMinScore порог, начиная с которого пара считается кандидатом в дубликаты
+const DefaultMinScore = 0.7
+
+// Веса признаков в итоговой оценке, в сумме 1
+const (
+	nameWeight  = 0.5
+	startWeight = 0.3
+	venueWeight = 0.2
+)
+
+// StartWindow максимальная разница начала событий-дубликатов.
+// Оценка по времени линейно падает от 1 при совпадении до 0 на границе окна
+const StartWindow = 12 * time.Hour
+
+// Причины, по которым пара считается дубликатом
+const (
+	ReasonSameName     = "same_name"
+	ReasonSimilarName  = "similar_name"
+	ReasonSameStart    = "same_start"
+	ReasonCloseStart   = "close_start"
+	ReasonSameVenue    = "same_venue"
+	ReasonSameLocation = "same_location"
+)
+
+// Candidate пара событий, похожих на дубликаты, с оценкой от 0 до 1
+type Candidate struct {
+	Event     *db.Event
+	Duplicate *db.Event
+	Score     float64
+	Reasons   []string
+}
+
+// Score оценивает, насколько события a и b похожи на одно событие из разных источников.
+// Детерминированные правила:
+//   - события одного источника с разными внешними ID не дубликаты: источник сам различает свои события;
+//   - события, начала которых отличаются больше чем на StartWindow, не дубликаты.
+//
+// Иначе оценка складывается из похожести названия, близости начала и совпадения площадки.
+func Score(a, b *db.Event) (float64, []string) {
+	if a.Id == b.Id {
+		return 0, nil
+	}
+
+	if a.SourceID != nil && b.SourceID != nil && *a.SourceID == *b.SourceID &&
+		a.ExternalID != "" && b.ExternalID != "" && a.ExternalID != b.ExternalID {
+		return 0, nil
+	}
+
+	var reasons []string
+
+	nameScore := jaccard(tokens(a.Name), tokens(b.Name))
+	switch {
+	case nameScore == 1:
+		reasons = append(reasons, ReasonSameName)
+	case nameScore >= 0.5:
+		reasons = append(reasons, ReasonSimilarName)
+	}
+
+	startScore := 0.0
+	if a.StartsAt != nil && b.StartsAt != nil {
+		diff := a.StartsAt.Sub(*b.StartsAt).Abs()
+		if diff > StartWindow {
+			return 0, nil
+		}
+		startScore = 1 - float64(diff)/float64(StartWindow)
+		if diff == 0 {
+			reasons = append(reasons, ReasonSameStart)
+		} else {
+			reasons = append(reasons, ReasonCloseStart)
+		}
+	}
+
+	venueScore := 0.0
+	switch {
+	case a.VenueID != nil && b.VenueID != nil && *a.VenueID == *b.VenueID:
+		venueScore = 1
+		reasons = append(reasons, ReasonSameVenue)
+	default:
+		venueScore = jaccard(tokens(a.Location), tokens(b.Location))
+		if venueScore == 1 {
+			reasons = append(reasons, ReasonSameLocation)
+		}
+	}
+
+	score := nameWeight*nameScore + startWeight*startScore + venueWeight*venueScore
+	return math.Round(score*1000) / 1000, reasons
+}
+
+// FindCandidates оценивает event против каждого из others и возвращает пары
+// с оценкой не ниже minScore, от самых похожих.
+func FindCandidates(event *db.Event, others []*db.Event, minScore float64) []Candidate {
+	var candidates []Candidate
+	for _, other := range others {
+		if score, reasons := Score(event, other); score >= minScore && score > 0 {
+			candidates = append(candidates, Candidate{Event: event, Duplicate: other, Score: score, Reasons: reasons})
+		}
+	}
+
+	SortCandidates(candidates)
+	return candidates
+}
+
+// FindPairs оценивает все пары событий и возвращает пары с оценкой не ниже minScore,
+// от самых похожих. В паре первым идет событие с меньшим ID.
+func FindPairs(events []*db.Event, minScore float64) []Candidate {
+	sorted := make([]*db.Event, len(events))
+	copy(sorted, events)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Id < sorted[j].Id })
+
+	var candidates []Candidate
+	for i, event := range sorted {
+		for _, other := range sorted[i+1:] {
+			if score, reasons := Score(event, other); score >= minScore && score > 0 {
+				candidates = append(candidates, Candidate{Event: event, Duplicate: other, Score: score, Reasons: reasons})
+			}
+		}
+	}
+
+	SortCandidates(candidates)
+	return candidates
+}
+
+// SortCandidates сортирует пары по убыванию оценки, при равенстве - по ID событий.
+func SortCandidates(candidates []Candidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Event.Id != b.Event.Id {
+			return a.Event.Id < b.Event.Id
+		}
+		return a.Duplicate.Id < b.Duplicate.Id
+	})
+}
+
+// tokens разбивает строку на слова в нижнем регистре без пунктуации, "ё" заменяется на "е"
+func tokens(s string) []string {
+	s = strings.ReplaceAll(strings.ToLower(s), "ё", "е")
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// jaccard доля общих слов среди всех слов двух строк. Для двух пустых строк 0
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(a))
+	for _, token := range a {
+		set[token] = true
+	}
+
+	union := len(set)
+	common := 0
+	seen := make(map[string]bool, len(b))
+	for _, token := range b {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		if set[token] {
+			common++
+		} else {
+			union++
+		}
+	}
+
+	return float64(common) / float64(union)
+}
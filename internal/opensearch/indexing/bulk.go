@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"strconv"
 	"strings"
 
@@ -52,18 +53,37 @@ func (b *BulkOperations) BulkIndex(ctx context.Context, docs []*models.EventDocu
 	return nil
 }
 
-func (b *BulkOperations) processBatch(ctx context.Context, docs []*models.EventDocument) error {
-	return b.retryLogic.ExecuteWithRetry(ctx, func(ctx context.Context) error {
-		return b.executeBulkRequest(ctx, docs)
-	})
+// BulkDelete удаляет документы событий пачками. Отсутствующие документы ошибкой не считаются.
+func (b *BulkOperations) BulkDelete(ctx context.Context, eventIDs []int64) error {
+	const maxBatchSize = 100
+
+	for i := 0; i < len(eventIDs); i += maxBatchSize {
+		end := min(i+maxBatchSize, len(eventIDs))
+
+		body := b.buildDeleteBody(eventIDs[i:end])
+		err := b.retryLogic.ExecuteWithRetry(ctx, func(ctx context.Context) error {
+			return b.executeBulkRequest(ctx, body, end-i)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to process delete batch %d-%d: %w", i, end-1, err)
+		}
+	}
+
+	return nil
 }
 
-func (b *BulkOperations) executeBulkRequest(ctx context.Context, docs []*models.EventDocument) error {
+func (b *BulkOperations) processBatch(ctx context.Context, docs []*models.EventDocument) error {
 	body, err := b.buildBulkBody(docs)
 	if err != nil {
 		return fmt.Errorf("failed to build bulk body: %w", err)
 	}
 
+	return b.retryLogic.ExecuteWithRetry(ctx, func(ctx context.Context) error {
+		return b.executeBulkRequest(ctx, body, len(docs))
+	})
+}
+
+func (b *BulkOperations) executeBulkRequest(ctx context.Context, body string, expectedCount int) error {
 	res, err := b.client.GetNativeClient().Bulk(
 		strings.NewReader(body),
 		b.client.GetNativeClient().Bulk.WithContext(ctx),
@@ -79,7 +99,7 @@ func (b *BulkOperations) executeBulkRequest(ctx context.Context, docs []*models.
 	}
 
 	// Проверяем ответ на ошибки в отдельных операциях
-	if err := b.checkBulkResponse(res.Body, len(docs)); err != nil {
+	if err := b.checkBulkResponse(res.Body, expectedCount); err != nil {
 		return fmt.Errorf("bulk response contains errors: %w", err)
 	}
 
@@ -91,12 +111,15 @@ func (b *BulkOperations) buildBulkBody(docs []*models.EventDocument) (string, er
 
 	for _, doc := range docs {
 		// Action line
-		actionLine := map[string]any{
-			"index": map[string]any{
-				"_index": b.client.GetIndexName(),
-				"_id":    strconv.FormatInt(doc.ID, 10),
-			},
+		action := map[string]any{
+			"_index": b.client.GetIndexName(),
+			"_id":    strconv.FormatInt(doc.ID, 10),
+		}
+		if doc.Version > 0 {
+			action["version"] = doc.Version
+			action["version_type"] = versionTypeExternalGTE
 		}
+		actionLine := map[string]any{"index": action}
 
 		actionBytes, err := json.Marshal(actionLine)
 		if err != nil {
@@ -120,17 +143,36 @@ func (b *BulkOperations) buildBulkBody(docs []*models.EventDocument) (string, er
 	return buf.String(), nil
 }
 
+// buildDeleteBody формирует тело bulk-запроса на удаление документов
+func (b *BulkOperations) buildDeleteBody(eventIDs []int64) string {
+	var buf bytes.Buffer
+
+	for _, id := range eventIDs {
+		// Ошибка невозможна: в строке действия только строки
+		actionBytes, _ := json.Marshal(map[string]any{
+			"delete": map[string]any{
+				"_index": b.client.GetIndexName(),
+				"_id":    strconv.FormatInt(id, 10),
+			},
+		})
+
+		buf.Write(actionBytes)
+		buf.WriteByte('\n')
+	}
+
+	return buf.String()
+}
+
 func (b *BulkOperations) checkBulkResponse(body io.Reader, expectedCount int) error {
+	// Каждый элемент ответа - объект с единственным ключом действия: index или delete
 	var response struct {
 		Errors bool `json:"errors"`
-		Items  []struct {
-			Index struct {
-				Status int `json:"status"`
-				Error  *struct {
-					Type   string `json:"type"`
-					Reason string `json:"reason"`
-				} `json:"error,omitempty"`
-			} `json:"index"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error,omitempty"`
 		} `json:"items"`
 	}
 
@@ -147,16 +189,33 @@ func (b *BulkOperations) checkBulkResponse(body io.Reader, expectedCount int) er
 	// Собираем ошибки
 	var errors []string
 	successCount := 0
+	staleCount := 0
 
 	for i, item := range response.Items {
-		if item.Index.Error != nil {
-			errors = append(errors, fmt.Sprintf("item %d: %s - %s",
-				i, item.Index.Error.Type, item.Index.Error.Reason))
-		} else if item.Index.Status >= 200 && item.Index.Status < 300 {
-			successCount++
+		for action, result := range item {
+			switch {
+			case result.Status == http.StatusConflict:
+				// В индексе уже более новая версия документа, запоздавшая запись пропущена
+				staleCount++
+			case action == "delete" && result.Status == http.StatusNotFound:
+				// Документа уже нет в индексе
+				successCount++
+			case result.Error != nil:
+				errors = append(errors, fmt.Sprintf("item %d: %s - %s",
+					i, result.Error.Type, result.Error.Reason))
+			case result.Status >= 200 && result.Status < 300:
+				successCount++
+			}
 		}
 	}
 
+	if len(errors) == 0 {
+		b.logger.Debug("Bulk operation completed, stale versions skipped",
+			"successful", successCount,
+			"stale", staleCount)
+		return nil
+	}
+
 	b.logger.Warn("Bulk operation completed with errors",
 		"total_operations", len(response.Items),
 		"successful", successCount,
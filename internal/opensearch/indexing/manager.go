@@ -5,14 +5,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
 
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+
 	"github.com/rx3lixir/event-service/internal/db"
 	"github.com/rx3lixir/event-service/internal/opensearch/client"
 	"github.com/rx3lixir/event-service/internal/opensearch/models"
 	"github.com/rx3lixir/event-service/pkg/logger"
 )
 
+// versionTypeExternalGTE внешняя версия документа: запись с версией ниже текущей отклоняется (409),
+// с равной принимается, чтобы повторная переиндексация того же состояния была идемпотентной
+const versionTypeExternalGTE = "external_gte"
+
 type Manager struct {
 	client     *client.Client
 	bulkOps    *BulkOperations
@@ -42,8 +49,10 @@ func (m *Manager) IndexEvent(ctx context.Context, event *db.Event) error {
 	})
 }
 
+// UpdateEvent переиндексирует документ события целиком с внешней версией.
+// Частичный _update не используется: он увеличивает внутренний _version индекса,
+// и следующая запись с external_gte отклонялась бы как устаревшая.
 func (m *Manager) UpdateEvent(ctx context.Context, event *db.Event) error {
-	// Пока что полное индексирование
 	return m.IndexEvent(ctx, event)
 }
 
@@ -70,6 +79,15 @@ func (m *Manager) BulkIndexEvents(ctx context.Context, events []*db.Event) error
 	return m.bulkOps.BulkIndex(ctx, docs)
 }
 
+// BulkDeleteEvents удаляет документы событий одним bulk-запросом на пачку.
+func (m *Manager) BulkDeleteEvents(ctx context.Context, eventIDs []int64) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	return m.bulkOps.BulkDelete(ctx, eventIDs)
+}
+
 func (m *Manager) indexSingleEvent(ctx context.Context, doc *models.EventDocument) error {
 	docData := doc.PrepareForIndex()
 
@@ -78,18 +96,34 @@ func (m *Manager) indexSingleEvent(ctx context.Context, doc *models.EventDocumen
 		return fmt.Errorf("failed to marshal document: %w", err)
 	}
 
-	res, err := m.client.GetNativeClient().Index(
-		m.client.GetIndexName(),
-		bytes.NewReader(body),
-		m.client.GetNativeClient().Index.WithDocumentID(strconv.FormatInt(doc.ID, 10)),
-		m.client.GetNativeClient().Index.WithContext(ctx),
-		m.client.GetNativeClient().Index.WithRefresh("true"),
-	)
+	native := m.client.GetNativeClient()
+	opts := []func(*opensearchapi.IndexRequest){
+		native.Index.WithDocumentID(strconv.FormatInt(doc.ID, 10)),
+		native.Index.WithContext(ctx),
+		native.Index.WithRefresh("true"),
+	}
+	if doc.Version > 0 {
+		opts = append(opts,
+			native.Index.WithVersion(int(doc.Version)),
+			native.Index.WithVersionType(versionTypeExternalGTE),
+		)
+	}
+
+	res, err := native.Index(m.client.GetIndexName(), bytes.NewReader(body), opts...)
 	if err != nil {
 		return fmt.Errorf("failed to index document: %w", err)
 	}
 	defer res.Body.Close()
 
+	// В индексе уже более новая версия: запоздавшая запись не должна ее откатить
+	if res.StatusCode == http.StatusConflict {
+		m.logger.Debug("Stale document version skipped",
+			"event_id", doc.ID,
+			"version", doc.Version,
+		)
+		return nil
+	}
+
 	if res.IsError() {
 		return fmt.Errorf("indexing failed with status: %s", res.Status())
 	}
@@ -69,6 +69,11 @@ func (m *Manager) validateAndRecreateIfNeeded(ctx context.Context, indexName str
 	return m.recreateIndex(ctx, indexName)
 }
 
+// RecreateIndex удаляет индекс и создает его заново с маппингом из events.json
+func (m *Manager) RecreateIndex(ctx context.Context) error {
+	return m.recreateIndex(ctx, m.client.GetIndexName())
+}
+
 func (m *Manager) recreateIndex(ctx context.Context, indexName string) error {
 	m.logger.Info("Recreating OpenSearch index", "index", indexName)
 
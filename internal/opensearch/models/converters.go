@@ -10,7 +10,7 @@ func FromDBEvent(event *db.Event) *EventDocument {
 		return nil
 	}
 
-	return &EventDocument{
+	doc := &EventDocument{
 		ID:          event.Id,
 		Name:        event.Name,
 		Description: event.Description,
@@ -21,9 +21,49 @@ func FromDBEvent(event *db.Event) *EventDocument {
 		Price:       event.Price,
 		Image:       event.Image,
 		Source:      event.Source,
+		StartsAt:    event.StartsAt,
+		EndsAt:      event.EndsAt,
+		Timezone:    event.Timezone,
 		CreatedAt:   event.CreatedAt,
 		UpdatedAt:   event.UpdatedAt,
+
+		RecurrenceRule:    event.RecurrenceRule,
+		RecurrenceExDates: event.RecurrenceExDates,
+		RecurrenceUntil:   event.RecurrenceUntil,
+		OccurrenceStart:   event.OccurrenceStart,
+		IsOverride:        event.IsOverride,
+		DistanceKm:        event.DistanceKm,
+		Tags:              event.Tags,
+		TagSlugs:          db.TagSlugs(event.Tags),
+		VenueID:           event.VenueID,
+		OrganizerID:       event.OrganizerID,
+		PriceFrom:         event.PriceFrom,
+		PriceTo:           event.PriceTo,
+		Currency:          event.Currency,
+		TicketTiers:       event.TicketTiers,
+		Status:            event.Status,
+		PublishAt:         event.PublishAt,
+		StatusReason:      event.StatusReason,
+		Version:           event.Version,
+		SourceID:          event.SourceID,
+		ExternalID:        event.ExternalID,
+		Translations:      event.Translations,
+		Images:            event.Images,
+	}
+
+	if venue := event.Venue; venue != nil {
+		doc.VenueName = venue.Name
+		doc.City = venue.City
+		if venue.HasCoordinates() {
+			doc.GeoLocation = &GeoPoint{Lat: *venue.Latitude, Lon: *venue.Longitude}
+		}
 	}
+
+	if organizer := event.Organizer; organizer != nil {
+		doc.OrganizerName = organizer.Name
+	}
+
+	return doc
 }
 
 // FromDBEvents конвертирует слайс db.Event в слайс EventDocument
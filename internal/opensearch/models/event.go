@@ -18,8 +18,65 @@ type EventDocument struct {
 	Price        float32    `json:"price"`
 	Image        string     `json:"image"`
 	Source       string     `json:"source"`
+	StartsAt     *time.Time `json:"starts_at,omitempty"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+	Timezone     string     `json:"timezone,omitempty"`
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    *time.Time `json:"updated_at"`
+
+	// Повторение: документ хранит серию, вхождения разворачиваются после поиска
+	RecurrenceRule    string      `json:"recurrence_rule,omitempty"`
+	RecurrenceExDates []time.Time `json:"recurrence_exdates,omitempty"`
+	RecurrenceUntil   *time.Time  `json:"recurrence_until,omitempty"`
+
+	// Теги: имена для поиска и подсказок, slug для фильтрации
+	Tags     []string `json:"tags,omitempty"`
+	TagSlugs []string `json:"tag_slugs,omitempty"`
+
+	// Площадка: координаты индексируются как geo_point для пространственных запросов
+	VenueID     *int64    `json:"venue_id,omitempty"`
+	VenueName   string    `json:"venue_name,omitempty"`
+	City        string    `json:"city,omitempty"`
+	GeoLocation *GeoPoint `json:"geo_location,omitempty"`
+
+	// Организатор: имя денормализуется для поиска и подсказок
+	OrganizerID   *int64 `json:"organizer_id,omitempty"`
+	OrganizerName string `json:"organizer_name,omitempty"`
+
+	// Цены: диапазон по билетным категориям для фильтрации, сами категории только хранятся
+	PriceFrom   *float32        `json:"price_from,omitempty"`
+	PriceTo     *float32        `json:"price_to,omitempty"`
+	Currency    string          `json:"currency,omitempty"`
+	TicketTiers []db.TicketTier `json:"ticket_tiers,omitempty"`
+
+	// Жизненный цикл: индексируются все статусы, публичный поиск фильтрует по status
+	Status       string     `json:"status,omitempty"`
+	PublishAt    *time.Time `json:"publish_at,omitempty"`
+	StatusReason string     `json:"status_reason,omitempty"`
+
+	// Версия строки в PostgreSQL, используется как внешняя версия документа
+	Version int64 `json:"version,omitempty"`
+
+	// Внешний источник импортированного события
+	SourceID   *int64 `json:"source_id,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
+
+	// Переводы только хранятся, для поиска они раскладываются по name_i18n и description_i18n
+	Translations []db.EventTranslation `json:"translations,omitempty"`
+
+	// Изображения только хранятся, чтобы выдача поиска не обращалась к PostgreSQL
+	Images []db.EventImage `json:"images,omitempty"`
+
+	// Заполняются только в результатах поиска и в индекс не попадают
+	OccurrenceStart *time.Time `json:"occurrence_start,omitempty"`
+	IsOverride      bool       `json:"is_override,omitempty"`
+	DistanceKm      *float64   `json:"distance_km,omitempty"` // Расстояние от точки гео-поиска
+}
+
+// GeoPoint координаты в формате geo_point OpenSearch
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
 }
 
 type SearchResult struct {
@@ -27,6 +84,36 @@ type SearchResult struct {
 	Total      int64            `json:"total"`
 	MaxScore   *float64         `json:"max_score,omitempty"`
 	SearchTime string           `json:"search_time"`
+
+	// Курсор следующей страницы при обходе через search_after, nil на последней странице
+	Next *SearchCursor `json:"next,omitempty"`
+
+	// Фасеты, если были запрошены
+	Facets *db.EventFacets `json:"facets,omitempty"`
+}
+
+// SearchCursor позиция обхода через search_after: значения сортировки последнего документа
+// страницы и снимок индекса (point in time). PitID пуст, пока снимок не открыт.
+type SearchCursor struct {
+	PitID       string `json:"pit_id,omitempty"`
+	SearchAfter []any  `json:"search_after"`
+}
+
+// CalendarResult календарь разовых событий по дням и серии, пересекающиеся с его диапазоном.
+// Серии не попадают в дни: их вхождения разворачиваются после поиска.
+type CalendarResult struct {
+	Days        []*CalendarDay   `json:"days"`
+	Series      []*EventDocument `json:"series"`
+	SeriesTotal int64            `json:"series_total"` // Всего серий, Series может быть усечен
+	SearchTime  string           `json:"search_time"`
+}
+
+// CalendarDay разовые события одного дня календаря
+type CalendarDay struct {
+	Date       string           `json:"date"` // YYYY-MM-DD в часовом поясе календаря
+	Count      int64            `json:"count"`
+	Categories []db.FacetBucket `json:"categories,omitempty"`
+	Highlights []*EventDocument `json:"highlights,omitempty"`
 }
 
 func (e *EventDocument) PrepareForIndex() map[string]any {
@@ -41,8 +128,61 @@ func (e *EventDocument) PrepareForIndex() map[string]any {
 		"price":       e.Price,
 		"image":       e.Image,
 		"source":      e.Source,
+		"starts_at":   e.StartsAt,
+		"ends_at":     e.EndsAt,
+		"timezone":    e.Timezone,
 		"created_at":  e.CreatedAt,
 		"updated_at":  e.UpdatedAt,
+		"tags":        e.Tags,
+		"tag_slugs":   e.TagSlugs,
+		"price_from":  e.PriceFrom,
+		"price_to":    e.PriceTo,
+		"currency":    e.Currency,
+		"status":      e.Status,
+		"publish_at":  e.PublishAt,
+		"version":     e.Version,
+	}
+
+	if e.StatusReason != "" {
+		doc["status_reason"] = e.StatusReason
+	}
+
+	if len(e.TicketTiers) > 0 {
+		doc["ticket_tiers"] = e.TicketTiers
+	}
+
+	if e.SourceID != nil {
+		doc["source_id"] = *e.SourceID
+		doc["external_id"] = e.ExternalID
+	}
+
+	if len(e.Translations) > 0 {
+		doc["translations"] = e.Translations
+		doc["name_i18n"], doc["description_i18n"] = e.translatedTextFields()
+	}
+
+	if len(e.Images) > 0 {
+		doc["images"] = e.Images
+	}
+
+	if e.VenueID != nil {
+		doc["venue_id"] = *e.VenueID
+		doc["venue_name"] = e.VenueName
+		doc["city"] = e.City
+	}
+	if e.GeoLocation != nil {
+		doc["geo_location"] = e.GeoLocation
+	}
+
+	if e.OrganizerID != nil {
+		doc["organizer_id"] = *e.OrganizerID
+		doc["organizer_name"] = e.OrganizerName
+	}
+
+	if e.RecurrenceRule != "" {
+		doc["recurrence_rule"] = e.RecurrenceRule
+		doc["recurrence_exdates"] = e.RecurrenceExDates
+		doc["recurrence_until"] = e.RecurrenceUntil
 	}
 
 	return doc
@@ -61,7 +201,93 @@ func (e *EventDocument) ToDBEvent() *db.Event {
 		Price:       e.Price,
 		Image:       e.Image,
 		Source:      e.Source,
+		StartsAt:    e.StartsAt,
+		EndsAt:      e.EndsAt,
+		Timezone:    e.Timezone,
 		CreatedAt:   e.CreatedAt,
 		UpdatedAt:   e.UpdatedAt,
+
+		RecurrenceRule:    e.RecurrenceRule,
+		RecurrenceExDates: e.RecurrenceExDates,
+		RecurrenceUntil:   e.RecurrenceUntil,
+		OccurrenceStart:   e.OccurrenceStart,
+		IsOverride:        e.IsOverride,
+		DistanceKm:        e.DistanceKm,
+		Tags:              e.Tags,
+		VenueID:           e.VenueID,
+		Venue:             e.Venue(),
+		OrganizerID:       e.OrganizerID,
+		Organizer:         e.Organizer(),
+		PriceFrom:         e.PriceFrom,
+		PriceTo:           e.PriceTo,
+		Currency:          e.Currency,
+		TicketTiers:       e.TicketTiers,
+		Status:            e.Status,
+		PublishAt:         e.PublishAt,
+		StatusReason:      e.StatusReason,
+		Version:           e.Version,
+		SourceID:          e.SourceID,
+		ExternalID:        e.ExternalID,
+		Translations:      e.Translations,
+		Images:            e.Images,
+	}
+}
+
+// IndexedLanguages языки переводов, для которых в маппинге есть подполя
+// name_i18n.<язык> и description_i18n.<язык> со своим анализатором
+var IndexedLanguages = []string{"en"}
+
+// translatedTextFields раскладывает переводы по подполям индексируемых языков.
+// Для языка берется перевод без региона, а если его нет - первый региональный.
+func (e *EventDocument) translatedTextFields() (names, descriptions map[string]string) {
+	locales := make([]string, 0, len(e.Translations))
+	for _, translation := range e.Translations {
+		locales = append(locales, translation.Locale)
+	}
+
+	names = make(map[string]string)
+	descriptions = make(map[string]string)
+	for _, lang := range IndexedLanguages {
+		locale := db.ResolveLocale(locales, lang)
+		for _, translation := range e.Translations {
+			if locale == "" || translation.Locale != locale {
+				continue
+			}
+			if translation.Name != "" {
+				names[lang] = translation.Name
+			}
+			if translation.Description != "" {
+				descriptions[lang] = translation.Description
+			}
+		}
+	}
+
+	return names, descriptions
+}
+
+// Organizer восстанавливает организатора из денормализованных полей документа
+func (e *EventDocument) Organizer() *db.Organizer {
+	if e.OrganizerID == nil {
+		return nil
+	}
+	return &db.Organizer{Id: *e.OrganizerID, Name: e.OrganizerName}
+}
+
+// Venue восстанавливает площадку из денормализованных полей документа
+func (e *EventDocument) Venue() *db.Venue {
+	if e.VenueID == nil {
+		return nil
+	}
+
+	venue := &db.Venue{
+		Id:   *e.VenueID,
+		Name: e.VenueName,
+		City: e.City,
+	}
+	if e.GeoLocation != nil {
+		lat, lon := e.GeoLocation.Lat, e.GeoLocation.Lon
+		venue.Latitude = &lat
+		venue.Longitude = &lon
 	}
+	return venue
 }
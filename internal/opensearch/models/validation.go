@@ -25,6 +25,18 @@ func (e *EventDocument) Validate() error {
 		errors = append(errors, "price cannot be negative")
 	}
 
+	if e.PriceFrom != nil && e.PriceTo != nil && *e.PriceFrom > *e.PriceTo {
+		errors = append(errors, "price_from cannot be greater than price_to")
+	}
+
+	if e.StartsAt != nil && e.EndsAt != nil && e.EndsAt.Before(*e.StartsAt) {
+		errors = append(errors, "ends_at cannot be before starts_at")
+	}
+
+	if g := e.GeoLocation; g != nil && (g.Lat < -90 || g.Lat > 90 || g.Lon < -180 || g.Lon > 180) {
+		errors = append(errors, "geo_location is out of range")
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("validation errors: %s", strings.Join(errors, ", "))
 	}
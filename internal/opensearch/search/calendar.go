@@ -0,0 +1,209 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
+)
+
+// CalendarRequest параметры календаря событий. Диапазон дней задается
+// DateFrom/DateTo фильтра.
+type CalendarRequest struct {
+	Timezone   string         // Часовой пояс дней (IANA)
+	Highlights int            // Событий в подборке дня, 0 - без подборки
+	Ranking    []db.EventSort // Порядок подборки, пусто - по началу
+	Categories int            // Максимум категорий дня
+	MaxSeries  int            // Максимум серий в ответе
+}
+
+// GetRanking возвращает порядок подборки: заданный явно, иначе по началу.
+func (r *CalendarRequest) GetRanking() []db.EventSort {
+	if len(r.Ranking) > 0 {
+		return r.Ranking
+	}
+	return []db.EventSort{{Field: db.SortFieldStartsAt}}
+}
+
+// BuildCalendarQuery строит запрос календаря. Разовые события считаются по дням начала
+// агрегацией date_histogram с top_hits подборки, серии отбираются post_filter в hits:
+// их вхождения по дням разворачиваются после поиска.
+func (qb *QueryBuilder) BuildCalendarQuery(filter *Filter, req *CalendarRequest) map[string]any {
+	query := qb.BuildSearchQuery(filter)
+	delete(query, "from")
+
+	ranking := qb.buildSortQuery(&Filter{Sort: req.GetRanking()})
+	recurring := map[string]any{"exists": map[string]any{"field": "recurrence_rule"}}
+
+	query["size"] = req.MaxSeries
+	query["sort"] = ranking
+	query["post_filter"] = recurring
+
+	// Фильтр дат отбирает и события, начавшиеся раньше диапазона: в дни попадают
+	// только начинающиеся внутри него
+	from, to := filter.DateWindow()
+	oneOff := map[string]any{
+		"bool": map[string]any{
+			"must_not": recurring,
+			"filter": map[string]any{
+				"range": map[string]any{
+					"starts_at": map[string]any{
+						"gte": from.Format(time.RFC3339),
+						"lt":  to.Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	}
+
+	dayAggs := map[string]any{
+		"categories": qb.buildTermsFacet("category_id", "category_name", req.Categories),
+	}
+	if req.Highlights > 0 {
+		dayAggs["highlights"] = map[string]any{
+			"top_hits": map[string]any{
+				"size": req.Highlights,
+				"sort": ranking,
+			},
+		}
+	}
+
+	query["aggs"] = map[string]any{
+		"days": map[string]any{
+			"filter": oneOff,
+			"aggs": map[string]any{
+				"values": map[string]any{
+					"date_histogram": map[string]any{
+						"field":             "starts_at",
+						"calendar_interval": "day",
+						"time_zone":         db.LoadLocation(req.Timezone).String(),
+						"format":            "yyyy-MM-dd",
+						"min_doc_count":     1,
+					},
+					"aggs": dayAggs,
+				},
+			},
+		},
+	}
+
+	return query
+}
+
+// EventCalendar строит календарь событий фильтра за диапазон filter.DateFrom..DateTo
+// одним запросом.
+func (s *Searcher) EventCalendar(ctx context.Context, filter *Filter, req *CalendarRequest) (*models.CalendarResult, error) {
+	if filter.DateFrom == nil || filter.DateTo == nil {
+		return nil, fmt.Errorf("calendar requires both date bounds")
+	}
+
+	queryBody, err := json.Marshal(s.queryBuilder.BuildCalendarQuery(filter, req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal calendar query: %w", err)
+	}
+
+	start := time.Now()
+	res, err := s.client.GetNativeClient().Search(
+		s.client.GetNativeClient().Search.WithContext(ctx),
+		s.client.GetNativeClient().Search.WithIndex(s.client.GetIndexName()),
+		s.client.GetNativeClient().Search.WithBody(bytes.NewReader(queryBody)),
+		s.client.GetNativeClient().Search.WithTrackTotalHits(true),
+	)
+	searchTime := time.Since(start)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute calendar search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		s.logger.Error("OpenSearch calendar query failed",
+			"status", res.Status(),
+			"error_body", string(body),
+			"query", string(queryBody),
+		)
+		return nil, fmt.Errorf("calendar search failed with status: %s", res.Status())
+	}
+
+	result, err := parseCalendarResponse(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse calendar response: %w", err)
+	}
+	result.SearchTime = searchTime.String()
+
+	s.logger.Debug("Calendar search completed",
+		"days", len(result.Days),
+		"series", len(result.Series),
+		"series_total", result.SeriesTotal,
+		"search_time", searchTime,
+	)
+
+	return result, nil
+}
+
+// calendarHits документы hits ответа или top_hits
+type calendarHits struct {
+	Total struct {
+		Value int64 `json:"value"`
+	} `json:"total"`
+	Hits []struct {
+		Source models.EventDocument `json:"_source"`
+	} `json:"hits"`
+}
+
+// documents возвращает документы hits
+func (h calendarHits) documents() []*models.EventDocument {
+	docs := make([]*models.EventDocument, 0, len(h.Hits))
+	for _, hit := range h.Hits {
+		doc := hit.Source
+		docs = append(docs, &doc)
+	}
+	return docs
+}
+
+// parseCalendarResponse разбирает ответ запроса календаря
+func parseCalendarResponse(body io.Reader) (*models.CalendarResult, error) {
+	var response struct {
+		Hits         calendarHits `json:"hits"`
+		Aggregations struct {
+			Days struct {
+				Values struct {
+					Buckets []struct {
+						KeyAsString string           `json:"key_as_string"`
+						DocCount    int64            `json:"doc_count"`
+						Categories  termsAggregation `json:"categories"`
+						Highlights  struct {
+							Hits calendarHits `json:"hits"`
+						} `json:"highlights"`
+					} `json:"buckets"`
+				} `json:"values"`
+			} `json:"days"`
+		} `json:"aggregations"`
+	}
+
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode calendar response: %w", err)
+	}
+
+	buckets := response.Aggregations.Days.Values.Buckets
+	result := &models.CalendarResult{
+		Days:        make([]*models.CalendarDay, 0, len(buckets)),
+		Series:      response.Hits.documents(),
+		SeriesTotal: response.Hits.Total.Value,
+	}
+	for _, bucket := range buckets {
+		result.Days = append(result.Days, &models.CalendarDay{
+			Date:       bucket.KeyAsString,
+			Count:      bucket.DocCount,
+			Categories: bucket.Categories.facetBuckets(),
+			Highlights: bucket.Highlights.Hits.documents(),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,212 @@
+package search
+
+import (
+	"strconv"
+
+	"github.com/rx3lixir/event-service/internal/db"
+)
+
+// facetFilterOrder порядок фильтров фасетов в запросе
+var facetFilterOrder = []string{
+	db.FacetCategories,
+	db.FacetSources,
+	db.FacetCities,
+	db.FacetVenues,
+	db.FacetPrice,
+	db.FacetDates,
+}
+
+// facetFilters фильтры, которыми выбираются значения фасетов, по фасету
+type facetFilters map[string]map[string]any
+
+// except возвращает фильтры всех фасетов, кроме facet. Пустой facet - все фильтры.
+func (f facetFilters) except(facet string) []any {
+	var filters []any
+	for _, name := range facetFilterOrder {
+		if clause, ok := f[name]; ok && name != facet {
+			filters = append(filters, clause)
+		}
+	}
+	return filters
+}
+
+// buildFacetFilters строит фильтры фильтра, соответствующие фасетам
+func (qb *QueryBuilder) buildFacetFilters(filter *Filter) facetFilters {
+	filters := facetFilters{}
+
+	if len(filter.CategoryIDs) > 0 {
+		filters[db.FacetCategories] = qb.buildCategoriesFilter(filter.CategoryIDs)
+	}
+	if filter.Source != nil {
+		filters[db.FacetSources] = qb.buildSourceFilter(*filter.Source)
+	}
+	if len(filter.Cities) > 0 {
+		filters[db.FacetCities] = qb.buildCitiesFilter(filter.Cities)
+	}
+	if len(filter.VenueIDs) > 0 {
+		filters[db.FacetVenues] = qb.buildVenuesFilter(filter.VenueIDs)
+	}
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		filters[db.FacetPrice] = qb.buildPriceRangeFilter(filter.MinPrice, filter.MaxPrice)
+	}
+	if filter.DateFrom != nil || filter.DateTo != nil {
+		filters[db.FacetDates] = qb.buildDateRangeFilter(filter.DateFrom, filter.DateTo)
+	}
+
+	return filters
+}
+
+// buildFacetAggs строит агрегации фасетов. Каждый фасет оборачивается в filter-агрегацию
+// с фильтрами остальных фасетов: основной запрос их не содержит (они в post_filter),
+// а собственный фильтр фасета не должен сужать его счетчики.
+func (qb *QueryBuilder) buildFacetAggs(req *db.FacetRequest, filters facetFilters) map[string]any {
+	aggs := map[string]any{}
+
+	add := func(facet string, values map[string]any) {
+		scope := map[string]any{"match_all": map[string]any{}}
+		if others := filters.except(facet); len(others) > 0 {
+			scope = map[string]any{"bool": map[string]any{"filter": others}}
+		}
+		aggs[facet] = map[string]any{
+			"filter": scope,
+			"aggs":   map[string]any{"values": values},
+		}
+	}
+
+	if req.Categories {
+		add(db.FacetCategories, qb.buildTermsFacet("category_id", "category_name", req.Size))
+	}
+	if req.Sources {
+		add(db.FacetSources, withoutEmptyKey(qb.buildTermsFacet("source", "", req.Size)))
+	}
+	if req.Cities {
+		add(db.FacetCities, withoutEmptyKey(qb.buildTermsFacet("city", "", req.Size)))
+	}
+	if req.Venues {
+		add(db.FacetVenues, qb.buildTermsFacet("venue_id", "venue_name.keyword", req.Size))
+	}
+	if req.PriceInterval > 0 {
+		add(db.FacetPrice, map[string]any{
+			"histogram": map[string]any{
+				"field":         "price_from",
+				"interval":      req.PriceInterval,
+				"min_doc_count": 1,
+			},
+		})
+	}
+	if req.Dates {
+		// Дни считаются по первому вхождению серии, как и в PostgreSQL
+		add(db.FacetDates, map[string]any{
+			"date_histogram": map[string]any{
+				"field":             "starts_at",
+				"calendar_interval": "day",
+				"time_zone":         db.LoadLocation(req.Timezone).String(),
+				"format":            "yyyy-MM-dd",
+				"min_doc_count":     1,
+			},
+		})
+	}
+
+	return aggs
+}
+
+// buildTermsFacet строит terms-агрегацию по field. Подпись значения берется
+// из labelField вложенной агрегацией, пустой labelField - без подписи.
+func (qb *QueryBuilder) buildTermsFacet(field, labelField string, size int) map[string]any {
+	agg := map[string]any{
+		"terms": map[string]any{
+			"field": field,
+			"size":  size,
+			"order": []any{
+				map[string]any{"_count": "desc"},
+				map[string]any{"_key": "asc"},
+			},
+		},
+	}
+	if labelField != "" {
+		agg["aggs"] = map[string]any{
+			"label": map[string]any{
+				"terms": map[string]any{"field": labelField, "size": 1},
+			},
+		}
+	}
+	return agg
+}
+
+// withoutEmptyKey исключает из terms-агрегации по строковому полю пустое значение:
+// площадка без города не образует фасет, как и в PostgreSQL
+func withoutEmptyKey(agg map[string]any) map[string]any {
+	agg["terms"].(map[string]any)["exclude"] = []string{""}
+	return agg
+}
+
+// facetAggregation ответ агрегации одного фасета
+type facetAggregation struct {
+	Values termsAggregation `json:"values"`
+}
+
+// termsAggregation ответ terms-агрегации или гистограммы с подписями значений
+type termsAggregation struct {
+	Buckets []struct {
+		Key         any    `json:"key"`
+		KeyAsString string `json:"key_as_string"`
+		DocCount    int64  `json:"doc_count"`
+		Label       struct {
+			Buckets []struct {
+				Key string `json:"key"`
+			} `json:"buckets"`
+		} `json:"label"`
+	} `json:"buckets"`
+}
+
+// facetBuckets переводит бакеты агрегации в значения фасета
+func (agg termsAggregation) facetBuckets() []db.FacetBucket {
+	result := make([]db.FacetBucket, 0, len(agg.Buckets))
+	for _, bucket := range agg.Buckets {
+		facetBucket := db.FacetBucket{Key: bucket.KeyAsString, Count: bucket.DocCount}
+		if facetBucket.Key == "" {
+			facetBucket.Key = formatFacetKey(bucket.Key)
+		}
+		if len(bucket.Label.Buckets) > 0 {
+			facetBucket.Label = bucket.Label.Buckets[0].Key
+		}
+		result = append(result, facetBucket)
+	}
+	return result
+}
+
+// parseFacets переводит агрегации фасетов в db.EventFacets. nil, если фасетов нет.
+func parseFacets(aggs map[string]facetAggregation) *db.EventFacets {
+	if len(aggs) == 0 {
+		return nil
+	}
+
+	buckets := func(facet string) []db.FacetBucket {
+		agg, ok := aggs[facet]
+		if !ok {
+			return nil
+		}
+		return agg.Values.facetBuckets()
+	}
+
+	return &db.EventFacets{
+		Categories: buckets(db.FacetCategories),
+		Sources:    buckets(db.FacetSources),
+		Cities:     buckets(db.FacetCities),
+		Venues:     buckets(db.FacetVenues),
+		Price:      buckets(db.FacetPrice),
+		Dates:      buckets(db.FacetDates),
+	}
+}
+
+// formatFacetKey форматирует ключ бакета: числа без экспоненты и лишних нулей
+func formatFacetKey(key any) string {
+	switch v := key.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
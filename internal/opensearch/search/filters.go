@@ -2,36 +2,74 @@ package search
 
 import (
 	"time"
+
+	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
 )
 
 type Filter struct {
 	// Поисковый текст
 	Query string `json:"query,omitempty"`
+	// Язык запроса (код без региона): переводы на него ищутся в первую очередь
+	Language string `json:"language,omitempty"`
 
 	// Фильтры
-	CategoryIDs []int64    `json:"category_ids,omitempty"`
-	MinPrice    *float32   `json:"min_price,omitempty"`
-	MaxPrice    *float32   `json:"max_price,omitempty"`
-	DateFrom    *time.Time `json:"date_from,omitempty"`
-	DateTo      *time.Time `json:"date_to,omitempty"`
-	Location    *string    `json:"location,omitempty"`
-	Source      *string    `json:"source,omitempty"`
+	CategoryIDs  []int64    `json:"category_ids,omitempty"`
+	MinPrice     *float32   `json:"min_price,omitempty"`
+	MaxPrice     *float32   `json:"max_price,omitempty"`
+	IsFree       *bool      `json:"is_free,omitempty"`
+	DateFrom     *time.Time `json:"date_from,omitempty"`
+	DateTo       *time.Time `json:"date_to,omitempty"`
+	Location     *string    `json:"location,omitempty"`
+	Source       *string    `json:"source,omitempty"`
+	TagsAny      []string   `json:"tags_any,omitempty"` // slug тегов, хотя бы один
+	TagsAll      []string   `json:"tags_all,omitempty"` // slug тегов, все сразу
+	Statuses     []string   `json:"statuses,omitempty"` // Допустимые статусы, пусто - любые
+	OrganizerIDs []int64    `json:"organizer_ids,omitempty"`
+	VenueIDs     []int64    `json:"venue_ids,omitempty"`
+	Cities       []string   `json:"cities,omitempty"` // Города площадок, точное совпадение
+
+	// Гео-фильтры по координатам площадки
+	Near        *GeoDistance `json:"near,omitempty"`
+	BoundingBox *BoundingBox `json:"bbox,omitempty"`
 
 	// Пагинация
 	From int `json:"from,omitempty"`
 	Size int `json:"size,omitempty"`
 
-	// Сортировка
-	SortBy    string `json:"sort_by,omitempty"`
-	SortOrder string `json:"sort_order,omitempty"`
+	// Обход через search_after вместо from: результат содержит курсор следующей страницы.
+	// After - курсор предыдущей страницы, nil для первой
+	Cursor bool                 `json:"cursor,omitempty"`
+	After  *models.SearchCursor `json:"after,omitempty"`
+
+	// Фасеты, считаются агрегациями в том же запросе. Фильтры фасетов при этом
+	// применяются как post_filter и не обнуляют счетчики своих фасетов
+	Facets *db.FacetRequest `json:"facets,omitempty"`
+
+	// Сортировка, пусто - по релевантности при Query, иначе db.DefaultEventSort.
+	// Сортировка по расстоянию считается от центра Near
+	Sort []db.EventSort `json:"sort,omitempty"`
+}
+
+// GeoDistance точка и радиус поиска в километрах
+type GeoDistance struct {
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	RadiusKm float64 `json:"radius_km"`
+}
+
+// BoundingBox прямоугольная область на карте
+type BoundingBox struct {
+	TopLeftLat     float64 `json:"top_left_lat"`
+	TopLeftLon     float64 `json:"top_left_lon"`
+	BottomRightLat float64 `json:"bottom_right_lat"`
+	BottomRightLon float64 `json:"bottom_right_lon"`
 }
 
 func NewFilter() *Filter {
 	return &Filter{
-		From:      0,
-		Size:      20,
-		SortBy:    "created_at",
-		SortOrder: "desc",
+		From: 0,
+		Size: 20,
 	}
 }
 
@@ -42,6 +80,32 @@ func (f *Filter) WithQuery(query string) *Filter {
 	return f
 }
 
+func (f *Filter) WithLanguage(language string) *Filter {
+	f.Language = language
+	return f
+}
+
+func (f *Filter) WithOrganizers(organizerIDs ...int64) *Filter {
+	f.OrganizerIDs = organizerIDs
+	return f
+}
+
+func (f *Filter) WithVenues(venueIDs ...int64) *Filter {
+	f.VenueIDs = venueIDs
+	return f
+}
+
+func (f *Filter) WithCities(cities ...string) *Filter {
+	f.Cities = cities
+	return f
+}
+
+// WithFacets запрашивает фасеты вместе с результатами поиска
+func (f *Filter) WithFacets(req *db.FacetRequest) *Filter {
+	f.Facets = req
+	return f
+}
+
 func (f *Filter) WithCategories(categoryIDs ...int64) *Filter {
 	f.CategoryIDs = categoryIDs
 	return f
@@ -53,6 +117,12 @@ func (f *Filter) WithPriceRange(min, max *float32) *Filter {
 	return f
 }
 
+// WithFree отбирает только бесплатные (true) или только платные (false) события
+func (f *Filter) WithFree(isFree bool) *Filter {
+	f.IsFree = &isFree
+	return f
+}
+
 func (f *Filter) WithDateRange(from, to *time.Time) *Filter {
 	f.DateFrom = from
 	f.DateTo = to
@@ -69,25 +139,136 @@ func (f *Filter) WithSource(source string) *Filter {
 	return f
 }
 
+// WithTagsAny отбирает события хотя бы с одним из тегов (без учета регистра)
+func (f *Filter) WithTagsAny(tags ...string) *Filter {
+	f.TagsAny = db.TagSlugs(tags)
+	return f
+}
+
+// WithTagsAll отбирает события со всеми перечисленными тегами (без учета регистра)
+func (f *Filter) WithTagsAll(tags ...string) *Filter {
+	f.TagsAll = db.TagSlugs(tags)
+	return f
+}
+
+// WithStatuses ограничивает поиск событиями в перечисленных статусах
+func (f *Filter) WithStatuses(statuses ...string) *Filter {
+	f.Statuses = statuses
+	return f
+}
+
+func (f *Filter) WithNear(lat, lon, radiusKm float64) *Filter {
+	f.Near = &GeoDistance{Lat: lat, Lon: lon, RadiusKm: radiusKm}
+	return f
+}
+
+func (f *Filter) WithBoundingBox(topLeftLat, topLeftLon, bottomRightLat, bottomRightLon float64) *Filter {
+	f.BoundingBox = &BoundingBox{
+		TopLeftLat:     topLeftLat,
+		TopLeftLon:     topLeftLon,
+		BottomRightLat: bottomRightLat,
+		BottomRightLon: bottomRightLon,
+	}
+	return f
+}
+
 func (f *Filter) WithPagination(from, size int) *Filter {
 	f.From = from
 	f.Size = size
 	return f
 }
 
-func (f *Filter) WithSort(sortBy, sortOrder string) *Filter {
-	f.SortBy = sortBy
-	f.SortOrder = sortOrder
+// WithCursor включает обход через search_after, не ограниченный index.max_result_window.
+// after - курсор предыдущей страницы (SearchResult.Next), nil для первой. From сбрасывается.
+func (f *Filter) WithCursor(after *models.SearchCursor) *Filter {
+	f.Cursor = true
+	f.After = after
+	f.From = 0
+	return f
+}
+
+// WithSort задает порядок выдачи; при равенстве ключей документы упорядочиваются по id
+func (f *Filter) WithSort(sorts ...db.EventSort) *Filter {
+	f.Sort = sorts
 	return f
 }
 
+// GetSort возвращает сортировку: заданную явно, иначе по релевантности для
+// поискового запроса и db.DefaultEventSort без него.
+func (f *Filter) GetSort() []db.EventSort {
+	if len(f.Sort) > 0 {
+		return f.Sort
+	}
+	if f.Query != "" {
+		return []db.EventSort{
+			{Field: db.SortFieldRelevance, Descending: true},
+			{Field: db.SortFieldCreatedAt, Descending: true},
+		}
+	}
+	return db.DefaultEventSort()
+}
+
+// sortIndex возвращает позицию поля в сортировке или -1
+func (f *Filter) sortIndex(field string) int {
+	for i, sort := range f.GetSort() {
+		if sort.Field == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// DateWindow возвращает диапазон дат как полуинтервал [from, to).
+// Незаданные границы возвращаются нулевыми.
+func (f *Filter) DateWindow() (time.Time, time.Time) {
+	var from, to time.Time
+	if f.DateFrom != nil {
+		from = *f.DateFrom
+	}
+	if f.DateTo != nil {
+		to = f.DateTo.AddDate(0, 0, 1)
+	}
+	return from, to
+}
+
 func (f *Filter) IsEmpty() bool {
 	return f.Query == "" &&
 		len(f.CategoryIDs) == 0 &&
 		f.MinPrice == nil &&
 		f.MaxPrice == nil &&
+		f.IsFree == nil &&
 		f.DateFrom == nil &&
 		f.DateTo == nil &&
 		f.Location == nil &&
-		f.Source == nil
+		f.Source == nil &&
+		len(f.TagsAny) == 0 &&
+		len(f.TagsAll) == 0 &&
+		len(f.Statuses) == 0 &&
+		len(f.VenueIDs) == 0 &&
+		len(f.Cities) == 0 &&
+		f.Near == nil &&
+		f.BoundingBox == nil
+}
+
+// SimilarFilter параметры поиска событий, похожих на событие EventID, через more_like_this
+type SimilarFilter struct {
+	EventID int64 `json:"event_id"`
+
+	// Окно начала похожих событий [StartsFrom, StartsTo), незаданные границы не ограничивают
+	StartsFrom *time.Time `json:"starts_from,omitempty"`
+	StartsTo   *time.Time `json:"starts_to,omitempty"`
+
+	// Поля сравнения текста, пусто - name, description, location и venue_name
+	Fields []string `json:"fields,omitempty"`
+	// Фильтры выдачи, nil - без фильтров
+	Filter *Filter `json:"filter,omitempty"`
+
+	// Усиление событий той же категории и начинающихся около StartsNear, пусто - без усиления
+	CategoryID int64      `json:"category_id,omitempty"`
+	StartsNear *time.Time `json:"starts_near,omitempty"`
+
+	// Минимальная оценка похожего события с учетом усилений, 0 - без порога
+	MinScore float64 `json:"min_score,omitempty"`
+
+	Size int `json:"size,omitempty"`
 }
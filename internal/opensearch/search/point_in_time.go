@@ -0,0 +1,102 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
+)
+
+// pitKeepAlive время жизни снимка индекса; каждый запрос по снимку продлевает его.
+// Брошенный обход держит снимок не дольше этого срока
+const pitKeepAlive = "2m"
+
+// ErrCursorExpired возвращается, когда снимок индекса курсора закрыт или истек
+var ErrCursorExpired = errors.New("search cursor expired")
+
+// nextCursor возвращает курсор страницы после result. На неполной странице обход закончен:
+// курсора нет, а открытый снимок закрывается.
+func (s *Searcher) nextCursor(ctx context.Context, filter *Filter, pitID string, result *models.SearchResult) *models.SearchCursor {
+	next := result.Next
+	if next == nil || filter.Size <= 0 || len(result.Events) < filter.Size {
+		if pitID != "" {
+			s.closePointInTime(ctx, pitID)
+		}
+		return nil
+	}
+
+	// Ответ по снимку может вернуть новый идентификатор снимка
+	if next.PitID == "" {
+		next.PitID = pitID
+	}
+	return next
+}
+
+// openPointInTime открывает снимок индекса событий и возвращает его идентификатор.
+// Клиент opensearch-go v1 не знает этого API, поэтому запрос выполняется через Perform.
+func (s *Searcher) openPointInTime(ctx context.Context) (string, error) {
+	path := "/" + url.PathEscape(s.client.GetIndexName()) + "/_search/point_in_time?keep_alive=" + pitKeepAlive
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build point in time request: %w", err)
+	}
+
+	res, err := s.client.GetNativeClient().Perform(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to open point in time: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(res.Body)
+		s.logger.Error("OpenSearch point in time request failed",
+			"status", res.Status,
+			"error_body", string(body),
+		)
+		return "", fmt.Errorf("open point in time failed with status: %s", res.Status)
+	}
+
+	var response struct {
+		PitID string `json:"pit_id"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode point in time response: %w", err)
+	}
+	if response.PitID == "" {
+		return "", fmt.Errorf("point in time response has no pit_id")
+	}
+
+	return response.PitID, nil
+}
+
+// closePointInTime закрывает снимок индекса. Ошибка только логируется:
+// незакрытый снимок истечет сам через pitKeepAlive.
+func (s *Searcher) closePointInTime(ctx context.Context, pitID string) {
+	body, err := json.Marshal(map[string]any{"pit_id": []string{pitID}})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, "/_search/point_in_time", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.client.GetNativeClient().Perform(req)
+	if err != nil {
+		s.logger.Warn("failed to close point in time", "error", err)
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest && res.StatusCode != http.StatusNotFound {
+		s.logger.Warn("close point in time failed", "status", res.Status)
+	}
+}
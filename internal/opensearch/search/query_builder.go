@@ -1,8 +1,13 @@
 package search
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
 )
 
 type QueryBuilder struct{}
@@ -23,64 +28,214 @@ func (qb *QueryBuilder) BuildSearchQuery(filter *Filter) map[string]any {
 	}
 
 	var mustQueries []any
-	var filterQueries []any
 
 	// Полнотекстовый поиск
 	if filter.Query != "" {
-		mustQueries = append(mustQueries, qb.buildAdvancedTextSearchQuery(filter.Query))
+		mustQueries = append(mustQueries, qb.buildAdvancedTextSearchQuery(filter.Query, filter.Language))
 	}
 
-	// Фильтры
-	if len(filter.CategoryIDs) > 0 {
-		filterQueries = append(filterQueries, qb.buildCategoriesFilter(filter.CategoryIDs))
+	facetFilters := qb.buildFacetFilters(filter)
+	filterQueries := qb.buildFilterQueries(filter, facetFilters)
+
+	if len(mustQueries) == 0 {
+		mustQueries = append(mustQueries, map[string]any{
+			"match_all": map[string]any{},
+		})
 	}
 
-	if filter.MinPrice != nil || filter.MaxPrice != nil {
-		filterQueries = append(filterQueries, qb.buildPriceRangeFilter(filter.MinPrice, filter.MaxPrice))
+	boolQuery["bool"].(map[string]any)["must"] = mustQueries
+	if len(filterQueries) > 0 {
+		boolQuery["bool"].(map[string]any)["filter"] = filterQueries
 	}
 
-	if filter.DateFrom != nil || filter.DateTo != nil {
-		filterQueries = append(filterQueries, qb.buildDateRangeFilter(filter.DateFrom, filter.DateTo))
+	query["query"] = boolQuery
+
+	if filter.Facets != nil {
+		if postFilter := facetFilters.except(""); len(postFilter) > 0 {
+			query["post_filter"] = map[string]any{"bool": map[string]any{"filter": postFilter}}
+		}
+		query["aggs"] = qb.buildFacetAggs(filter.Facets, facetFilters)
+	}
+
+	// Сортировка
+	query["sort"] = qb.buildSortQuery(filter)
+
+	return query
+}
+
+// buildFilterQueries строит фильтры запроса, кроме полнотекстового поиска.
+// facetFilters - фильтры фасетов того же фильтра.
+func (qb *QueryBuilder) buildFilterQueries(filter *Filter, facetFilters facetFilters) []any {
+	var filterQueries []any
+
+	// Фильтры, которыми выбираются значения фасетов. При запросе фасетов они уходят
+	// в post_filter, иначе применяются как обычные фильтры
+	if filter.Facets == nil {
+		filterQueries = append(filterQueries, facetFilters.except("")...)
+	}
+
+	if filter.IsFree != nil {
+		filterQueries = append(filterQueries, qb.buildIsFreeFilter(*filter.IsFree))
 	}
 
 	if filter.Location != nil {
 		filterQueries = append(filterQueries, qb.buildLocationFilter(*filter.Location))
 	}
 
-	if filter.Source != nil {
-		filterQueries = append(filterQueries, qb.buildSourceFilter(*filter.Source))
+	if len(filter.TagsAny) > 0 {
+		filterQueries = append(filterQueries, qb.buildTagsAnyFilter(filter.TagsAny))
 	}
 
-	if len(mustQueries) == 0 {
-		mustQueries = append(mustQueries, map[string]any{
-			"match_all": map[string]any{},
-		})
+	if len(filter.TagsAll) > 0 {
+		filterQueries = append(filterQueries, qb.buildTagsAllFilter(filter.TagsAll))
 	}
 
-	boolQuery["bool"].(map[string]any)["must"] = mustQueries
+	if len(filter.Statuses) > 0 {
+		filterQueries = append(filterQueries, qb.buildStatusFilter(filter.Statuses))
+	}
+
+	if len(filter.OrganizerIDs) > 0 {
+		filterQueries = append(filterQueries, qb.buildOrganizersFilter(filter.OrganizerIDs))
+	}
+
+	if filter.Near != nil {
+		filterQueries = append(filterQueries, qb.buildGeoDistanceFilter(filter.Near))
+	}
+
+	if filter.BoundingBox != nil {
+		filterQueries = append(filterQueries, qb.buildGeoBoundingBoxFilter(filter.BoundingBox))
+	}
+
+	return filterQueries
+}
+
+// moreLikeThisFields текстовые поля, по которым сравниваются похожие события
+var moreLikeThisFields = []string{"name", "description", "location", "venue_name"}
+
+// Усиление похожих событий той же категории и близких дат
+const (
+	similarCategoryBoost = 2.0
+	similarDateBoost     = 2.0
+	similarDateScale     = "14d" // Расстояние от даты события, на котором усиление падает вдвое
+
+	// DefaultSimilarMinScore порог оценки похожего события: усиления категории и даты
+	// вместе не достигают его без заметного текстового совпадения
+	DefaultSimilarMinScore = similarCategoryBoost + similarDateBoost + 1.0
+)
+
+// BuildMoreLikeThisQuery строит запрос событий, похожих на документ filter.EventID в индексе index.
+// Сам документ в результаты не попадает.
+func (qb *QueryBuilder) BuildMoreLikeThisQuery(index string, filter *SimilarFilter) map[string]any {
+	fields := filter.Fields
+	if len(fields) == 0 {
+		fields = moreLikeThisFields
+	}
+
+	boolQuery := map[string]any{
+		"must": []any{
+			map[string]any{
+				"more_like_this": map[string]any{
+					"fields": fields,
+					"like": []any{
+						map[string]any{
+							"_index": index,
+							"_id":    fmt.Sprintf("%d", filter.EventID),
+						},
+					},
+					// Описания событий короткие: учитываем и редкие, и единичные слова
+					"min_term_freq":        1,
+					"min_doc_freq":         1,
+					"max_query_terms":      25,
+					"minimum_should_match": "30%",
+				},
+			},
+		},
+	}
+
+	var filterQueries []any
+	if filter.StartsFrom != nil || filter.StartsTo != nil {
+		startsAt := map[string]any{}
+		if filter.StartsFrom != nil {
+			startsAt["gte"] = filter.StartsFrom.Format(time.RFC3339)
+		}
+		if filter.StartsTo != nil {
+			startsAt["lt"] = filter.StartsTo.Format(time.RFC3339)
+		}
+		filterQueries = append(filterQueries, map[string]any{"range": map[string]any{"starts_at": startsAt}})
+	}
+	if filter.Filter != nil {
+		filterQueries = append(filterQueries, qb.buildFilterQueries(filter.Filter, qb.buildFacetFilters(filter.Filter))...)
+	}
 	if len(filterQueries) > 0 {
-		boolQuery["bool"].(map[string]any)["filter"] = filterQueries
+		boolQuery["filter"] = filterQueries
 	}
 
-	query["query"] = boolQuery
+	if filter.CategoryID > 0 {
+		boolQuery["should"] = []any{
+			map[string]any{
+				"term": map[string]any{
+					"category_id": map[string]any{"value": filter.CategoryID, "boost": similarCategoryBoost},
+				},
+			},
+		}
+	}
 
-	// Сортировка
-	if filter.SortBy != "" {
-		query["sort"] = qb.buildSortQuery(filter.SortBy, filter.SortOrder)
+	query := map[string]any{"bool": boolQuery}
+
+	// Близость дат прибавляется к текстовой похожести, а не умножает ее:
+	// сильное текстовое совпадение не теряется из-за далекой даты
+	if filter.StartsNear != nil {
+		query = map[string]any{
+			"function_score": map[string]any{
+				"query": query,
+				"functions": []any{
+					map[string]any{
+						"gauss": map[string]any{
+							"starts_at": map[string]any{
+								"origin": filter.StartsNear.Format(time.RFC3339),
+								"scale":  similarDateScale,
+								"decay":  0.5,
+							},
+						},
+						"weight": similarDateBoost,
+					},
+				},
+				"boost_mode": "sum",
+			},
+		}
 	}
 
-	return query
+	result := map[string]any{
+		"size":  filter.Size,
+		"query": query,
+	}
+	if filter.MinScore > 0 {
+		result["min_score"] = filter.MinScore
+	}
+
+	return result
 }
 
-// buildAdvancedTextSearchQuery - улучшенный поисковый запрос
-func (qb *QueryBuilder) buildAdvancedTextSearchQuery(searchText string) map[string]any {
+// translatedSearchBoost во сколько раз совпадение в переводе на язык запроса
+// весомее совпадения в основном содержимом
+const translatedSearchBoost = 1.5
+
+// buildAdvancedTextSearchQuery - улучшенный поисковый запрос.
+// Для языка с индексируемыми переводами (models.IndexedLanguages) сначала ищет
+// в переводах на этот язык, основное содержимое остается запасным вариантом.
+func (qb *QueryBuilder) buildAdvancedTextSearchQuery(searchText, language string) map[string]any {
 	// Очищаем поисковый запрос
 	cleanQuery := strings.TrimSpace(searchText)
 
+	var should []any
+	if slices.Contains(models.IndexedLanguages, language) {
+		should = append(should, qb.buildTranslatedTextSearch(cleanQuery, language)...)
+	}
+
 	// Создаем составной запрос с разными стратегиями поиска
 	return map[string]any{
 		"bool": map[string]any{
-			"should": []any{
+			"should": append(should,
 				// 1. Точное совпадение в названии (максимальный boost)
 				map[string]any{
 					"match_phrase": map[string]any{
@@ -112,7 +267,7 @@ func (qb *QueryBuilder) buildAdvancedTextSearchQuery(searchText string) map[stri
 				map[string]any{
 					"multi_match": map[string]any{
 						"query":    cleanQuery,
-						"fields":   []string{"name^3", "description^2", "location^1"},
+						"fields":   []string{"name^3", "description^2", "location^1", "organizer_name^1.5"},
 						"type":     "cross_fields",
 						"operator": "and",
 						"boost":    2.0,
@@ -122,7 +277,7 @@ func (qb *QueryBuilder) buildAdvancedTextSearchQuery(searchText string) map[stri
 				map[string]any{
 					"multi_match": map[string]any{
 						"query":                cleanQuery,
-						"fields":               []string{"name^2", "description^1.5", "location^1"},
+						"fields":               []string{"name^2", "description^1.5", "location^1", "organizer_name^1"},
 						"type":                 "best_fields",
 						"operator":             "or",
 						"fuzziness":            "AUTO",
@@ -132,12 +287,71 @@ func (qb *QueryBuilder) buildAdvancedTextSearchQuery(searchText string) map[stri
 				},
 				// 6. Prefix поиск для автодополнения
 				qb.buildPrefixSearch(cleanQuery),
-			},
+			),
 			"minimum_should_match": 1,
 		},
 	}
 }
 
+// buildTranslatedTextSearch - те же стратегии поиска по переводам на язык language
+// с повышенным boost. Анализатор подполей соответствует языку
+func (qb *QueryBuilder) buildTranslatedTextSearch(query, language string) []any {
+	name := "name_i18n." + language
+	description := "description_i18n." + language
+
+	clauses := []any{
+		map[string]any{
+			"match_phrase": map[string]any{
+				name: map[string]any{
+					"query": query,
+					"boost": 10.0 * translatedSearchBoost,
+				},
+			},
+		},
+		map[string]any{
+			"match_phrase": map[string]any{
+				description: map[string]any{
+					"query": query,
+					"boost": 5.0 * translatedSearchBoost,
+				},
+			},
+		},
+		map[string]any{
+			"multi_match": map[string]any{
+				"query":    query,
+				"fields":   []string{name + "^3", description + "^2", "location^1"},
+				"type":     "cross_fields",
+				"operator": "and",
+				"boost":    2.0 * translatedSearchBoost,
+			},
+		},
+		map[string]any{
+			"multi_match": map[string]any{
+				"query":                query,
+				"fields":               []string{name + "^2", description + "^1.5"},
+				"type":                 "best_fields",
+				"operator":             "or",
+				"fuzziness":            "AUTO",
+				"boost":                0.5 * translatedSearchBoost,
+				"minimum_should_match": "75%",
+			},
+		},
+	}
+
+	if words := strings.Fields(query); len(words) > 0 && len(words[len(words)-1]) >= 2 {
+		clauses = append(clauses, map[string]any{
+			"match_phrase_prefix": map[string]any{
+				name + ".suggest": map[string]any{
+					"query": words[len(words)-1],
+					"boost": 2.0 * translatedSearchBoost,
+				},
+			},
+		})
+	}
+
+	return clauses
+}
+
 // buildPrefixSearch - поиск по началу слов
 func (qb *QueryBuilder) buildPrefixSearch(query string) map[string]any {
 	words := strings.Fields(query)
@@ -154,7 +368,7 @@ func (qb *QueryBuilder) buildPrefixSearch(query string) map[string]any {
 	return map[string]any{
 		"multi_match": map[string]any{
 			"query":  lastWord,
-			"fields": []string{"name.suggest^2", "location.suggest^1"},
+			"fields": []string{"name.suggest^2", "location.suggest^1", "organizer_name.suggest^1"},
 			"type":   "phrase_prefix",
 			"boost":  1.0,
 		},
@@ -170,36 +384,113 @@ func (qb *QueryBuilder) buildCategoriesFilter(categoryIDs []int64) map[string]an
 	}
 }
 
+// buildPriceRangeFilter отбирает события, диапазон цен [price_from, price_to]
+// которых пересекается с запрошенным
 func (qb *QueryBuilder) buildPriceRangeFilter(minPrice, maxPrice *float32) map[string]any {
-	rangeQuery := map[string]any{}
+	var must []any
 
 	if minPrice != nil {
-		rangeQuery["gte"] = *minPrice
+		must = append(must, map[string]any{
+			"range": map[string]any{
+				"price_to": map[string]any{"gte": *minPrice},
+			},
+		})
 	}
 	if maxPrice != nil {
-		rangeQuery["lte"] = *maxPrice
+		must = append(must, map[string]any{
+			"range": map[string]any{
+				"price_from": map[string]any{"lte": *maxPrice},
+			},
+		})
+	}
+
+	return map[string]any{
+		"bool": map[string]any{
+			"filter": must,
+		},
+	}
+}
+
+// buildIsFreeFilter отбирает события, у которых все билеты бесплатны (или наоборот)
+func (qb *QueryBuilder) buildIsFreeFilter(isFree bool) map[string]any {
+	if isFree {
+		return map[string]any{
+			"term": map[string]any{
+				"price_to": 0,
+			},
+		}
 	}
 
 	return map[string]any{
 		"range": map[string]any{
-			"price": rangeQuery,
+			"price_to": map[string]any{"gt": 0},
 		},
 	}
 }
 
+// buildDateRangeFilter отбирает события, пересекающиеся с диапазоном.
+// dateTo включает весь день в своем часовом поясе.
+// Событие без ends_at считается заканчивающимся в момент начала.
+// Серии отбираются по recurrence_until, вхождения разворачиваются после поиска.
 func (qb *QueryBuilder) buildDateRangeFilter(dateFrom, dateTo *time.Time) map[string]any {
-	rangeQuery := map[string]any{}
+	var must []any
 
 	if dateFrom != nil {
-		rangeQuery["gte"] = dateFrom.Format("2006-01-02")
+		from := dateFrom.Format(time.RFC3339)
+		must = append(must, map[string]any{
+			"bool": map[string]any{
+				"should": []any{
+					map[string]any{
+						"range": map[string]any{
+							"ends_at": map[string]any{"gte": from},
+						},
+					},
+					map[string]any{
+						"bool": map[string]any{
+							"must_not": map[string]any{
+								"exists": map[string]any{"field": "ends_at"},
+							},
+							"filter": map[string]any{
+								"range": map[string]any{
+									"starts_at": map[string]any{"gte": from},
+								},
+							},
+						},
+					},
+					map[string]any{
+						"range": map[string]any{
+							"recurrence_until": map[string]any{"gte": from},
+						},
+					},
+					map[string]any{
+						"bool": map[string]any{
+							"filter": map[string]any{
+								"exists": map[string]any{"field": "recurrence_rule"},
+							},
+							"must_not": map[string]any{
+								"exists": map[string]any{"field": "recurrence_until"},
+							},
+						},
+					},
+				},
+				"minimum_should_match": 1,
+			},
+		})
 	}
+
 	if dateTo != nil {
-		rangeQuery["lte"] = dateTo.Format("2006-01-02")
+		must = append(must, map[string]any{
+			"range": map[string]any{
+				"starts_at": map[string]any{
+					"lt": dateTo.AddDate(0, 0, 1).Format(time.RFC3339),
+				},
+			},
+		})
 	}
 
 	return map[string]any{
-		"range": map[string]any{
-			"date": rangeQuery,
+		"bool": map[string]any{
+			"filter": must,
 		},
 	}
 }
@@ -220,48 +511,174 @@ func (qb *QueryBuilder) buildSourceFilter(source string) map[string]any {
 	}
 }
 
-func (qb *QueryBuilder) buildSortQuery(sortBy, sortOrder string) []any {
-	sortField := sortBy
-	if sortOrder == "" {
-		sortOrder = "desc"
+// buildTagsAnyFilter отбирает события хотя бы с одним из тегов
+func (qb *QueryBuilder) buildTagsAnyFilter(slugs []string) map[string]any {
+	return map[string]any{
+		"terms": map[string]any{
+			"tag_slugs": slugs,
+		},
 	}
+}
 
-	// Для текстовых полей используем keyword версию
-	if sortField == "name" || sortField == "location" {
-		sortField += ".keyword"
+// buildTagsAllFilter отбирает события, у которых есть каждый из тегов
+func (qb *QueryBuilder) buildTagsAllFilter(slugs []string) map[string]any {
+	must := make([]any, 0, len(slugs))
+	for _, slug := range slugs {
+		must = append(must, map[string]any{
+			"term": map[string]any{
+				"tag_slugs": slug,
+			},
+		})
 	}
 
-	// Если есть поисковый запрос, сначала сортируем по релевантности
-	sort := []any{
-		map[string]any{
-			sortField: map[string]any{
-				"order": sortOrder,
-			},
+	return map[string]any{
+		"bool": map[string]any{
+			"filter": must,
 		},
 	}
+}
 
-	return sort
+// buildVenuesFilter отбирает события на одной из площадок
+func (qb *QueryBuilder) buildVenuesFilter(venueIDs []int64) map[string]any {
+	return map[string]any{
+		"terms": map[string]any{
+			"venue_id": venueIDs,
+		},
+	}
 }
 
-// Добавляем специальный метод для сортировки с учетом релевантности
-func (qb *QueryBuilder) BuildSearchQueryWithRelevanceSort(filter *Filter) map[string]any {
-	query := qb.BuildSearchQuery(filter)
+// buildCitiesFilter отбирает события в одном из городов площадки
+func (qb *QueryBuilder) buildCitiesFilter(cities []string) map[string]any {
+	return map[string]any{
+		"terms": map[string]any{
+			"city": cities,
+		},
+	}
+}
 
-	// Если есть поисковый запрос, сначала сортируем по score, потом по дате
-	if filter.Query != "" {
-		query["sort"] = []any{
-			map[string]any{
-				"_score": map[string]any{
-					"order": "desc",
-				},
+// buildOrganizersFilter отбирает события одного из организаторов
+func (qb *QueryBuilder) buildOrganizersFilter(organizerIDs []int64) map[string]any {
+	return map[string]any{
+		"terms": map[string]any{
+			"organizer_id": organizerIDs,
+		},
+	}
+}
+
+// buildStatusFilter отбирает события в одном из статусов
+func (qb *QueryBuilder) buildStatusFilter(statuses []string) map[string]any {
+	return map[string]any{
+		"terms": map[string]any{
+			"status": statuses,
+		},
+	}
+}
+
+// buildGeoDistanceFilter отбирает события в радиусе от точки
+func (qb *QueryBuilder) buildGeoDistanceFilter(near *GeoDistance) map[string]any {
+	return map[string]any{
+		"geo_distance": map[string]any{
+			"distance": fmt.Sprintf("%gkm", near.RadiusKm),
+			"geo_location": map[string]any{
+				"lat": near.Lat,
+				"lon": near.Lon,
 			},
-			map[string]any{
-				"created_at": map[string]any{
-					"order": "desc",
+		},
+	}
+}
+
+// buildGeoBoundingBoxFilter отбирает события внутри прямоугольной области
+func (qb *QueryBuilder) buildGeoBoundingBoxFilter(bbox *BoundingBox) map[string]any {
+	return map[string]any{
+		"geo_bounding_box": map[string]any{
+			"geo_location": map[string]any{
+				"top_left": map[string]any{
+					"lat": bbox.TopLeftLat,
+					"lon": bbox.TopLeftLon,
+				},
+				"bottom_right": map[string]any{
+					"lat": bbox.BottomRightLat,
+					"lon": bbox.BottomRightLon,
 				},
 			},
+		},
+	}
+}
+
+// sortFields поля индекса для ключей сортировки db.SortField*.
+// Релевантность и расстояние строятся отдельно.
+var sortFields = map[string]string{
+	db.SortFieldStartsAt:  "starts_at",
+	db.SortFieldPrice:     "price_from",
+	db.SortFieldName:      "name.keyword",
+	db.SortFieldCreatedAt: "created_at",
+}
+
+// buildGeoDistanceSort сортирует по расстоянию от центра near.
+// Значение сортировки в ответе - расстояние в километрах.
+func (qb *QueryBuilder) buildGeoDistanceSort(near *GeoDistance, order string) map[string]any {
+	return map[string]any{
+		"_geo_distance": map[string]any{
+			"geo_location": map[string]any{
+				"lat": near.Lat,
+				"lon": near.Lon,
+			},
+			"order":         order,
+			"unit":          "km",
+			"distance_type": "arc",
+		},
+	}
+}
+
+// buildSortQuery строит сортировку фильтра. Последним ключом идет id в направлении
+// последнего ключа: порядок однозначен, и search_after не пропускает документы.
+// Документы без значения поля идут в конце, как NULLS LAST в PostgreSQL.
+func (qb *QueryBuilder) buildSortQuery(filter *Filter) []any {
+	sorts := filter.GetSort()
+	sort := make([]any, 0, len(sorts)+1)
+
+	order := "asc"
+	for _, key := range sorts {
+		order = "asc"
+		if key.Descending {
+			order = "desc"
+		}
+
+		switch key.Field {
+		case db.SortFieldRelevance:
+			sort = append(sort, map[string]any{"_score": map[string]any{"order": order}})
+		case db.SortFieldDistance:
+			// Без точки поиска расстояния нет, проверяется при разборе запроса
+			if filter.Near != nil {
+				sort = append(sort, qb.buildGeoDistanceSort(filter.Near, order))
+			}
+		default:
+			sort = append(sort, map[string]any{
+				sortFields[key.Field]: map[string]any{
+					"order":   order,
+					"missing": "_last",
+				},
+			})
 		}
 	}
 
-	return query
+	return append(sort, map[string]any{"id": map[string]any{"order": order}})
+}
+
+// ApplyCursor переводит запрос на обход через search_after с курсора filter.After
+// по снимку pitID. Сортировка запроса уже однозначна благодаря id. При открытом снимке
+// индекс указывается в pit, а не в пути запроса.
+func (qb *QueryBuilder) ApplyCursor(query map[string]any, filter *Filter, pitID string) {
+	// search_after несовместим с from
+	delete(query, "from")
+
+	if pitID != "" {
+		query["pit"] = map[string]any{
+			"id":         pitID,
+			"keep_alive": pitKeepAlive,
+		}
+	}
+	if filter.After != nil {
+		query["search_after"] = filter.After.SearchAfter
+	}
 }
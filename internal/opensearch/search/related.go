@@ -0,0 +1,145 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rx3lixir/event-service/internal/opensearch/models"
+)
+
+// Веса подбора по категории и цене
+const (
+	relatedCategoryWeight = 3.0
+	relatedPriceWeight    = 2.0
+	relatedMinPriceScale  = 500.0 // Минимальный масштаб близости цен, в валюте события
+)
+
+// RelatedFilter параметры подбора событий без текстового сходства: по совпадению
+// категории и близости минимальной цены. Запасной вариант для FindSimilarEvents.
+type RelatedFilter struct {
+	// Фильтры выдачи, nil - без фильтров
+	Filter *Filter `json:"filter,omitempty"`
+	// Исключаемые события: исходное и уже подобранные
+	ExcludeIDs []int64 `json:"exclude_ids,omitempty"`
+
+	CategoryID int64    `json:"category_id,omitempty"` // 0 - без веса категории
+	PriceFrom  *float32 `json:"price_from,omitempty"`  // nil - без веса цены
+
+	Size int `json:"size,omitempty"`
+}
+
+// BuildRelatedQuery строит запрос подбора по категории и цене. Оценка - сумма весов
+// совпадения категории и близости цены, при равенстве раньше идут ближайшие события.
+func (qb *QueryBuilder) BuildRelatedQuery(filter *RelatedFilter) map[string]any {
+	boolQuery := map[string]any{
+		"must": []any{map[string]any{"match_all": map[string]any{}}},
+	}
+	if filter.Filter != nil {
+		if filterQueries := qb.buildFilterQueries(filter.Filter, qb.buildFacetFilters(filter.Filter)); len(filterQueries) > 0 {
+			boolQuery["filter"] = filterQueries
+		}
+	}
+	if len(filter.ExcludeIDs) > 0 {
+		boolQuery["must_not"] = map[string]any{
+			"terms": map[string]any{"id": filter.ExcludeIDs},
+		}
+	}
+
+	var functions []any
+	if filter.CategoryID > 0 {
+		functions = append(functions, map[string]any{
+			"filter": map[string]any{"term": map[string]any{"category_id": filter.CategoryID}},
+			"weight": relatedCategoryWeight,
+		})
+	}
+	if filter.PriceFrom != nil {
+		// Близость цены относительна: для дорогих событий разница в сотню незаметна
+		scale := max(float64(*filter.PriceFrom)/2, relatedMinPriceScale)
+		functions = append(functions, map[string]any{
+			"gauss": map[string]any{
+				"price_from": map[string]any{
+					"origin": *filter.PriceFrom,
+					"scale":  scale,
+					"decay":  0.5,
+				},
+			},
+			"weight": relatedPriceWeight,
+		})
+	}
+
+	query := map[string]any{"bool": boolQuery}
+	if len(functions) > 0 {
+		query = map[string]any{
+			"function_score": map[string]any{
+				"query":      query,
+				"functions":  functions,
+				"score_mode": "sum",
+				"boost_mode": "replace",
+			},
+		}
+	}
+
+	return map[string]any{
+		"size":  filter.Size,
+		"query": query,
+		"sort": []any{
+			map[string]any{"_score": map[string]any{"order": "desc"}},
+			map[string]any{"starts_at": map[string]any{"order": "asc", "missing": "_last"}},
+			map[string]any{"id": map[string]any{"order": "asc"}},
+		},
+	}
+}
+
+// FindRelatedEvents подбирает события по совпадению категории и близости цены.
+func (s *Searcher) FindRelatedEvents(ctx context.Context, filter *RelatedFilter) (*models.SearchResult, error) {
+	if filter.Size <= 0 {
+		filter.Size = 20
+	}
+
+	queryBody, err := json.Marshal(s.queryBuilder.BuildRelatedQuery(filter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal related events query: %w", err)
+	}
+
+	start := time.Now()
+	res, err := s.client.GetNativeClient().Search(
+		s.client.GetNativeClient().Search.WithContext(ctx),
+		s.client.GetNativeClient().Search.WithIndex(s.client.GetIndexName()),
+		s.client.GetNativeClient().Search.WithBody(bytes.NewReader(queryBody)),
+	)
+	searchTime := time.Since(start)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute related events search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		s.logger.Error("OpenSearch related events query failed",
+			"status", res.Status(),
+			"error_body", string(body),
+			"query", string(queryBody),
+		)
+		return nil, fmt.Errorf("related events search failed with status: %s", res.Status())
+	}
+
+	result, err := s.parseSearchResponse(res.Body, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse related events response: %w", err)
+	}
+	result.SearchTime = searchTime.String()
+	result.Next = nil
+
+	s.logger.Debug("Related events search completed",
+		"category_id", filter.CategoryID,
+		"found", len(result.Events),
+		"search_time", searchTime,
+	)
+
+	return result, nil
+}
@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"time"
 
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"github.com/rx3lixir/event-service/internal/db"
 	"github.com/rx3lixir/event-service/internal/opensearch/client"
 	"github.com/rx3lixir/event-service/internal/opensearch/models"
 	"github.com/rx3lixir/event-service/pkg/logger"
@@ -32,16 +35,38 @@ func (s *Searcher) SearchEvents(ctx context.Context, filter *Filter) (*models.Se
 		filter = NewFilter()
 	}
 
-	// Используем улучшенный запрос с релевантностью для поисковых запросов
-	var query map[string]any
-	if filter.Query != "" {
-		query = s.queryBuilder.BuildSearchQueryWithRelevanceSort(filter)
-		s.logger.Debug("Using relevance-based search for query", "query", filter.Query)
-	} else {
-		query = s.queryBuilder.BuildSearchQuery(filter)
-		s.logger.Debug("Using standard search without query")
+	// Без явной сортировки поисковый запрос сортируется по релевантности
+	query := s.queryBuilder.BuildSearchQuery(filter)
+	s.logger.Debug("Using search sort",
+		"query", filter.Query,
+		"sort", db.SortSignature(filter.GetSort()),
+	)
+
+	// Снимок индекса открывается со второй страницы обхода, то есть только когда
+	// page_token действительно использован: запросы из одной страницы, а их большинство,
+	// не держат снимков. Дальше снимок передается в курсоре
+	var pitID, openedPitID string
+	if filter.Cursor {
+		if filter.After != nil {
+			pitID = filter.After.PitID
+			if pitID == "" {
+				opened, err := s.openPointInTime(ctx)
+				if err != nil {
+					return nil, err
+				}
+				pitID, openedPitID = opened, opened
+			}
+		}
+		s.queryBuilder.ApplyCursor(query, filter, pitID)
 	}
 
+	// Снимок, открытый для неудавшегося запроса, закрывается сразу, а не по истечении keep_alive
+	defer func() {
+		if openedPitID != "" {
+			s.closePointInTime(ctx, openedPitID)
+		}
+	}()
+
 	// Сериализуем запрос
 	queryBody, err := json.Marshal(query)
 	if err != nil {
@@ -57,12 +82,16 @@ func (s *Searcher) SearchEvents(ctx context.Context, filter *Filter) (*models.Se
 
 	// Выполняем поиск
 	start := time.Now()
-	res, err := s.client.GetNativeClient().Search(
+	opts := []func(*opensearchapi.SearchRequest){
 		s.client.GetNativeClient().Search.WithContext(ctx),
-		s.client.GetNativeClient().Search.WithIndex(s.client.GetIndexName()),
 		s.client.GetNativeClient().Search.WithBody(bytes.NewReader(queryBody)),
 		s.client.GetNativeClient().Search.WithTrackTotalHits(true), // Важно для точного подсчета
-	)
+	}
+	// Запрос по снимку не указывает индекс: он уже задан снимком
+	if pitID == "" {
+		opts = append(opts, s.client.GetNativeClient().Search.WithIndex(s.client.GetIndexName()))
+	}
+	res, err := s.client.GetNativeClient().Search(opts...)
 	searchTime := time.Since(start)
 
 	if err != nil {
@@ -71,6 +100,11 @@ func (s *Searcher) SearchEvents(ctx context.Context, filter *Filter) (*models.Se
 	defer res.Body.Close()
 
 	if res.IsError() {
+		// Снимок закрыт или истек его keep_alive
+		if pitID != "" && res.StatusCode == http.StatusNotFound {
+			return nil, ErrCursorExpired
+		}
+
 		// Читаем тело ошибки для диагностики
 		body, _ := io.ReadAll(res.Body)
 		s.logger.Error("OpenSearch query failed",
@@ -82,13 +116,21 @@ func (s *Searcher) SearchEvents(ctx context.Context, filter *Filter) (*models.Se
 	}
 
 	// Парсим ответ
-	searchResult, err := s.parseSearchResponse(res.Body)
+	searchResult, err := s.parseSearchResponse(res.Body, filter.sortIndex(db.SortFieldDistance))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse search response: %w", err)
 	}
 
 	searchResult.SearchTime = searchTime.String()
 
+	if filter.Cursor {
+		// Дальше снимком распоряжается курсор
+		openedPitID = ""
+		searchResult.Next = s.nextCursor(ctx, filter, pitID, searchResult)
+	} else {
+		searchResult.Next = nil
+	}
+
 	// Логируем результаты с деталями
 	s.logger.Info("Search completed",
 		"query", filter.Query,
@@ -112,9 +154,63 @@ func (s *Searcher) SearchEvents(ctx context.Context, filter *Filter) (*models.Se
 	return searchResult, nil
 }
 
-func (s *Searcher) parseSearchResponse(body io.Reader) (*models.SearchResult, error) {
+// FindSimilarEvents ищет события, похожие на filter.EventID, через more_like_this.
+// Результаты отсортированы по убыванию похожести.
+func (s *Searcher) FindSimilarEvents(ctx context.Context, filter *SimilarFilter) (*models.SearchResult, error) {
+	if filter.Size <= 0 {
+		filter.Size = 20
+	}
+
+	queryBody, err := json.Marshal(s.queryBuilder.BuildMoreLikeThisQuery(s.client.GetIndexName(), filter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal more_like_this query: %w", err)
+	}
+
+	start := time.Now()
+	res, err := s.client.GetNativeClient().Search(
+		s.client.GetNativeClient().Search.WithContext(ctx),
+		s.client.GetNativeClient().Search.WithIndex(s.client.GetIndexName()),
+		s.client.GetNativeClient().Search.WithBody(bytes.NewReader(queryBody)),
+	)
+	searchTime := time.Since(start)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute more_like_this search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		s.logger.Error("OpenSearch more_like_this query failed",
+			"status", res.Status(),
+			"error_body", string(body),
+			"event_id", filter.EventID,
+		)
+		return nil, fmt.Errorf("more_like_this search failed with status: %s", res.Status())
+	}
+
+	result, err := s.parseSearchResponse(res.Body, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse more_like_this response: %w", err)
+	}
+	result.SearchTime = searchTime.String()
+
+	s.logger.Debug("Similar events search completed",
+		"event_id", filter.EventID,
+		"found", len(result.Events),
+		"search_time", searchTime,
+	)
+
+	return result, nil
+}
+
+// parseSearchResponse разбирает ответ поиска.
+// distanceIndex - позиция расстояния в км среди значений sort каждого hit, -1 если его нет.
+func (s *Searcher) parseSearchResponse(body io.Reader, distanceIndex int) (*models.SearchResult, error) {
 	var response struct {
-		Hits struct {
+		PitID        string                      `json:"pit_id"`
+		Aggregations map[string]facetAggregation `json:"aggregations"`
+		Hits         struct {
 			Total struct {
 				Value int64 `json:"value"`
 			} `json:"total"`
@@ -122,6 +218,7 @@ func (s *Searcher) parseSearchResponse(body io.Reader) (*models.SearchResult, er
 			Hits     []struct {
 				Source models.EventDocument `json:"_source"`
 				Score  *float64             `json:"_score"`
+				Sort   []any                `json:"sort"`
 			} `json:"hits"`
 		} `json:"hits"`
 	}
@@ -145,6 +242,11 @@ func (s *Searcher) parseSearchResponse(body io.Reader) (*models.SearchResult, er
 	for _, hit := range response.Hits.Hits {
 		// Сохраняем score в событии для отладки
 		event := hit.Source
+		if distanceIndex >= 0 && len(hit.Sort) > distanceIndex {
+			if distance, ok := hit.Sort[distanceIndex].(float64); ok {
+				event.DistanceKm = &distance
+			}
+		}
 		events = append(events, &event)
 
 		// Логируем score для отладки
@@ -157,11 +259,22 @@ func (s *Searcher) parseSearchResponse(body io.Reader) (*models.SearchResult, er
 		}
 	}
 
-	return &models.SearchResult{
+	result := &models.SearchResult{
 		Events:   events,
 		Total:    response.Hits.Total.Value,
 		MaxScore: response.Hits.MaxScore,
-	}, nil
+		Facets:   parseFacets(response.Aggregations),
+	}
+
+	// Значения сортировки последнего документа - позиция для search_after
+	if n := len(response.Hits.Hits); n > 0 && len(response.Hits.Hits[n-1].Sort) > 0 {
+		result.Next = &models.SearchCursor{
+			PitID:       response.PitID,
+			SearchAfter: response.Hits.Hits[n-1].Sort,
+		}
+	}
+
+	return result, nil
 }
 
 // Вспомогательный метод для получения score события (для отладки)
@@ -181,6 +294,7 @@ func (s *Searcher) CountEvents(ctx context.Context, filter *Filter) (int64, erro
 	countFilter := *filter
 	countFilter.From = 0
 	countFilter.Size = 0
+	countFilter.Facets = nil
 
 	query := s.queryBuilder.BuildSearchQuery(&countFilter)
 
@@ -55,19 +55,11 @@ func (s *Service) Initialize(ctx context.Context) error {
 	return nil
 }
 
-// RecreateIndex пересоздает индекс (удаляет и создает заново)
+// RecreateIndex пересоздает индекс (удаляет и создает заново с актуальным маппингом).
+// Нужен после изменения маппинга, например при добавлении новых полей.
 func (s *Service) RecreateIndex(ctx context.Context) error {
-	s.logger.Info("Recreating OpenSearch index", "index", s.client.GetIndexName())
-
-	// Удаляем существующий индекс
-	if err := s.client.GetNativeClient().Indices.Delete.WithContext(ctx); err != nil {
-		// Игнорируем ошибку если индекс не существует
-		s.logger.Debug("Index deletion result", "error", err)
-	}
-
-	// Создаем новый индекс
-	if err := s.client.GetNativeClient().Indices.Create.WithContext(ctx); err != nil {
-		return fmt.Errorf("failed to create index after recreation: %w", err)
+	if err := s.mapper.RecreateIndex(ctx); err != nil {
+		return fmt.Errorf("failed to recreate index: %w", err)
 	}
 
 	s.logger.Info("Index recreated successfully", "index", s.client.GetIndexName())
@@ -79,6 +71,21 @@ func (s *Service) SearchEvents(ctx context.Context, filter *search.Filter) (*mod
 	return s.searcher.SearchEvents(ctx, filter)
 }
 
+// FindSimilarEvents ищет события, похожие на filter.EventID, через more_like_this
+func (s *Service) FindSimilarEvents(ctx context.Context, filter *search.SimilarFilter) (*models.SearchResult, error) {
+	return s.searcher.FindSimilarEvents(ctx, filter)
+}
+
+// FindRelatedEvents подбирает события по категории и цене, запасной вариант FindSimilarEvents
+func (s *Service) FindRelatedEvents(ctx context.Context, filter *search.RelatedFilter) (*models.SearchResult, error) {
+	return s.searcher.FindRelatedEvents(ctx, filter)
+}
+
+// EventCalendar считает события фильтра по дням с подборкой на каждый день
+func (s *Service) EventCalendar(ctx context.Context, filter *search.Filter, req *search.CalendarRequest) (*models.CalendarResult, error) {
+	return s.searcher.EventCalendar(ctx, filter, req)
+}
+
 // Операции индексации
 func (s *Service) IndexEvent(ctx context.Context, event *db.Event) error {
 	return s.indexer.IndexEvent(ctx, event)
@@ -96,6 +103,11 @@ func (s *Service) BulkIndexEvents(ctx context.Context, events []*db.Event) error
 	return s.indexer.BulkIndexEvents(ctx, events)
 }
 
+// BulkDeleteEvents удаляет документы событий через bulk API.
+func (s *Service) BulkDeleteEvents(ctx context.Context, eventIDs []int64) error {
+	return s.indexer.BulkDeleteEvents(ctx, eventIDs)
+}
+
 // Suggestions
 func (s *Service) GetSuggestions(ctx context.Context, req *suggestions.Request) (*suggestions.Response, error) {
 	return s.suggester.GetSuggestions(ctx, req)
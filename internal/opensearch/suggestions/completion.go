@@ -14,7 +14,7 @@ func (qb *QueryBuilder) BuildSuggestionQuery(req *Request) map[string]any {
 		suggest[field+"_suggestion"] = map[string]any{
 			"prefix": req.Query,
 			"completion": map[string]any{
-				"field":           field + ".completion",
+				"field":           indexField(field) + ".completion",
 				"size":            req.MaxResults,
 				"skip_duplicates": true,
 				"fuzzy": map[string]any{
@@ -44,7 +44,7 @@ func (qb *QueryBuilder) BuildSuggestionQuery(req *Request) map[string]any {
 func (qb *QueryBuilder) buildFallbackQuery(req *Request) map[string]any {
 	fields := make([]string, len(req.Fields))
 	for i, field := range req.Fields {
-		fields[i] = field + ".suggest"
+		fields[i] = indexField(field) + ".suggest"
 	}
 
 	return map[string]any{
@@ -63,7 +63,7 @@ func (qb *QueryBuilder) buildFallbackAggregation(req *Request) map[string]any {
 	for _, field := range req.Fields {
 		aggs[field+"_terms"] = map[string]any{
 			"terms": map[string]any{
-				"field": field + ".keyword",
+				"field": indexField(field) + ".keyword",
 				"size":  req.MaxResults,
 				"order": map[string]any{
 					"_count": "desc",
@@ -29,17 +29,12 @@ func (r *Request) Validate() error {
 	}
 
 	if len(r.Fields) == 0 {
-		r.Fields = []string{"name", "location"}
+		r.Fields = []string{"name", "location", "tags", "organizer"}
 	}
 
 	// Валидируем поля
-	validFields := map[string]bool{
-		"name":     true,
-		"location": true,
-	}
-
 	for _, field := range r.Fields {
-		if !validFields[field] {
+		if _, ok := indexFields[field]; !ok {
 			return fmt.Errorf("invalid field: %s", field)
 		}
 	}
@@ -47,6 +42,22 @@ func (r *Request) Validate() error {
 	return nil
 }
 
+// indexFields поля документа в индексе для полей подсказок
+var indexFields = map[string]string{
+	"name":      "name",
+	"location":  "location",
+	"tags":      "tags",
+	"organizer": "organizer_name",
+}
+
+// indexField возвращает поле документа в индексе для поля подсказок
+func indexField(field string) string {
+	if indexed, ok := indexFields[field]; ok {
+		return indexed
+	}
+	return field
+}
+
 type Response struct {
 	Suggestions []Suggestion `json:"suggestions"`
 	Query       string       `json:"query"`
@@ -64,9 +75,11 @@ type Suggestion struct {
 type SuggestionType string
 
 const (
-	SuggestionTypeEvent    SuggestionType = "event"
-	SuggestionTypeLocation SuggestionType = "location"
-	SuggestionTypeGeneral  SuggestionType = "general"
+	SuggestionTypeEvent     SuggestionType = "event"
+	SuggestionTypeLocation  SuggestionType = "location"
+	SuggestionTypeTag       SuggestionType = "tag"
+	SuggestionTypeOrganizer SuggestionType = "organizer"
+	SuggestionTypeGeneral   SuggestionType = "general"
 )
 
 func (s *Suggestion) SetType(field string) {
@@ -75,6 +88,10 @@ func (s *Suggestion) SetType(field string) {
 		s.Type = string(SuggestionTypeEvent)
 	case "location":
 		s.Type = string(SuggestionTypeLocation)
+	case "tags":
+		s.Type = string(SuggestionTypeTag)
+	case "organizer":
+		s.Type = string(SuggestionTypeOrganizer)
 	default:
 		s.Type = string(SuggestionTypeGeneral)
 	}
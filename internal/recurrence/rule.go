@@ -0,0 +1,215 @@
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency частота повторения RRULE (FREQ)
+type Frequency int
+
+const (
+	Daily Frequency = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+// WeekdayNum день недели с опциональным порядковым номером (BYDAY=MO, 1FR, -1SU).
+// N = 0 означает "каждый такой день" в периоде.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule разобранное правило повторения RFC 5545.
+// Поддерживается подмножество: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL,
+// COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH, WKST.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var frequencies = map[string]Frequency{
+	"DAILY":   Daily,
+	"WEEKLY":  Weekly,
+	"MONTHLY": Monthly,
+	"YEARLY":  Yearly,
+}
+
+// Parse разбирает строку RRULE (с префиксом "RRULE:" или без).
+// loc используется для UNTIL без явной зоны (floating time и даты).
+func Parse(value string, loc *time.Location) (*Rule, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("empty recurrence rule")
+	}
+
+	rule := &Rule{
+		Interval:  1,
+		WeekStart: time.Monday,
+	}
+	hasFreq := false
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("malformed rule part: %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			freq, ok := frequencies[strings.ToUpper(val)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported FREQ: %s", val)
+			}
+			rule.Freq = freq
+			hasFreq = true
+
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval <= 0 {
+				return nil, fmt.Errorf("invalid INTERVAL: %s", val)
+			}
+			rule.Interval = interval
+
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count <= 0 {
+				return nil, fmt.Errorf("invalid COUNT: %s", val)
+			}
+			rule.Count = count
+
+		case "UNTIL":
+			until, err := parseUntil(val, loc)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+
+		case "BYDAY":
+			for _, item := range strings.Split(val, ",") {
+				wd, err := parseWeekdayNum(item)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+
+		case "BYMONTHDAY":
+			for _, item := range strings.Split(val, ",") {
+				day, err := strconv.Atoi(item)
+				if err != nil || day == 0 || day < -31 || day > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY: %s", item)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, day)
+			}
+
+		case "BYMONTH":
+			for _, item := range strings.Split(val, ",") {
+				month, err := strconv.Atoi(item)
+				if err != nil || month < 1 || month > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH: %s", item)
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+
+		case "WKST":
+			day, ok := weekdays[strings.ToUpper(val)]
+			if !ok {
+				return nil, fmt.Errorf("invalid WKST: %s", val)
+			}
+			rule.WeekStart = day
+
+		default:
+			return nil, fmt.Errorf("unsupported rule part: %s", key)
+		}
+	}
+
+	if !hasFreq {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot be used together")
+	}
+
+	if rule.Freq == Daily || rule.Freq == Weekly {
+		for _, wd := range rule.ByDay {
+			if wd.N != 0 {
+				return nil, fmt.Errorf("numbered BYDAY is not allowed with FREQ=DAILY or WEEKLY")
+			}
+		}
+	}
+
+	return rule, nil
+}
+
+// IsFinite сообщает, ограничено ли правило COUNT или UNTIL
+func (r *Rule) IsFinite() bool {
+	return r.Count > 0 || r.Until != nil
+}
+
+func parseWeekdayNum(value string) (WeekdayNum, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY: %s", value)
+	}
+
+	day, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY: %s", value)
+	}
+
+	wd := WeekdayNum{Day: day}
+	if prefix := value[:len(value)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY: %s", value)
+		}
+		wd.N = n
+	}
+
+	return wd, nil
+}
+
+// parseUntil поддерживает форматы UNTIL: 20060102T150405Z, 20060102T150405 и 20060102.
+// Дата без времени означает конец этого дня в часовом поясе события.
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid UNTIL: %s", value)
+}
@@ -0,0 +1,68 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+		check   func(t *testing.T, rule *Rule)
+	}{
+		{
+			name:  "prefix and defaults",
+			value: "RRULE:FREQ=WEEKLY",
+			check: func(t *testing.T, rule *Rule) {
+				if rule.Freq != Weekly || rule.Interval != 1 || rule.WeekStart != time.Monday {
+					t.Errorf("got %+v", rule)
+				}
+			},
+		},
+		{
+			name:  "ordinal BYDAY",
+			value: "FREQ=MONTHLY;BYDAY=-1FR,2MO",
+			check: func(t *testing.T, rule *Rule) {
+				want := []WeekdayNum{{N: -1, Day: time.Friday}, {N: 2, Day: time.Monday}}
+				if len(rule.ByDay) != len(want) || rule.ByDay[0] != want[0] || rule.ByDay[1] != want[1] {
+					t.Errorf("ByDay = %+v, want %+v", rule.ByDay, want)
+				}
+			},
+		},
+		{
+			name:  "date-only UNTIL is the end of the day",
+			value: "FREQ=DAILY;UNTIL=20260103",
+			check: func(t *testing.T, rule *Rule) {
+				want := time.Date(2026, 1, 3, 23, 59, 59, 0, time.UTC)
+				if rule.Until == nil || !rule.Until.Equal(want) {
+					t.Errorf("Until = %v, want %v", rule.Until, want)
+				}
+			},
+		},
+		{name: "missing FREQ", value: "COUNT=3", wantErr: true},
+		{name: "unsupported FREQ", value: "FREQ=HOURLY", wantErr: true},
+		{name: "COUNT with UNTIL", value: "FREQ=DAILY;COUNT=2;UNTIL=20260101", wantErr: true},
+		{name: "ordinal BYDAY with WEEKLY", value: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{name: "BYMONTHDAY out of range", value: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{name: "zero INTERVAL", value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "unsupported part", value: "FREQ=DAILY;BYHOUR=10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.value, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) succeeded, want error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.value, err)
+			}
+			tt.check(t, rule)
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package recurrence
+
+import (
+	"sort"
+	"time"
+)
+
+// maxPeriods ограничивает количество перебираемых периодов правила,
+// чтобы бесконечные или "пустые" правила (например, 30 февраля) не зацикливались.
+const maxPeriods = 20000
+
+// Set описывает серию повторений: правило, первое вхождение (DTSTART) и исключения (EXDATE).
+// Вхождения вычисляются в часовом поясе Start, поэтому локальное время сохраняется при переходе на летнее время.
+type Set struct {
+	Rule    *Rule
+	Start   time.Time
+	ExDates []time.Time
+}
+
+// NewSet создает серию с началом start, переведенным в часовой пояс loc.
+func NewSet(rule *Rule, start time.Time, loc *time.Location, exDates []time.Time) *Set {
+	return &Set{
+		Rule:    rule,
+		Start:   start.In(loc),
+		ExDates: exDates,
+	}
+}
+
+// Between возвращает начала вхождений в полуинтервале [from, to).
+// Нулевой to означает отсутствие верхней границы, limit > 0 ограничивает количество результатов.
+func (s *Set) Between(from, to time.Time, limit int) []time.Time {
+	var result []time.Time
+
+	s.iterate(from, to, func(t time.Time) bool {
+		if !to.IsZero() && !t.Before(to) {
+			return false
+		}
+		if t.Before(from) || s.isExcluded(t) {
+			return true
+		}
+
+		result = append(result, t)
+		return limit <= 0 || len(result) < limit
+	})
+
+	return result
+}
+
+// Includes проверяет, является ли t началом одного из вхождений серии.
+func (s *Set) Includes(t time.Time) bool {
+	occurrences := s.Between(t, t.Add(time.Second), 1)
+	return len(occurrences) == 1 && occurrences[0].Equal(t)
+}
+
+// Last возвращает начало последнего вхождения конечной серии.
+// Для бесконечного правила возвращает false.
+func (s *Set) Last() (time.Time, bool) {
+	if !s.Rule.IsFinite() {
+		return time.Time{}, false
+	}
+
+	var last time.Time
+	found := false
+
+	s.iterate(time.Time{}, time.Time{}, func(t time.Time) bool {
+		if !s.isExcluded(t) {
+			last = t
+			found = true
+		}
+		return true
+	})
+
+	return last, found
+}
+
+func (s *Set) isExcluded(t time.Time) bool {
+	for _, ex := range s.ExDates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// iterate перебирает вхождения по порядку до тех пор, пока fn возвращает true.
+// DTSTART всегда первое вхождение, даже если не подходит под правило (RFC 5545).
+// COUNT учитывается до исключения EXDATE, как того требует RFC 5545, поэтому серия
+// с COUNT перебирается с начала. Бесконечная или ограниченная UNTIL серия перебирается
+// с периода, содержащего from: вхождения до него не нужны.
+func (s *Set) iterate(from, to time.Time, fn func(time.Time) bool) {
+	first := 0
+	if s.Rule.Count == 0 && !from.IsZero() {
+		first = s.periodAt(from)
+	}
+
+	count := 0
+	if first == 0 {
+		count++
+		if !fn(s.Start) {
+			return
+		}
+		if s.Rule.Count > 0 && count >= s.Rule.Count {
+			return
+		}
+	}
+
+	for k := first; k < first+maxPeriods; k++ {
+		periodStart, candidates := s.candidates(k)
+		if !to.IsZero() && !periodStart.Before(to) {
+			return
+		}
+
+		for _, c := range candidates {
+			// DTSTART уже учтен
+			if !c.After(s.Start) {
+				continue
+			}
+			if s.Rule.Until != nil && c.After(*s.Rule.Until) {
+				return
+			}
+
+			count++
+			if !fn(c) {
+				return
+			}
+			if s.Rule.Count > 0 && count >= s.Rule.Count {
+				return
+			}
+		}
+	}
+}
+
+// periodAt возвращает номер периода правила, с которого можно начинать перебор вхождений
+// не раньше t. Номер берется с запасом в один период: вхождения периодов до него раньше t.
+func (s *Set) periodAt(t time.Time) int {
+	r := s.Rule
+	t = t.In(s.Start.Location())
+
+	var periods int
+	switch r.Freq {
+	case Daily:
+		periods = daysBetween(s.Start, t) / r.Interval
+	case Weekly:
+		offset := (int(s.Start.Weekday()) - int(r.WeekStart) + 7) % 7
+		periods = (daysBetween(s.Start, t) + offset) / 7 / r.Interval
+	case Monthly:
+		periods = ((t.Year()-s.Start.Year())*12 + int(t.Month()) - int(s.Start.Month())) / r.Interval
+	case Yearly:
+		periods = (t.Year() - s.Start.Year()) / r.Interval
+	}
+
+	return max(periods-1, 0)
+}
+
+// daysBetween возвращает количество календарных дней от даты a до даты b
+// без учета времени суток и переходов на летнее время.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	days := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC).Sub(time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC))
+	return int(days.Hours() / 24)
+}
+
+// candidates возвращает начало k-го периода правила и отсортированные кандидаты в нем.
+func (s *Set) candidates(k int) (time.Time, []time.Time) {
+	r := s.Rule
+	loc := s.Start.Location()
+	y, mo, d := s.Start.Date()
+	h, mi, sec := s.Start.Clock()
+
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, h, mi, sec, 0, loc)
+	}
+
+	var periodStart time.Time
+	var result []time.Time
+
+	switch r.Freq {
+	case Daily:
+		periodStart = time.Date(y, mo, d+k*r.Interval, 0, 0, 0, 0, loc)
+		c := at(periodStart.Date())
+		if r.matchesMonth(c) && r.matchesMonthDay(c) && r.matchesWeekday(c) {
+			result = append(result, c)
+		}
+
+	case Weekly:
+		offset := (int(s.Start.Weekday()) - int(r.WeekStart) + 7) % 7
+		periodStart = time.Date(y, mo, d-offset+7*k*r.Interval, 0, 0, 0, 0, loc)
+
+		days := []time.Weekday{s.Start.Weekday()}
+		if len(r.ByDay) > 0 {
+			days = days[:0]
+			for _, wd := range r.ByDay {
+				days = append(days, wd.Day)
+			}
+		}
+
+		for _, wd := range days {
+			delta := (int(wd) - int(r.WeekStart) + 7) % 7
+			c := at(periodStart.AddDate(0, 0, delta).Date())
+			if r.matchesMonth(c) {
+				result = append(result, c)
+			}
+		}
+
+	case Monthly:
+		periodStart = time.Date(y, mo+time.Month(k*r.Interval), 1, 0, 0, 0, 0, loc)
+		if r.matchesMonth(periodStart) {
+			for _, day := range r.monthDays(periodStart.Year(), periodStart.Month(), d) {
+				result = append(result, at(periodStart.Year(), periodStart.Month(), day))
+			}
+		}
+
+	case Yearly:
+		year := y + k*r.Interval
+		periodStart = time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+
+		if len(r.ByDay) > 0 && len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 {
+			for _, day := range r.yearWeekdays(year, loc) {
+				result = append(result, at(day.Date()))
+			}
+			break
+		}
+
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{mo}
+		}
+		for _, month := range months {
+			for _, day := range r.monthDays(year, month, d) {
+				result = append(result, at(year, month, day))
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+
+	return periodStart, dedupe(result)
+}
+
+// monthDays возвращает дни месяца, подходящие под BYMONTHDAY/BYDAY.
+// Без этих частей используется день месяца из DTSTART (месяцы без такого дня пропускаются).
+func (r *Rule) monthDays(year int, month time.Month, startDay int) []int {
+	lastDay := daysIn(year, month)
+	var days []int
+
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, md := range r.ByMonthDay {
+			day := md
+			if md < 0 {
+				day = lastDay + md + 1
+			}
+			if day < 1 || day > lastDay {
+				continue
+			}
+			if len(r.ByDay) > 0 && !r.matchesWeekday(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)) {
+				continue
+			}
+			days = append(days, day)
+		}
+
+	case len(r.ByDay) > 0:
+		for _, wd := range r.ByDay {
+			var matches []int
+			for day := 1; day <= lastDay; day++ {
+				if time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() == wd.Day {
+					matches = append(matches, day)
+				}
+			}
+			days = append(days, pickOrdinal(matches, wd.N)...)
+		}
+
+	default:
+		if startDay <= lastDay {
+			days = append(days, startDay)
+		}
+	}
+
+	return days
+}
+
+// yearWeekdays возвращает дни года для BYDAY без BYMONTH (порядковый номер считается в пределах года).
+func (r *Rule) yearWeekdays(year int, loc *time.Location) []time.Time {
+	var days []time.Time
+
+	for _, wd := range r.ByDay {
+		var matches []time.Time
+		for day := time.Date(year, time.January, 1, 0, 0, 0, 0, loc); day.Year() == year; day = day.AddDate(0, 0, 1) {
+			if day.Weekday() == wd.Day {
+				matches = append(matches, day)
+			}
+		}
+
+		switch {
+		case wd.N == 0:
+			days = append(days, matches...)
+		case wd.N > 0 && wd.N <= len(matches):
+			days = append(days, matches[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(matches):
+			days = append(days, matches[len(matches)+wd.N])
+		}
+	}
+
+	return days
+}
+
+func (r *Rule) matchesMonth(t time.Time) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if t.Month() == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	lastDay := daysIn(t.Year(), t.Month())
+	for _, md := range r.ByMonthDay {
+		if md == t.Day() || (md < 0 && lastDay+md+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if t.Weekday() == wd.Day {
+			return true
+		}
+	}
+	return false
+}
+
+func pickOrdinal(matches []int, n int) []int {
+	switch {
+	case n == 0:
+		return matches
+	case n > 0 && n <= len(matches):
+		return []int{matches[n-1]}
+	case n < 0 && -n <= len(matches):
+		return []int{matches[len(matches)+n]}
+	}
+	return nil
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func dedupe(times []time.Time) []time.Time {
+	if len(times) < 2 {
+		return times
+	}
+
+	result := times[:1]
+	for _, t := range times[1:] {
+		if !t.Equal(result[len(result)-1]) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,229 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s is not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestSetBetween(t *testing.T) {
+	utc := time.UTC
+	date := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, utc)
+	}
+
+	tests := []struct {
+		name    string
+		rule    string
+		start   time.Time
+		exDates []time.Time
+		from    time.Time
+		to      time.Time
+		want    []time.Time
+	}{
+		{
+			name:    "COUNT counts excluded occurrences",
+			rule:    "FREQ=DAILY;COUNT=4",
+			start:   date(2026, 1, 1, 10, 0),
+			exDates: []time.Time{date(2026, 1, 2, 10, 0)},
+			want: []time.Time{
+				date(2026, 1, 1, 10, 0),
+				date(2026, 1, 3, 10, 0),
+				date(2026, 1, 4, 10, 0),
+			},
+		},
+		{
+			name:  "date-only UNTIL includes the whole day",
+			rule:  "FREQ=DAILY;UNTIL=20260103",
+			start: date(2026, 1, 1, 23, 0),
+			want: []time.Time{
+				date(2026, 1, 1, 23, 0),
+				date(2026, 1, 2, 23, 0),
+				date(2026, 1, 3, 23, 0),
+			},
+		},
+		{
+			name:  "last Friday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			start: date(2026, 1, 30, 19, 0),
+			want: []time.Time{
+				date(2026, 1, 30, 19, 0),
+				date(2026, 2, 27, 19, 0),
+				date(2026, 3, 27, 19, 0),
+			},
+		},
+		{
+			name:  "second Monday of the month",
+			rule:  "FREQ=MONTHLY;BYDAY=2MO;COUNT=3",
+			start: date(2026, 1, 12, 9, 0),
+			want: []time.Time{
+				date(2026, 1, 12, 9, 0),
+				date(2026, 2, 9, 9, 0),
+				date(2026, 3, 9, 9, 0),
+			},
+		},
+		{
+			name:  "BYMONTHDAY=31 skips short months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=4",
+			start: date(2026, 1, 31, 12, 0),
+			want: []time.Time{
+				date(2026, 1, 31, 12, 0),
+				date(2026, 3, 31, 12, 0),
+				date(2026, 5, 31, 12, 0),
+				date(2026, 7, 31, 12, 0),
+			},
+		},
+		{
+			name:  "DTSTART not matching BYDAY is the first occurrence",
+			rule:  "FREQ=WEEKLY;BYDAY=MO;COUNT=3",
+			start: date(2026, 1, 7, 18, 0), // среда
+			want: []time.Time{
+				date(2026, 1, 7, 18, 0),
+				date(2026, 1, 12, 18, 0),
+				date(2026, 1, 19, 18, 0),
+			},
+		},
+		{
+			name:  "window far from DTSTART",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH",
+			start: date(2000, 1, 4, 8, 0),
+			from:  date(2026, 1, 1, 0, 0),
+			to:    date(2026, 1, 15, 0, 0),
+			want: []time.Time{
+				date(2026, 1, 1, 8, 0),
+				date(2026, 1, 13, 8, 0),
+			},
+		},
+		{
+			name:  "window starting mid-series of a monthly rule",
+			rule:  "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=15",
+			start: date(2010, 2, 15, 12, 0),
+			from:  date(2026, 4, 1, 0, 0),
+			to:    date(2026, 12, 1, 0, 0),
+			want: []time.Time{
+				date(2026, 5, 15, 12, 0),
+				date(2026, 8, 15, 12, 0),
+				date(2026, 11, 15, 12, 0),
+			},
+		},
+		{
+			name:  "yearly rule after many years",
+			rule:  "FREQ=YEARLY",
+			start: date(1990, 6, 1, 20, 0),
+			from:  date(2025, 1, 1, 0, 0),
+			to:    date(2027, 1, 1, 0, 0),
+			want: []time.Time{
+				date(2025, 6, 1, 20, 0),
+				date(2026, 6, 1, 20, 0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule, utc)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+
+			got := NewSet(rule, tt.start, utc, tt.exDates).Between(tt.from, tt.to, 0)
+			assertTimes(t, got, tt.want)
+		})
+	}
+}
+
+func TestSetBetweenKeepsLocalTimeAcrossDST(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	rule, err := Parse("FREQ=WEEKLY;COUNT=3", berlin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Переход на летнее время 29 марта 2026
+	start := time.Date(2026, 3, 22, 10, 0, 0, 0, berlin)
+	got := NewSet(rule, start, berlin, nil).Between(time.Time{}, time.Time{}, 0)
+
+	assertTimes(t, got, []time.Time{
+		time.Date(2026, 3, 22, 9, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 29, 8, 0, 0, 0, time.UTC),
+		time.Date(2026, 4, 5, 8, 0, 0, 0, time.UTC),
+	})
+	for _, occurrence := range got {
+		if hour := occurrence.In(berlin).Hour(); hour != 10 {
+			t.Errorf("occurrence %v: local hour = %d, want 10", occurrence, hour)
+		}
+	}
+}
+
+func TestSetIncludes(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;INTERVAL=3", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2001, 1, 1, 7, 30, 0, 0, time.UTC)
+	set := NewSet(rule, start, time.UTC, nil)
+
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{start, true},
+		{time.Date(2026, 1, 1, 7, 30, 0, 0, time.UTC), false}, // 9131 день от начала
+		{time.Date(2026, 1, 2, 7, 30, 0, 0, time.UTC), true},  // 9132 дня от начала
+		{time.Date(2001, 1, 4, 7, 30, 0, 0, time.UTC), true},
+		{time.Date(2001, 1, 4, 7, 31, 0, 0, time.UTC), false},
+		{time.Date(2001, 1, 5, 7, 30, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		if got := set.Includes(tt.t); got != tt.want {
+			t.Errorf("Includes(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestSetLast(t *testing.T) {
+	rule, err := Parse("FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+	exDates := []time.Time{time.Date(2026, 5, 31, 12, 0, 0, 0, time.UTC)}
+
+	last, ok := NewSet(rule, start, time.UTC, exDates).Last()
+	if !ok {
+		t.Fatal("Last() of a finite rule returned false")
+	}
+	if want := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC); !last.Equal(want) {
+		t.Errorf("Last() = %v, want %v", last, want)
+	}
+
+	infinite, err := Parse("FREQ=DAILY", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := NewSet(infinite, start, time.UTC, nil).Last(); ok {
+		t.Error("Last() of an infinite rule returned true")
+	}
+}
+
+func assertTimes(t *testing.T, got, want []time.Time) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d occurrences %v, want %d %v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("occurrence %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
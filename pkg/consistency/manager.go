@@ -13,6 +13,9 @@ import (
 	"github.com/rx3lixir/event-service/pkg/logger"
 )
 
+// searchPageSize размер страницы при чтении всех документов OpenSearch
+const searchPageSize = 1000
+
 // Manager отвечает за проверку консистентности данных
 // между PostgreSQL и OpenSearch
 type Manager struct {
@@ -78,12 +81,11 @@ func (m *Manager) CheckConsistency(ctx context.Context) (*CheckResult, error) {
 	result.TotalEventsDB = len(dbEvents)
 
 	// Получаем все события из OpenSearch
-	filter := search.NewFilter().WithPagination(0, 10000)
-	osResult, err := m.osService.SearchEvents(ctx, filter)
+	osEvents, err := m.searchAllEvents(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events from opensearch: %w", err)
 	}
-	result.TotalEventsOS = int(osResult.Total)
+	result.TotalEventsOS = len(osEvents)
 
 	// Создаем мапы для быстрого поиска
 	dbEventsMap := make(map[int64]*db.Event)
@@ -93,7 +95,7 @@ func (m *Manager) CheckConsistency(ctx context.Context) (*CheckResult, error) {
 
 	// Создаем мапы для быстрого поиска
 	osEventsMap := make(map[int64]*models.EventDocument)
-	for _, doc := range osResult.Events {
+	for _, doc := range osEvents {
 		osEventsMap[doc.ID] = doc
 	}
 
@@ -142,6 +144,30 @@ func (m *Manager) CheckConsistency(ctx context.Context) (*CheckResult, error) {
 	return result, nil
 }
 
+// searchAllEvents читает все документы индекса постранично через search_after:
+// выдача через from/size ограничена index.max_result_window (10000 документов).
+func (m *Manager) searchAllEvents(ctx context.Context) ([]*models.EventDocument, error) {
+	var docs []*models.EventDocument
+	var after *models.SearchCursor
+
+	for {
+		filter := search.NewFilter().
+			WithPagination(0, searchPageSize).
+			WithCursor(after)
+
+		page, err := m.osService.SearchEvents(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, page.Events...)
+
+		if page.Next == nil {
+			return docs, nil
+		}
+		after = page.Next
+	}
+}
+
 // CheckEventConsistency проверяет консистентность конкретного события
 func (m *Manager) CheckEventConsistency(ctx context.Context, eventID int64) (*CheckResult, error) {
 	m.log.Debug("checking consistency for single event", "event_id", eventID)
@@ -322,6 +348,24 @@ func (m *Manager) compareEvent(dbEvent *db.Event, osDoc *models.EventDocument) [
 		})
 	}
 
+	if !equalTimes(dbEvent.StartsAt, osDoc.StartsAt) {
+		mismatches = append(mismatches, EventMismatch{
+			EventID: dbEvent.Id,
+			Field:   "starts_at",
+			DBValue: formatTime(dbEvent.StartsAt),
+			OSValue: formatTime(osDoc.StartsAt),
+		})
+	}
+
+	if !equalTimes(dbEvent.EndsAt, osDoc.EndsAt) {
+		mismatches = append(mismatches, EventMismatch{
+			EventID: dbEvent.Id,
+			Field:   "ends_at",
+			DBValue: formatTime(dbEvent.EndsAt),
+			OSValue: formatTime(osDoc.EndsAt),
+		})
+	}
+
 	if dbEvent.Location != osDoc.Location {
 		mismatches = append(mismatches, EventMismatch{
 			EventID: dbEvent.Id,
@@ -331,6 +375,15 @@ func (m *Manager) compareEvent(dbEvent *db.Event, osDoc *models.EventDocument) [
 		})
 	}
 
+	if formatID(dbEvent.VenueID) != formatID(osDoc.VenueID) {
+		mismatches = append(mismatches, EventMismatch{
+			EventID: dbEvent.Id,
+			Field:   "venue_id",
+			DBValue: formatID(dbEvent.VenueID),
+			OSValue: formatID(osDoc.VenueID),
+		})
+	}
+
 	if dbEvent.Price != osDoc.Price {
 		mismatches = append(mismatches, EventMismatch{
 			EventID: dbEvent.Id,
@@ -340,6 +393,33 @@ func (m *Manager) compareEvent(dbEvent *db.Event, osDoc *models.EventDocument) [
 		})
 	}
 
+	if formatPrice(dbEvent.PriceFrom) != formatPrice(osDoc.PriceFrom) || formatPrice(dbEvent.PriceTo) != formatPrice(osDoc.PriceTo) {
+		mismatches = append(mismatches, EventMismatch{
+			EventID: dbEvent.Id,
+			Field:   "price_range",
+			DBValue: formatPrice(dbEvent.PriceFrom) + "-" + formatPrice(dbEvent.PriceTo),
+			OSValue: formatPrice(osDoc.PriceFrom) + "-" + formatPrice(osDoc.PriceTo),
+		})
+	}
+
+	if dbEvent.Status != osDoc.Status {
+		mismatches = append(mismatches, EventMismatch{
+			EventID: dbEvent.Id,
+			Field:   "status",
+			DBValue: dbEvent.Status,
+			OSValue: osDoc.Status,
+		})
+	}
+
+	if dbEvent.Version != osDoc.Version {
+		mismatches = append(mismatches, EventMismatch{
+			EventID: dbEvent.Id,
+			Field:   "version",
+			DBValue: fmt.Sprintf("%d", dbEvent.Version),
+			OSValue: fmt.Sprintf("%d", osDoc.Version),
+		})
+	}
+
 	if dbEvent.Source != osDoc.Source {
 		mismatches = append(mismatches, EventMismatch{
 			EventID: dbEvent.Id,
@@ -352,6 +432,38 @@ func (m *Manager) compareEvent(dbEvent *db.Event, osDoc *models.EventDocument) [
 	return mismatches
 }
 
+// equalTimes сравнивает опциональные моменты времени без учета часового пояса
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// formatTime форматирует опциональный момент времени для отчета о несоответствиях
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatPrice форматирует опциональную цену для отчета о несоответствиях
+func formatPrice(price *float32) string {
+	if price == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", *price)
+}
+
+// formatID форматирует опциональный идентификатор для отчета о несоответствиях
+func formatID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", *id)
+}
+
 // getCachedResult возвращает закэшированный результат, если он еще актуален
 func (m *Manager) getCachedResult() *CheckResult {
 	m.mu.RLock()
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/internal/opensearch"
+	"github.com/rx3lixir/event-service/pkg/logger"
+)
+
+// DefaultPublishInterval период проверки запланированных публикаций по умолчанию
+const DefaultPublishInterval = time.Minute
+
+// Publisher периодически публикует запланированные события (status = scheduled),
+// момент публикации которых наступил, и переиндексирует их в OpenSearch
+type Publisher struct {
+	store     *db.PostgresStore
+	osService *opensearch.Service
+	log       logger.Logger
+	interval  time.Duration
+}
+
+// NewPublisher создает планировщик публикаций. Неположительный interval
+// заменяется на DefaultPublishInterval
+func NewPublisher(store *db.PostgresStore, osService *opensearch.Service, log logger.Logger, interval time.Duration) *Publisher {
+	if interval <= 0 {
+		interval = DefaultPublishInterval
+	}
+
+	return &Publisher{
+		store:     store,
+		osService: osService,
+		log:       log,
+		interval:  interval,
+	}
+}
+
+// Start запускает планировщик в фоне. Он останавливается при отмене ctx
+func (p *Publisher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		// Первая проверка сразу при старте, чтобы не ждать целый интервал после рестарта
+		p.PublishDue(ctx)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.PublishDue(ctx)
+			}
+		}
+	}()
+}
+
+// PublishDue публикует события, время публикации которых наступило.
+// Ошибки только логируются: следующая итерация повторит попытку
+func (p *Publisher) PublishDue(ctx context.Context) {
+	events, err := p.store.PublishDueEvents(ctx, time.Now())
+	if err != nil {
+		p.log.Error("failed to publish scheduled events", "error", err)
+		return
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	// В PostgreSQL события уже опубликованы. Если переиндексация не удалась,
+	// расхождение покажет проверка консистентности
+	if err := p.osService.BulkIndexEvents(ctx, events); err != nil {
+		p.log.Error("failed to reindex published events in OpenSearch",
+			"events_count", len(events),
+			"error", err,
+		)
+	}
+
+	p.log.Info("scheduled events published",
+		"events_count", len(events),
+	)
+}
@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/rx3lixir/event-service/internal/db"
+	"github.com/rx3lixir/event-service/pkg/logger"
+)
+
+const (
+	// DefaultPurgeInterval период запуска очистки по умолчанию
+	DefaultPurgeInterval = time.Hour
+	// DefaultPurgeRetention срок хранения мягко удаленных записей по умолчанию
+	DefaultPurgeRetention = 30 * 24 * time.Hour
+)
+
+// Purger периодически окончательно удаляет события и категории,
+// мягко удаленные раньше, чем retention назад, и такие же старые записи журнала изменений
+type Purger struct {
+	store     *db.PostgresStore
+	log       logger.Logger
+	interval  time.Duration
+	retention time.Duration
+}
+
+// NewPurger создает задачу очистки. Неположительные interval и retention
+// заменяются на DefaultPurgeInterval и DefaultPurgeRetention
+func NewPurger(store *db.PostgresStore, log logger.Logger, interval, retention time.Duration) *Purger {
+	if interval <= 0 {
+		interval = DefaultPurgeInterval
+	}
+	if retention <= 0 {
+		retention = DefaultPurgeRetention
+	}
+
+	return &Purger{
+		store:     store,
+		log:       log,
+		interval:  interval,
+		retention: retention,
+	}
+}
+
+// Start запускает очистку в фоне. Она останавливается при отмене ctx
+func (p *Purger) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		p.Purge(ctx)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.Purge(ctx)
+			}
+		}
+	}()
+}
+
+// Purge удаляет записи с истекшим сроком хранения. Сначала удаляются события,
+// чтобы освободить категории, на которые они ссылались.
+// Из OpenSearch удаленные события убраны еще при мягком удалении
+func (p *Purger) Purge(ctx context.Context) {
+	before := time.Now().Add(-p.retention)
+
+	events, err := p.store.PurgeDeletedEvents(ctx, before)
+	if err != nil {
+		p.log.Error("failed to purge deleted events", "error", err)
+		return
+	}
+
+	categories, err := p.store.PurgeDeletedCategories(ctx, before)
+	if err != nil {
+		p.log.Error("failed to purge deleted categories", "error", err)
+		return
+	}
+
+	changes, err := p.store.PurgeEventChanges(ctx, before)
+	if err != nil {
+		p.log.Error("failed to purge event changes", "error", err)
+		return
+	}
+
+	if events == 0 && categories == 0 && changes == 0 {
+		return
+	}
+
+	p.log.Info("deleted records purged",
+		"events_count", events,
+		"categories_count", categories,
+		"changes_count", changes,
+		"deleted_before", before,
+	)
+}